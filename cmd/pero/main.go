@@ -19,6 +19,7 @@ import (
 func main() {
 	app := fx.New(fx.Options(
 		fx.Provide(configs.NewConfig()),
+		fx.Provide(configs.NewChannelsConfig()),
 		fx.Options(lib.NewModules()),
 		fx.Options(models.NewModules()),
 		fx.Options(thirdparty.NewModules()),
@@ -2,8 +2,8 @@ package handlers
 
 import (
 	"github.com/nekomeowww/perobot/internal/bots/telegram/dispatcher"
-	"github.com/nekomeowww/perobot/internal/bots/telegram/handlers/pixiv2images"
-	"github.com/nekomeowww/perobot/internal/bots/telegram/handlers/tweet2images"
+	"github.com/nekomeowww/perobot/internal/bots/telegram/handlers/pipeline"
+	"github.com/nekomeowww/perobot/internal/bots/telegram/handlers/queue"
 	"github.com/nekomeowww/perobot/pkg/handler"
 	"go.uber.org/fx"
 )
@@ -11,17 +11,17 @@ import (
 func NewModules() fx.Option {
 	return fx.Options(
 		fx.Provide(NewHandlers()),
-		fx.Provide(tweet2images.NewHandler()),
-		fx.Provide(pixiv2images.NewHandler()),
+		fx.Provide(pipeline.NewHandler()),
+		fx.Provide(queue.NewHandler()),
 	)
 }
 
 type NewHandlersParam struct {
 	fx.In
 
-	Dispatcher          *dispatcher.Dispatcher
-	Tweet2ImagesHandler *tweet2images.Handler
-	Pixiv2ImagesHandler *pixiv2images.Handler
+	Dispatcher      *dispatcher.Dispatcher
+	PipelineHandler *pipeline.Handler
+	QueueHandler    *queue.Handler
 }
 
 type Handlers struct {
@@ -36,12 +36,11 @@ func NewHandlers() func(param NewHandlersParam) *Handlers {
 		return &Handlers{
 			Dispatcher: param.Dispatcher,
 			MessageHandlers: []handler.HandleFunc{
-				param.Tweet2ImagesHandler.HandleMessageAutomaticForwardedFromLinkedChannel,
-				param.Pixiv2ImagesHandler.HandleMessageAutomaticForwardedFromLinkedChannel,
+				param.PipelineHandler.HandleMessageAutomaticForwardedFromLinkedChannel,
+				param.QueueHandler.HandleMessageQueueCommands,
 			},
 			ChannelPostHandlers: []handler.HandleFunc{
-				param.Tweet2ImagesHandler.HandleChannelPostTweetToImages,
-				param.Pixiv2ImagesHandler.HandleChannelPostPixivToImages,
+				param.PipelineHandler.HandleChannelPost,
 			},
 		}
 	}
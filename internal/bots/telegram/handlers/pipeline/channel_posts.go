@@ -0,0 +1,534 @@
+package pipeline
+
+import (
+	"fmt"
+	"html"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/samber/lo"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+
+	"github.com/nekomeowww/elapsing"
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	"github.com/nekomeowww/perobot/internal/configs"
+	"github.com/nekomeowww/perobot/internal/models/filecache"
+	"github.com/nekomeowww/perobot/internal/models/imagehashes"
+	"github.com/nekomeowww/perobot/internal/models/published"
+	"github.com/nekomeowww/perobot/internal/thirdparty"
+	"github.com/nekomeowww/perobot/pkg/bots/telegram"
+	"github.com/nekomeowww/perobot/pkg/downloads"
+	"github.com/nekomeowww/perobot/pkg/handler"
+	"github.com/nekomeowww/perobot/pkg/logger"
+	"github.com/nekomeowww/perobot/pkg/spool"
+)
+
+type NewHandlerParam struct {
+	fx.In
+
+	Logger         *logger.Logger
+	Providers      publishing.Providers
+	FileCacheModel *filecache.Model
+	PublishedModel *published.Model
+	ImageHashes    *imagehashes.Model
+	ChannelsConfig *configs.ChannelsConfig
+	Uploader       *thirdparty.TelegramFileUploader
+	Spool          *spool.Store
+	Downloads      *downloads.Manager
+}
+
+// Handler 将各个来源的作品发布到频道，并在讨论群组中回复原图、原视频的统一流程
+type Handler struct {
+	Exchange sync.Map
+
+	Logger    *logger.Logger
+	Providers publishing.Providers
+	FileCache *filecache.Model
+	Published *published.Model
+
+	ImageHashes    *imagehashes.Model
+	ChannelsConfig *configs.ChannelsConfig
+
+	Uploader   *thirdparty.TelegramFileUploader
+	SizePolicy telegram.SizePolicy
+	Spool      *spool.Store
+	Downloads  *downloads.Manager
+}
+
+func NewHandler() func(param NewHandlerParam) *Handler {
+	return func(param NewHandlerParam) *Handler {
+		handler := &Handler{
+			Logger:         param.Logger,
+			Providers:      param.Providers,
+			FileCache:      param.FileCacheModel,
+			Published:      param.PublishedModel,
+			ImageHashes:    param.ImageHashes,
+			ChannelsConfig: param.ChannelsConfig,
+			Uploader:       param.Uploader,
+			SizePolicy:     param.Uploader.SizePolicy(),
+			Spool:          param.Spool,
+			Downloads:      param.Downloads,
+		}
+		return handler
+	}
+}
+
+func (h *Handler) HandleChannelPost(c *handler.Context) {
+	// 转发的消息不处理
+	if c.Update.ChannelPost.ForwardFrom != nil {
+		return
+	}
+	// 转发的消息不处理
+	if c.Update.ChannelPost.ForwardFromChat != nil {
+		return
+	}
+	// 不是 /t 或 /t! 命令的消息不处理
+	postCommand, ok := telegram.ParsePostCommand(c.Update.ChannelPost.Text)
+	if !ok {
+		return
+	}
+	if !h.Match(c.Update.ChannelPost.Chat.ID, postCommand.Argument) {
+		return
+	}
+
+	h.Publish(c.Bot, publishing.NewChannelPostRequest(c.Update.ChannelPost, postCommand.Argument, postCommand.Force))
+}
+
+// Match 判断 link 是否为频道 channelID 中可以发布的任意一个来源的作品链接
+func (h *Handler) Match(channelID int64, link string) bool {
+	provider, _ := h.Providers.Match(h.ChannelsConfig.Channel(channelID), link)
+	return provider != nil
+}
+
+// Publish 将来源作品中的媒体发布到频道
+func (h *Handler) Publish(bot *tgbotapi.BotAPI, request *publishing.Request) {
+	e := elapsing.New()
+
+	channelConfig := h.ChannelsConfig.Channel(request.Chat.ID)
+
+	provider, postID := h.Providers.Match(channelConfig, request.URL)
+	if provider == nil {
+		return
+	}
+	e.StepEnds(elapsing.WithName("Match Provider"))
+
+	source := provider.Source()
+	logEntry := h.Logger.WithFields(logrus.Fields{
+		"source":     source,
+		"source_id":  postID,
+		"url":        request.URL,
+		"chat_id":    request.Chat.ID,
+		"chat_title": request.Chat.Title,
+	})
+
+	// 已经发布过的作品回复已有消息的链接，/t! 命令强制重新发布
+	if !request.Force {
+		publishedEntry := h.Published.Get(request.Chat.ID, source, postID)
+		if publishedEntry != nil {
+			logEntry.Infof("%s post already published as message %d, skipping...", source, publishedEntry.MessageID)
+			h.replyAlreadyPublished(bot, request, publishedEntry, logEntry)
+
+			return
+		}
+	}
+
+	post, err := provider.FetchPost(postID)
+	if err != nil {
+		logEntry.Errorf("failed to fetch %s post, err: %v", source, err)
+		return
+	}
+	if post == nil {
+		logEntry.Warnf("%s post not found", source)
+		return
+	}
+	e.StepEnds(elapsing.WithName("Fetch Post"))
+
+	// 在下载媒体之前按照频道的过滤规则检查作品，被拒绝的作品不会产生任何下载
+	if postFilter, ok := provider.(publishing.PostFilter); ok {
+		rejectedReason := postFilter.FilterPost(channelConfig, post)
+		if rejectedReason != "" {
+			logEntry.WithField("reason", rejectedReason).Infof("%s post rejected by channel filters", source)
+			h.notice(bot, request, "这个作品没有通过频道的过滤规则："+rejectedReason, logEntry)
+
+			return
+		}
+	}
+
+	// AI 生成的作品按照频道配置附加标签或拒绝发布
+	labels := publishing.ApplyLabels(channelConfig, post.Metadata)
+	if labels.RefusedReason != "" {
+		logEntry.WithField("reason", labels.RefusedReason).Infof("%s post refused by channel labeling policy", source)
+		h.notice(bot, request, labels.RefusedReason, logEntry)
+
+		return
+	}
+
+	// 来源标记为敏感内容的作品按照频道配置加上剧透遮罩、内容警告或拒绝发布
+	sensitiveConfig := channelConfig.Sensitive
+	if post.Sensitive && sensitiveConfig.Action == configs.SensitiveActionRefuse {
+		logEntry.Infof("%s post is marked as sensitive, refused to publish", source)
+		h.notice(bot, request, "这个作品被来源标记为敏感内容，频道设置不允许发布", logEntry)
+
+		return
+	}
+
+	medias, err := provider.ListMedia(post)
+	if err != nil {
+		logEntry.Errorf("failed to list medias of %s post, err: %v", source, err)
+		return
+	}
+	if len(medias) == 0 {
+		logEntry.Warnf("no images/videos found in %s post", source)
+		return
+	}
+	e.StepEnds(elapsing.WithName("List Medias"))
+
+	caption := provider.RenderCaption(post, labels.Caption)
+	if post.Sensitive && sensitiveConfig.WarningText() != "" {
+		warning := html.EscapeString(sensitiveConfig.WarningText())
+		if post.SensitiveLabel != "" {
+			warning += fmt.Sprintf("（%s）", html.EscapeString(post.SensitiveLabel))
+		}
+
+		caption = warning + "\n\n" + caption
+	}
+
+	logEntry.Infof("%s post found, fetching %d images/videos...", source, len(medias))
+
+	fetchedMedias := h.fetchMedias(provider, medias, logEntry)
+
+	// 交给讨论群组之前的任何提前返回都需要释放已下载的媒体
+	handedOff := false
+	defer func() {
+		if !handedOff {
+			closeFetchedMedias(fetchedMedias)
+		}
+	}()
+
+	if len(fetchedMedias) == 0 {
+		logEntry.Warn("no images/videos fetched, probably because of rate limit")
+		return
+	}
+
+	logEntry.Infof("%d images/videos fetched, sending to telegram...", len(fetchedMedias))
+	e.StepEnds(elapsing.WithName("Fetch Medias"))
+
+	// 与近期发布的图片视觉上相同时，按照频道配置提示或跳过，/t! 命令强制发布，接近纯色的图片不参与比较
+	previewHashes := lo.FilterMap(fetchedMedias, func(item *fetchedMedia, _ int) (uint64, bool) { return item.Hash, item.HasHash })
+
+	similarRecord, skip := h.checkSimilarPublished(bot, request, previewHashes, logEntry)
+	if skip {
+		return
+	}
+	e.StepEnds(elapsing.WithName("Find Similar Published Images"))
+
+	files := h.Uploader.NewBatch()
+	defer files.Cleanup()
+
+	// 频道中的第一条消息发送后立即交给讨论群组，之后的相册和动画仍在发送时讨论群组就可以开始回复原图，
+	// 讨论群组在 sending 结束之前不会释放已下载的文件
+	exchanged := &exchange{
+		Source: source,
+		PostID: postID,
+		Medias: fetchedMedias,
+	}
+	exchanged.sending.Add(1)
+	defer exchanged.sending.Done()
+
+	assignExchangeOnce := func(sent []tgbotapi.Message) {
+		if handedOff || len(sent) == 0 {
+			return
+		}
+
+		h.assignExchange(sent[0].Chat.ID, sent[0].MessageID, exchanged)
+		handedOff = true
+	}
+
+	// 动画无法与照片和视频放在同一个相册中，需要在相册之后单独发送
+	spoiler := post.Sensitive && sensitiveConfig.Action == configs.SensitiveActionSpoiler
+	albumMedias := lo.Filter(fetchedMedias, func(item *fetchedMedia, _ int) bool {
+		return item.Type != publishing.MediaTypeAnimation
+	})
+	animationMedias := lo.Filter(fetchedMedias, func(item *fetchedMedia, _ int) bool {
+		return item.Type == publishing.MediaTypeAnimation
+	})
+
+	// 相册中途发送失败时已经发送的消息仍然需要记录，以免再次发布时重复
+	messages, albumErr := h.sendAlbum(bot, files, request.Chat.ID, albumMedias, caption, spoiler, assignExchangeOnce, logEntry)
+	e.StepEnds(elapsing.WithName("Send MediaGroup"))
+
+	// 只有动画的作品由第一个动画带上说明文字，否则动画回复相册的第一条消息
+	animationCaption := caption
+	replyToMessageID := 0
+	if len(messages) > 0 {
+		animationCaption = ""
+		replyToMessageID = messages[0].MessageID
+	}
+
+	// 只有动画的作品在第一个动画发送后交给讨论群组，不需要等待其余的动画上传
+	animationMessages := h.sendAnimations(bot, files, request.Chat.ID, animationMedias, animationCaption, replyToMessageID, spoiler, assignExchangeOnce, logEntry)
+	messages = append(messages, animationMessages...)
+	if len(messages) == 0 {
+		logEntry.Warn("no images/videos sent to channel")
+		return
+	}
+
+	e.StepEnds(elapsing.WithName("Send Animations"))
+
+	h.Published.Set(messages[0].Chat.ID, source, postID, messages[0].MessageID)
+	h.recordPublishedHashes(messages[0].Chat.ID, messages[0].MessageID, source, postID, previewHashes)
+	if similarRecord != nil {
+		text := withPostLink("这个作品与近期发布过的作品看起来相同", request.Chat, similarRecord.MessageID)
+		h.reply(bot, request.Chat.ID, messages[0].MessageID, text, logEntry)
+	}
+	if albumErr != nil {
+		logEntry.Errorf("album partially sent, %d of %d images/videos sent, err: %v", len(messages)-len(animationMessages), len(albumMedias), albumErr)
+		h.notice(bot, request, "相册只发送了一部分，可以删除已发送的消息后使用 /t! 命令重新发布", logEntry)
+
+		return
+	}
+
+	logEntry.Infof("%d images/videos sent to channel", len(fetchedMedias))
+
+	// 删除发布命令，定时发布时没有需要删除的发布命令
+	if request.CommandMessageID != 0 {
+		_, err = bot.Request(tgbotapi.NewDeleteMessage(request.Chat.ID, request.CommandMessageID))
+		if err != nil {
+			h.Logger.Error(err)
+			return
+		}
+
+		e.StepEnds(elapsing.WithName("Delete Original Message"))
+	}
+	go h.Logger.Debugf("%s post to media done, time cost:\n%s", source, e.Stats())
+}
+
+// sendAlbum 以相册的形式发送照片和视频，第一条消息带有 caption，每个相册发送成功后调用 onSent，
+// 中途发送失败时返回已经发送的消息和错误
+func (h *Handler) sendAlbum(
+	bot *tgbotapi.BotAPI,
+	files *telegram.FileBatch,
+	chatID int64,
+	medias []*fetchedMedia,
+	caption string,
+	spoiler bool,
+	onSent func(sent []tgbotapi.Message),
+	logEntry *logrus.Entry,
+) ([]tgbotapi.Message, error) {
+	mediaGroupBuilder := telegram.NewMediaGroupBuilder(chatID).
+		Caption(caption, "HTML").
+		Spoiler(spoiler).
+		OnSent(onSent)
+	addedMedias := make([]*fetchedMedia, 0, len(medias))
+	for _, media := range medias {
+		file, err := h.previewFile(files, media)
+		if err != nil {
+			logEntry.Error(err)
+			continue
+		}
+
+		addedMedias = append(addedMedias, media)
+
+		switch media.Type {
+		case publishing.MediaTypePhoto:
+			if media.AsDocument {
+				mediaGroupBuilder.Add(tgbotapi.NewInputMediaDocument(file))
+				h.Logger.Debugf("created a new input media document with name: %s", media.FileName)
+				continue
+			}
+
+			mediaGroupBuilder.Add(tgbotapi.NewInputMediaPhoto(file))
+			h.Logger.Debugf("created a new input media photo with name: %s", media.FileName)
+		case publishing.MediaTypeVideo:
+			inputMediaVideo := tgbotapi.NewInputMediaVideo(file)
+			inputMediaVideo.Height = media.Height
+			inputMediaVideo.Width = media.Width
+			inputMediaVideo.Duration = media.Duration
+			inputMediaVideo.SupportsStreaming = true
+			if media.FileID == "" && media.Thumbnail != nil {
+				inputMediaVideo.Thumb = tgbotapi.FileBytes{Name: "thumbnail-" + media.FileName, Bytes: media.Thumbnail}
+			}
+
+			mediaGroupBuilder.Add(inputMediaVideo)
+			h.Logger.Debugf("created a new input media video with name: %s", media.FileName)
+		}
+	}
+	if mediaGroupBuilder.Len() == 0 {
+		return make([]tgbotapi.Message, 0), nil
+	}
+
+	messages, err := mediaGroupBuilder.Send(bot)
+
+	for sentIndex, addedIndex := range mediaGroupBuilder.SentOrder() {
+		media := addedMedias[addedIndex]
+		if sentIndex < len(messages) {
+			if media.FileID == "" {
+				h.FileCache.SetPreviewFromMessage(media.CacheKey, messages[sentIndex], media.Hash, media.HasHash)
+			}
+
+			continue
+		}
+		// 未发送的媒体所缓存的 file_id 可能已经失效，清除后下次会重新下载和上传
+		if err != nil && media.FileID != "" {
+			h.FileCache.Invalidate(media.CacheKey, filecache.VariantPreview)
+		}
+	}
+
+	return messages, err
+}
+
+// sendAnimations 依次以动画的形式发送媒体，第一个动画带有 caption 并回复 replyToMessageID，
+// 之后的动画回复第一个动画，每个动画发送成功后调用 onSent，发送失败的动画会被跳过
+func (h *Handler) sendAnimations(
+	bot *tgbotapi.BotAPI,
+	files *telegram.FileBatch,
+	chatID int64,
+	medias []*fetchedMedia,
+	caption string,
+	replyToMessageID int,
+	spoiler bool,
+	onSent func(sent []tgbotapi.Message),
+	logEntry *logrus.Entry,
+) []tgbotapi.Message {
+	messages := make([]tgbotapi.Message, 0, len(medias))
+
+	for _, media := range medias {
+		file, err := h.previewFile(files, media)
+		if err != nil {
+			logEntry.Error(err)
+			continue
+		}
+
+		animationConfig := tgbotapi.NewAnimation(chatID, file)
+		animationConfig.ReplyToMessageID = replyToMessageID
+		animationConfig.Duration = media.Duration
+		if media.FileID == "" && media.Thumbnail != nil {
+			animationConfig.Thumb = tgbotapi.FileBytes{Name: "thumbnail-" + media.FileName, Bytes: media.Thumbnail}
+		}
+		if len(messages) == 0 {
+			animationConfig.Caption = caption
+			animationConfig.ParseMode = "HTML"
+		}
+
+		message, err := telegram.SendAnimation(bot, animationConfig, spoiler)
+		if err != nil {
+			logEntry.WithField("file_name", media.FileName).Errorf("failed to send animation, err: %v", err)
+
+			// 缓存的 file_id 可能已经失效，清除后下次会重新下载和上传
+			if media.FileID != "" {
+				h.FileCache.Invalidate(media.CacheKey, filecache.VariantPreview)
+			}
+
+			continue
+		}
+		if media.FileID == "" {
+			h.FileCache.SetPreviewFromMessage(media.CacheKey, message, media.Hash, media.HasHash)
+		}
+		if len(messages) == 0 && replyToMessageID == 0 {
+			replyToMessageID = message.MessageID
+		}
+
+		h.Logger.Debugf("sent a new animation with name: %s", media.FileName)
+		messages = append(messages, message)
+		onSent([]tgbotapi.Message{message})
+	}
+
+	return messages
+}
+
+// replyAlreadyPublished 告知作品已经发布过并附上已有消息的链接
+func (h *Handler) replyAlreadyPublished(bot *tgbotapi.BotAPI, request *publishing.Request, entry *published.Entry, logEntry *logrus.Entry) {
+	text := withPostLink("这个作品已经发布过了", request.Chat, entry.MessageID)
+	text += "\n\n如需再次发布，请使用 <code>/t!</code> 命令"
+
+	h.notice(bot, request, text, logEntry)
+}
+
+// notice 发送请求的提示消息
+func (h *Handler) notice(bot *tgbotapi.BotAPI, request *publishing.Request, text string, logEntry *logrus.Entry) {
+	h.reply(bot, request.NoticeChatID, request.NoticeReplyToMessageID, text, logEntry)
+}
+
+// reply 在会话 chatID 中回复消息 replyToMessageID
+func (h *Handler) reply(bot *tgbotapi.BotAPI, chatID int64, replyToMessageID int, text string, logEntry *logrus.Entry) {
+	message := tgbotapi.NewMessage(chatID, text)
+	message.ParseMode = "HTML"
+	message.ReplyToMessageID = replyToMessageID
+	message.DisableWebPagePreview = true
+
+	_, err := bot.Send(message)
+	if err != nil {
+		logEntry.Errorf("failed to send reply, err: %v", err)
+	}
+}
+
+// withPostLink 在 text 后附上频道中消息 messageID 的链接
+func withPostLink(text string, chat *tgbotapi.Chat, messageID int) string {
+	link := telegram.MessageLink(chat, messageID)
+	if link == "" {
+		return text
+	}
+
+	return text + fmt.Sprintf(`：<a href="%s">查看</a>`, link)
+}
+
+// checkSimilarPublished 查找与 hashes 视觉上相同的近期发布的图片，返回找到的图片，以及按照频道配置是否应当跳过发布，
+// 跳过时会发送提示消息，/t! 命令强制发布时不检查
+func (h *Handler) checkSimilarPublished(bot *tgbotapi.BotAPI, request *publishing.Request, hashes []uint64, logEntry *logrus.Entry) (*imagehashes.Record, bool) {
+	if request.Force {
+		return nil, false
+	}
+
+	similarRecord := h.findSimilarPublished(request.Chat.ID, hashes)
+	if similarRecord == nil {
+		return nil, false
+	}
+
+	logEntry.WithField("similar_message_id", similarRecord.MessageID).Warnf("visually identical to %s %s published recently", similarRecord.Source, similarRecord.SourceID)
+
+	if h.ChannelsConfig.Channel(request.Chat.ID).Duplicates.Action == configs.DuplicateActionSkip {
+		text := withPostLink("这个作品与近期发布过的作品看起来相同", request.Chat, similarRecord.MessageID)
+		text += "\n\n如需发布，请使用 <code>/t!</code> 命令"
+		h.notice(bot, request, text, logEntry)
+
+		return similarRecord, true
+	}
+
+	return similarRecord, false
+}
+
+// findSimilarPublished 在频道近期发布的图片中查找与 hashes 视觉上相同的图片
+func (h *Handler) findSimilarPublished(chatID int64, hashes []uint64) *imagehashes.Record {
+	duplicatesConfig := h.ChannelsConfig.Channel(chatID).Duplicates
+	if duplicatesConfig.Action == configs.DuplicateActionOff || len(hashes) == 0 {
+		return nil
+	}
+
+	return h.ImageHashes.FindSimilar(chatID, hashes, *duplicatesConfig.Threshold, duplicatesConfig.Window)
+}
+
+// recordPublishedHashes 记录频道中新发布的图片的感知哈希
+func (h *Handler) recordPublishedHashes(chatID int64, messageID int, source published.Source, sourceID string, hashes []uint64) {
+	records := lo.Map(hashes, func(hash uint64, _ int) imagehashes.Record {
+		return imagehashes.Record{
+			Hash:        hash,
+			Source:      source,
+			SourceID:    sourceID,
+			MessageID:   messageID,
+			PublishedAt: time.Now(),
+		}
+	})
+
+	h.ImageHashes.Add(chatID, records, h.ChannelsConfig.Channel(chatID).Duplicates.Window)
+}
+
+// previewFile 返回频道中预览所使用的文件，已缓存 file_id 时直接使用 file_id
+func (h *Handler) previewFile(files *telegram.FileBatch, media *fetchedMedia) (tgbotapi.RequestFileData, error) {
+	if media.FileID != "" {
+		return tgbotapi.FileID(media.FileID), nil
+	}
+
+	return files.Spooled(media.FileName, media.Body)
+}
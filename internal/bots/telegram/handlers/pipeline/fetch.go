@@ -0,0 +1,297 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/samber/lo"
+	"github.com/sirupsen/logrus"
+	"github.com/sourcegraph/conc/iter"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	"github.com/nekomeowww/perobot/internal/models/filecache"
+	"github.com/nekomeowww/perobot/pkg/bots/telegram"
+	"github.com/nekomeowww/perobot/pkg/imagehash"
+	"github.com/nekomeowww/perobot/pkg/spool"
+)
+
+// fetchedMedia 已下载或已缓存 file_id 的媒体
+type fetchedMedia struct {
+	*publishing.Media
+
+	// Body 频道中的预览，已缓存 file_id 时为 nil
+	Body *spool.File
+	// OriginalBody 讨论群组中的原图、原视频，已缓存 file_id 或来源没有提供时为 nil
+	OriginalBody *spool.File
+	// AsDocument 预览无法满足 Telegram 对照片的限制，需要以文件的形式发送
+	AsDocument bool
+	// FileID 已缓存的预览 file_id，不为空时 Body 为 nil
+	FileID string
+	// OriginalFileID 已缓存的原图、原视频 file_id，不为空时 OriginalBody 为 nil
+	OriginalFileID string
+	// Hash 预览的感知哈希，只有 HasHash 为 true 时有效
+	Hash uint64
+	// HasHash 预览有可以用于查找重复图片的感知哈希，视频、无法解码或接近纯色的预览没有，使用已缓存的 file_id 时来自缓存
+	HasHash bool
+	// Thumbnail 由封面图生成的视频和动画的缩略图，使用已缓存 file_id 或没有封面图时为 nil
+	Thumbnail []byte
+}
+
+// closeFetchedMedias 释放已下载的预览和原图、原视频
+func closeFetchedMedias(medias []*fetchedMedia) {
+	for _, media := range medias {
+		if media == nil {
+			continue
+		}
+
+		_ = media.Body.Close()
+		_ = media.OriginalBody.Close()
+	}
+}
+
+// fetchMedias 并行下载作品中的媒体，无法下载的媒体改为下载其 Fallback，仍然失败的媒体会被跳过
+func (h *Handler) fetchMedias(provider publishing.Provider, medias []*publishing.Media, logEntry *logrus.Entry) []*fetchedMedia {
+	fetchedMediasByMedia := iter.Map(medias, func(media **publishing.Media) []*fetchedMedia {
+		fetched := h.fetchMedia(provider, *media, logEntry)
+		if fetched != nil {
+			return []*fetchedMedia{fetched}
+		}
+		if len((*media).Fallback) == 0 {
+			logEntry.Warnf("failed to fetch %s: %s", (*media).Type, (*media).FileName)
+			return nil
+		}
+
+		logEntry.Warnf("failed to fetch %s: %s, falling back to %d medias", (*media).Type, (*media).FileName, len((*media).Fallback))
+		return h.fetchMedias(provider, (*media).Fallback, logEntry)
+	})
+
+	return lo.Flatten(fetchedMediasByMedia)
+}
+
+// fetchMedia 下载媒体的预览和原图、原视频，已经上传过的版本无需重新下载，预览无法下载时返回 nil
+func (h *Handler) fetchMedia(provider publishing.Provider, media *publishing.Media, logEntry *logrus.Entry) *fetchedMedia {
+	fetched := &fetchedMedia{Media: media}
+
+	download := provider.Download
+	if media.Download != nil {
+		download = media.Download
+	}
+
+	cachedPreview := h.FileCache.Get(media.CacheKey, filecache.VariantPreview)
+	if cachedPreview != nil {
+		fetched.FileID = cachedPreview.FileID
+		fetched.AsDocument = cachedPreview.Type == telegram.MediaTypeDocument
+		fetched.Hash, fetched.HasHash = cachedPreview.Hash, cachedPreview.HasHash
+	}
+
+	cachedOriginal := h.FileCache.Get(media.CacheKey, filecache.VariantOriginal)
+	if cachedOriginal != nil {
+		fetched.OriginalFileID = cachedOriginal.FileID
+	}
+
+	needsPreview := cachedPreview == nil
+	needsOriginal := cachedOriginal == nil && media.OriginalURL != ""
+
+	// 视频和动画无法缩小，超出上传限制时无法使用，下载超出限制时立即中止；照片超出限制时仍可以缩小后作为预览，
+	// 需要转换的预览的大小与下载的文件无关，同样不限制
+	var originalLimit, previewLimit int64
+	if media.Type != publishing.MediaTypePhoto {
+		originalLimit = h.SizePolicy.MaxUploadSize
+		previewLimit = lo.Ternary(media.ConvertPreview != nil, 0, h.SizePolicy.MaxUploadSize)
+	}
+	if lo.Contains(media.PreviewURLs, media.OriginalURL) {
+		originalLimit = previewLimit
+	}
+
+	// 原图、原视频与预览并行下载，预览的候选链接与原图相同时等待原图下载完成后直接使用
+	var original *spool.File
+	originalDone := make(chan struct{})
+	if needsOriginal || (needsPreview && lo.Contains(media.PreviewURLs, media.OriginalURL)) {
+		go func() {
+			defer close(originalDone)
+
+			var err error
+			original, err = h.download(download, media.OriginalURL, originalLimit)
+			if errors.Is(err, spool.ErrTooLarge) {
+				logEntry.WithField("media_url", media.OriginalURL).Warnf("original %s exceeds upload limit of %d bytes, download aborted", media.Type, originalLimit)
+			} else if err != nil {
+				logEntry.WithField("media_url", media.OriginalURL).Errorf("failed to fetch original %s, err: %v", media.Type, err)
+			}
+		}()
+	} else {
+		close(originalDone)
+	}
+
+	var previewSource *spool.File
+	if needsPreview {
+		for _, previewURL := range media.PreviewURLs {
+			var downloaded *spool.File
+			if previewURL == media.OriginalURL {
+				<-originalDone
+				downloaded = original
+			} else {
+				var err error
+				downloaded, err = h.download(download, previewURL, previewLimit)
+				if errors.Is(err, spool.ErrTooLarge) {
+					logEntry.WithField("media_url", previewURL).Warnf("%s exceeds upload limit of %d bytes, download aborted", media.Type, previewLimit)
+				} else if err != nil {
+					logEntry.WithField("media_url", previewURL).Errorf("failed to fetch %s, err: %v", media.Type, err)
+				}
+			}
+			if downloaded == nil {
+				continue
+			}
+
+			err := h.preparePreview(fetched, downloaded)
+			if err != nil {
+				logEntry.WithField("media_url", previewURL).Warnf("%s is not usable as preview, err: %v", media.Type, err)
+				if downloaded != original {
+					_ = downloaded.Close()
+				}
+
+				continue
+			}
+
+			previewSource = downloaded
+			break
+		}
+	}
+
+	<-originalDone
+
+	if needsPreview && fetched.Body == nil {
+		_ = original.Close()
+		return nil
+	}
+
+	// 原图、原视频无法下载或超出上传限制时，以下载的预览代替
+	if needsOriginal {
+		if original != nil && h.SizePolicy.FitsUpload(original.Size()) {
+			fetched.OriginalBody = original
+		} else {
+			if original != nil {
+				logEntry.WithField("media_url", media.OriginalURL).Warnf("original %s is too large (%d bytes), falling back to preview", media.Type, original.Size())
+			}
+			if previewSource != nil && h.SizePolicy.FitsUpload(previewSource.Size()) {
+				fetched.OriginalBody = previewSource
+			}
+		}
+	}
+
+	for _, file := range []*spool.File{original, previewSource} {
+		if file != fetched.Body && file != fetched.OriginalBody {
+			_ = file.Close()
+		}
+	}
+
+	if media.Type != publishing.MediaTypePhoto && media.PosterURL != "" && (fetched.Body != nil || fetched.OriginalBody != nil) {
+		fetched.Thumbnail = h.fetchThumbnail(download, media.PosterURL, logEntry)
+	}
+
+	return fetched
+}
+
+// preparePreview 使下载的预览满足 Telegram 的限制，照片会在需要时缩小，视频和动画超出上传限制时返回错误
+func (h *Handler) preparePreview(fetched *fetchedMedia, downloaded *spool.File) error {
+	if fetched.ConvertPreview != nil {
+		converted, hashImage, err := fetched.ConvertPreview(downloaded)
+		if err != nil {
+			return err
+		}
+		if !h.SizePolicy.FitsUpload(converted.Size()) {
+			_ = converted.Close()
+			return fmt.Errorf("converted preview is too large (%d bytes)", converted.Size())
+		}
+
+		fetched.Body = converted
+		if hashImage != nil {
+			fetched.Hash, fetched.HasHash = imagehash.DHashWithDetail(hashImage)
+		}
+
+		return nil
+	}
+
+	if fetched.Type != publishing.MediaTypePhoto {
+		if !h.SizePolicy.FitsUpload(downloaded.Size()) {
+			return fmt.Errorf("preview is too large (%d bytes)", downloaded.Size())
+		}
+
+		fetched.Body = downloaded
+
+		return nil
+	}
+
+	data, err := downloaded.Bytes()
+	if err != nil {
+		return err
+	}
+
+	preparedPhoto, err := h.SizePolicy.PreparePhoto(data)
+	if err != nil {
+		return err
+	}
+	if preparedPhoto.Resized {
+		h.Logger.Infof("%s exceeds telegram photo limits, resized from %d bytes to %d bytes", fetched.FileName, downloaded.Size(), len(preparedPhoto.Bytes))
+	}
+
+	fetched.Body = spool.FromBytes(preparedPhoto.Bytes)
+	fetched.AsDocument = preparedPhoto.AsDocument
+	if preparedPhoto.Image != nil {
+		fetched.Hash, fetched.HasHash = imagehash.DHashWithDetail(preparedPhoto.Image)
+	}
+
+	return nil
+}
+
+// fetchThumbnail 下载视频和动画的封面图并生成缩略图，失败时返回 nil
+func (h *Handler) fetchThumbnail(download func(link string, w io.Writer) error, posterURL string, logEntry *logrus.Entry) []byte {
+	poster, err := h.download(download, posterURL, 0)
+	if err != nil {
+		logEntry.WithField("media_url", posterURL).Warnf("failed to fetch poster, err: %v", err)
+		return nil
+	}
+
+	defer poster.Close()
+
+	posterData, err := poster.Bytes()
+	if err != nil {
+		logEntry.WithField("media_url", posterURL).Warnf("failed to read poster, err: %v", err)
+		return nil
+	}
+
+	thumbnail, err := telegram.Thumbnail(posterData)
+	if err != nil {
+		logEntry.WithField("media_url", posterURL).Warnf("failed to generate thumbnail, err: %v", err)
+		return nil
+	}
+
+	return thumbnail
+}
+
+// download 通过共享的下载管理器调用 fn 将来源中的媒体下载为暂存文件，limit 大于 0 时超过 limit 字节的下载会被中止并返回 spool.ErrTooLarge
+func (h *Handler) download(fn func(link string, w io.Writer) error, link string, limit int64) (*spool.File, error) {
+	var release func()
+
+	file, err := h.Spool.DownloadLimited(limit, func(w io.Writer) error {
+		var err error
+
+		release, err = h.Downloads.Download(link, w, func(w io.Writer) error {
+			return fn(link, w)
+		})
+
+		return err
+	})
+	if err != nil {
+		if release != nil {
+			release()
+		}
+
+		return nil, err
+	}
+
+	// 下载的数据在发送完毕、关闭之前仍然占用下载的字节数上限
+	file.OnClose(release)
+
+	return file, nil
+}
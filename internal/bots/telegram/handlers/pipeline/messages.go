@@ -0,0 +1,290 @@
+package pipeline
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/sirupsen/logrus"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	"github.com/nekomeowww/perobot/internal/models/filecache"
+	"github.com/nekomeowww/perobot/internal/models/published"
+	"github.com/nekomeowww/perobot/pkg/bots/telegram"
+	"github.com/nekomeowww/perobot/pkg/handler"
+)
+
+const (
+	// exchangeTTL 交给讨论群组的文件最长保留的时间
+	exchangeTTL = 10 * time.Minute
+	// exchangeWaitTimeout 收到自动转发的消息后等待频道中的发布交出数据的最长时间
+	exchangeWaitTimeout = time.Minute
+)
+
+// exchange 频道中发布的作品交给讨论群组回复原图、原视频所需的数据
+type exchange struct {
+	Source published.Source
+	PostID string
+	Medias []*fetchedMedia
+
+	// sending 频道中的发布仍在使用 Medias 发送之后的相册和动画，释放文件之前需要等待
+	sending sync.WaitGroup
+}
+
+// exchangeSlot 一条频道消息所对应的交接位置，发布和自动转发的消息哪一方先到达都会创建
+type exchangeSlot struct {
+	ready chan struct{}
+	value *exchange
+	taken atomic.Bool
+}
+
+func exchangeKey(chatID int64, messageID int) string {
+	return fmt.Sprintf("key/exchange/%d/%d", chatID, messageID)
+}
+
+func (h *Handler) exchangeSlot(key string) *exchangeSlot {
+	slot, _ := h.Exchange.LoadOrStore(key, &exchangeSlot{ready: make(chan struct{})})
+	return slot.(*exchangeSlot)
+}
+
+// assignExchange 保存交给讨论群组的数据，频道没有关联讨论群组时不会收到自动转发的消息，超时后释放已下载的文件
+func (h *Handler) assignExchange(chatID int64, messageID int, value *exchange) {
+	key := exchangeKey(chatID, messageID)
+
+	slot := h.exchangeSlot(key)
+	slot.value = value
+	close(slot.ready)
+
+	time.AfterFunc(exchangeTTL, func() {
+		h.releaseExchange(key, slot)
+	})
+}
+
+// takeExchange 等待并取出交给讨论群组的数据，同一条消息只有第一次调用能够取出，用于去重，
+// 超过 timeout 仍未交出时返回 false
+func (h *Handler) takeExchange(chatID int64, messageID int, timeout time.Duration) (*exchange, bool) {
+	key := exchangeKey(chatID, messageID)
+	slot := h.exchangeSlot(key)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-slot.ready:
+	case <-timer.C:
+		// 不是由发布流程发送的频道消息，或者发布流程在交出数据之前失败
+		h.Exchange.CompareAndDelete(key, slot)
+		return nil, false
+	}
+
+	if !slot.taken.CompareAndSwap(false, true) {
+		return nil, false
+	}
+
+	h.Exchange.CompareAndDelete(key, slot)
+
+	return slot.value, true
+}
+
+// releaseExchange 在讨论群组没有取走数据时释放已下载的文件
+func (h *Handler) releaseExchange(key string, slot *exchangeSlot) {
+	h.Exchange.CompareAndDelete(key, slot)
+	if !slot.taken.CompareAndSwap(false, true) {
+		return
+	}
+
+	slot.value.sending.Wait()
+	closeFetchedMedias(slot.value.Medias)
+}
+
+func (h *Handler) HandleMessageAutomaticForwardedFromLinkedChannel(c *handler.Context) {
+	// 非自动转发的消息不处理
+	if !c.Update.Message.IsAutomaticForward {
+		return
+	}
+	// 不是转发的消息不处理
+	if c.Update.Message.ForwardFromChat == nil {
+		return
+	}
+
+	// 自动转发的消息可能早于发布流程拿到频道消息的 message id，等待发布流程交出数据
+	taken, ok := h.takeExchange(c.Update.Message.ForwardFromChat.ID, c.Update.Message.ForwardFromMessageID, exchangeWaitTimeout)
+	if !ok {
+		return
+	}
+
+	// 处理完毕，并且频道中的发布也不再使用之后释放已下载的文件
+	defer func() {
+		taken.sending.Wait()
+		closeFetchedMedias(taken.Medias)
+	}()
+
+	loggerFields := logrus.Fields{
+		"chat_id":                 c.Update.Message.Chat.ID,
+		"chat_title":              c.Update.Message.Chat.Title,
+		"forward_from_chat_id":    c.Update.Message.ForwardFromChat.ID,
+		"forward_from_chat_title": c.Update.Message.ForwardFromChat.Title,
+		"forward_from_message_id": c.Update.Message.ForwardFromMessageID,
+		"source":                  taken.Source,
+		"source_id":               taken.PostID,
+	}
+
+	botChatMemberInOriginalChannel, err := c.Bot.GetChatMember(tgbotapi.GetChatMemberConfig{
+		ChatConfigWithUser: tgbotapi.ChatConfigWithUser{
+			ChatID: c.Update.Message.ForwardFromChat.ID,
+			UserID: c.Bot.Self.ID,
+		},
+	})
+	if err != nil {
+		h.Logger.WithFields(loggerFields).Error(err)
+		return
+	}
+
+	if botChatMemberInOriginalChannel.Status != "administrator" {
+		h.Logger.WithFields(loggerFields).Warn("" +
+			"received a message from a channel that the bot is not " +
+			"an administrator in, ignoring...")
+		return
+	}
+
+	h.Logger.Info("" +
+		"linked channel message received, processing... prepare to " +
+		"send originals to discussion group")
+	botChatMemberInDiscussionGroup, err := c.Bot.GetChatMember(tgbotapi.GetChatMemberConfig{
+		ChatConfigWithUser: tgbotapi.ChatConfigWithUser{
+			ChatID: c.Update.Message.Chat.ID,
+			UserID: c.Bot.Self.ID,
+		},
+	})
+	if err != nil {
+		h.Logger.WithFields(loggerFields).Error(err)
+		return
+	}
+	if botChatMemberInDiscussionGroup.Status != "administrator" &&
+		!botChatMemberInDiscussionGroup.CanSendMediaMessages {
+		h.Logger.WithFields(loggerFields).Error("" +
+			"bot is not an administrator in the discussion group or " +
+			"does not have the permission to send messages or media " +
+			"messages")
+		return
+	}
+
+	h.Logger.Info("generating thumbnails...")
+	thumbnailImages := make([][]byte, len(taken.Medias))
+	for i, media := range taken.Medias {
+		// 已缓存的原图会以 file_id 发送，之前上传时已经带有缩略图
+		if media.OriginalFileID != "" || media.OriginalBody == nil {
+			continue
+		}
+		// 视频和动画使用封面图生成的缩略图
+		if media.Type != publishing.MediaTypePhoto {
+			thumbnailImages[i] = media.Thumbnail
+			continue
+		}
+
+		thumbnailImages[i] = h.photoThumbnail(media, loggerFields)
+	}
+
+	h.Logger.Info("sending originals to discussion group...")
+	files := h.Uploader.NewBatch()
+	defer files.Cleanup()
+
+	mediaGroupBuilder := telegram.NewMediaGroupBuilder(c.Update.Message.Chat.ID).ReplyTo(c.Update.Message.MessageID)
+	addedMedias := make([]*fetchedMedia, 0, len(taken.Medias))
+
+	for i, media := range taken.Medias {
+		if media.OriginalFileID != "" {
+			inputMediaDocument := tgbotapi.NewInputMediaDocument(tgbotapi.FileID(media.OriginalFileID))
+			inputMediaDocument.Caption = telegram.TruncateText(media.Description, telegram.MaxCaptionLength)
+
+			mediaGroupBuilder.Add(inputMediaDocument)
+			addedMedias = append(addedMedias, media)
+			h.Logger.Debugf("created a new input media document with cached file id: %s", media.OriginalFileID)
+
+			continue
+		}
+		if media.OriginalBody == nil {
+			continue
+		}
+
+		file, err := files.Spooled(media.OriginalFileName, media.OriginalBody)
+		if err != nil {
+			h.Logger.WithFields(loggerFields).Error(err)
+			continue
+		}
+
+		inputMediaDocument := tgbotapi.NewInputMediaDocument(file)
+		inputMediaDocument.Caption = telegram.TruncateText(media.Description, telegram.MaxCaptionLength)
+		h.Logger.Debugf(""+
+			"created a new input media document with name: %s, "+
+			"and size: %d", media.OriginalFileName, media.OriginalBody.Size())
+
+		if thumbnailImages[i] != nil {
+			thumbFile := tgbotapi.FileBytes{
+				Name:  "thumbnail-" + media.OriginalFileName,
+				Bytes: thumbnailImages[i],
+			}
+
+			inputMediaDocument.Thumb = thumbFile
+			h.Logger.Debugf(""+
+				"created a new input media document thumbnail with "+
+				"name: %s, and size: %d", thumbFile.Name, len(thumbFile.Bytes))
+		}
+
+		mediaGroupBuilder.Add(inputMediaDocument)
+		addedMedias = append(addedMedias, media)
+	}
+	if mediaGroupBuilder.Len() == 0 {
+		return
+	}
+
+	// 中途发送失败时已经发送的原图仍然缓存 file_id
+	messages, err := mediaGroupBuilder.Send(c.Bot)
+
+	for sentIndex, addedIndex := range mediaGroupBuilder.SentOrder() {
+		media := addedMedias[addedIndex]
+		if sentIndex < len(messages) {
+			if media.OriginalFileID == "" {
+				h.FileCache.SetFromMessage(media.CacheKey, filecache.VariantOriginal, messages[sentIndex])
+			}
+
+			continue
+		}
+		// 未发送的原图所缓存的 file_id 可能已经失效，清除后下次会重新下载和上传
+		if err != nil && media.OriginalFileID != "" {
+			h.FileCache.Invalidate(media.CacheKey, filecache.VariantOriginal)
+		}
+	}
+	if err != nil {
+		h.Logger.WithFields(loggerFields).Errorf("%d of %d originals sent before failure, err: %v", len(messages), len(addedMedias), err)
+		return
+	}
+
+	h.Logger.WithFields(loggerFields).Infof(""+
+		"%d originals sent as comment of channel post in "+
+		"discussion group", len(addedMedias))
+}
+
+// photoThumbnail 由照片的预览生成缩略图，预览已缓存 file_id 时使用原图
+func (h *Handler) photoThumbnail(media *fetchedMedia, loggerFields logrus.Fields) []byte {
+	body := media.Body
+	if body == nil {
+		body = media.OriginalBody
+	}
+
+	data, err := body.Bytes()
+	if err != nil {
+		h.Logger.WithFields(loggerFields).Error(err)
+		return nil
+	}
+
+	thumbnail, err := telegram.Thumbnail(data)
+	if err != nil {
+		h.Logger.WithFields(loggerFields).Error(err)
+		return nil
+	}
+
+	return thumbnail
+}
@@ -0,0 +1,47 @@
+package pipeline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExchange(t *testing.T) {
+	t.Run("AssignedAfterForward", func(t *testing.T) {
+		h := &Handler{}
+		assigned := &exchange{PostID: "1234"}
+
+		time.AfterFunc(50*time.Millisecond, func() {
+			h.assignExchange(-1001234567890, 1, assigned)
+		})
+
+		taken, ok := h.takeExchange(-1001234567890, 1, time.Second)
+		require.True(t, ok)
+		assert.Same(t, assigned, taken)
+
+		_, ok = h.takeExchange(-1001234567890, 1, 10*time.Millisecond)
+		assert.False(t, ok)
+	})
+
+	t.Run("AssignedBeforeForward", func(t *testing.T) {
+		h := &Handler{}
+		assigned := &exchange{PostID: "1234"}
+		h.assignExchange(-1001234567890, 2, assigned)
+
+		taken, ok := h.takeExchange(-1001234567890, 2, time.Second)
+		require.True(t, ok)
+		assert.Same(t, assigned, taken)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		h := &Handler{}
+
+		_, ok := h.takeExchange(-1001234567890, 3, 10*time.Millisecond)
+		assert.False(t, ok)
+
+		_, ok = h.Exchange.Load(exchangeKey(-1001234567890, 3))
+		assert.False(t, ok)
+	})
+}
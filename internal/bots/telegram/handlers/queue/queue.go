@@ -0,0 +1,283 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/handlers/pipeline"
+	"github.com/nekomeowww/perobot/internal/configs"
+	"github.com/nekomeowww/perobot/internal/models/postqueue"
+	"github.com/nekomeowww/perobot/pkg/bots/telegram"
+	"github.com/nekomeowww/perobot/pkg/handler"
+	"github.com/nekomeowww/perobot/pkg/logger"
+)
+
+const (
+	timeLayout = "2006-01-02 15:04"
+)
+
+var (
+	ErrInvalidScheduleTime = errors.New("invalid schedule time")
+)
+
+type NewHandlerParam struct {
+	fx.In
+
+	Logger         *logger.Logger
+	ChannelsConfig *configs.ChannelsConfig
+	PostQueue      *postqueue.Model
+	Pipeline       *pipeline.Handler
+}
+
+// Handler 处理暂存会话中的发布队列命令
+type Handler struct {
+	Logger         *logger.Logger
+	ChannelsConfig *configs.ChannelsConfig
+	PostQueue      *postqueue.Model
+	Pipeline       *pipeline.Handler
+}
+
+func NewHandler() func(param NewHandlerParam) *Handler {
+	return func(param NewHandlerParam) *Handler {
+		return &Handler{
+			Logger:         param.Logger,
+			ChannelsConfig: param.ChannelsConfig,
+			PostQueue:      param.PostQueue,
+			Pipeline:       param.Pipeline,
+		}
+	}
+}
+
+// HandleMessageQueueCommands 处理暂存会话中的命令
+//
+// 1. /t <url> 或 /t! <url> 将作品加入发布队列，按照频道配置的间隔依次发布；
+// 2. /schedule <time> <url> 将作品加入发布队列，并在指定的时间发布；
+// 3. /queue 列出发布队列中的作品；
+// 4. /queue remove <id> 从发布队列中移除作品。
+func (h *Handler) HandleMessageQueueCommands(c *handler.Context) {
+	if c.Update.Message.Chat == nil {
+		return
+	}
+
+	channelID, ok := h.ChannelsConfig.StagingChannel(c.Update.Message.Chat.ID)
+	if !ok {
+		return
+	}
+
+	command, argument, ok := telegram.ParseCommand(c.Update.Message.Text)
+	if !ok {
+		return
+	}
+
+	logEntry := h.Logger.WithFields(logrus.Fields{
+		"chat_id":    c.Update.Message.Chat.ID,
+		"channel_id": channelID,
+		"command":    command,
+	})
+
+	switch command {
+	case "/t", "/t!":
+		postCommand, ok := telegram.ParsePostCommand(c.Update.Message.Text)
+		if !ok {
+			h.reply(c, "用法：<code>/t &lt;链接&gt;</code>", logEntry)
+			return
+		}
+
+		h.enqueue(c, channelID, postCommand.Argument, postCommand.Force, nil, logEntry)
+	case "/schedule":
+		queueConfig := h.ChannelsConfig.Channel(channelID).Queue
+
+		scheduledAt, link, err := ParseScheduleArgument(argument, time.Now(), queueConfig.Location())
+		if err != nil {
+			h.reply(c, ""+
+				"用法：<code>/schedule &lt;时间&gt; &lt;链接&gt;</code>\n\n"+
+				"时间支持 <code>15:04</code>、<code>2006-01-02 15:04</code> 和 <code>+2h30m</code> 这几种格式，时区为 "+queueConfig.Location().String(), logEntry)
+
+			return
+		}
+
+		h.enqueue(c, channelID, link, false, &scheduledAt, logEntry)
+	case "/queue":
+		subcommand, subargument, _ := strings.Cut(argument, " ")
+		switch subcommand {
+		case "":
+			h.list(c, channelID, logEntry)
+		case "remove":
+			h.remove(c, channelID, strings.TrimSpace(subargument), logEntry)
+		default:
+			h.reply(c, "用法：<code>/queue</code> 或 <code>/queue remove &lt;编号&gt;</code>", logEntry)
+		}
+	}
+}
+
+func (h *Handler) enqueue(c *handler.Context, channelID int64, link string, force bool, scheduledAt *time.Time, logEntry *logrus.Entry) {
+	if !h.Pipeline.Match(channelID, link) {
+		h.reply(c, "不支持的链接", logEntry)
+		return
+	}
+
+	item, err := h.PostQueue.Enqueue(channelID, postqueue.Item{
+		URL:              link,
+		Force:            force,
+		ScheduledAt:      scheduledAt,
+		StagingChatID:    c.Update.Message.Chat.ID,
+		StagingMessageID: c.Update.Message.MessageID,
+	})
+	if err != nil {
+		logEntry.Errorf("failed to enqueue, err: %v", err)
+		h.reply(c, "加入发布队列失败", logEntry)
+
+		return
+	}
+
+	logEntry.WithField("queue_item_id", item.ID).Infof("enqueued %s", link)
+
+	plannedAt, ok := h.plannedAt(channelID, item.ID)
+	if !ok {
+		h.reply(c, fmt.Sprintf("已加入发布队列，编号 #%d", item.ID), logEntry)
+		return
+	}
+
+	h.reply(c, fmt.Sprintf("已加入发布队列，编号 #%d，预计于 %s 发布", item.ID, plannedAt), logEntry)
+}
+
+// plannedAt 返回作品 id 的预计发布时间
+func (h *Handler) plannedAt(channelID int64, id int64) (string, bool) {
+	items, err := h.PostQueue.List(channelID)
+	if err != nil {
+		return "", false
+	}
+
+	queueConfig := h.ChannelsConfig.Channel(channelID).Queue
+	planned := postqueue.Plan(items, time.Now(), h.PostQueue.LastPublishedAt(channelID), queueConfig)
+
+	for i, item := range items {
+		if item.ID == id {
+			return planned[i].In(queueConfig.Location()).Format(timeLayout), true
+		}
+	}
+
+	return "", false
+}
+
+func (h *Handler) list(c *handler.Context, channelID int64, logEntry *logrus.Entry) {
+	items, err := h.PostQueue.List(channelID)
+	if err != nil {
+		logEntry.Errorf("failed to list post queue, err: %v", err)
+		h.reply(c, "获取发布队列失败", logEntry)
+
+		return
+	}
+	if len(items) == 0 {
+		h.reply(c, "发布队列是空的", logEntry)
+		return
+	}
+
+	queueConfig := h.ChannelsConfig.Channel(channelID).Queue
+	planned := postqueue.Plan(items, time.Now(), h.PostQueue.LastPublishedAt(channelID), queueConfig)
+
+	lines := make([]string, 0, len(items)+1)
+	lines = append(lines, fmt.Sprintf("发布队列中共有 %d 个作品：\n", len(items)))
+
+	for i, item := range items {
+		plannedAt := planned[i].In(queueConfig.Location()).Format(timeLayout)
+		if item.ScheduledAt != nil {
+			plannedAt += "（定时）"
+		}
+
+		lines = append(lines, formatItem(item, plannedAt))
+	}
+
+	h.reply(c, strings.Join(lines, "\n"), logEntry)
+}
+
+// formatItem 返回发布队列列表中的一行，链接由用户输入，可能包含 HTML 中的特殊字符
+func formatItem(item postqueue.Item, plannedAt string) string {
+	link := html.EscapeString(item.URL)
+	return fmt.Sprintf(`#%d %s <a href="%s">%s</a>`, item.ID, plannedAt, link, link)
+}
+
+func (h *Handler) remove(c *handler.Context, channelID int64, argument string, logEntry *logrus.Entry) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(argument, "#"), 10, 64)
+	if err != nil {
+		h.reply(c, "用法：<code>/queue remove &lt;编号&gt;</code>", logEntry)
+		return
+	}
+
+	removed, err := h.PostQueue.Remove(channelID, id)
+	if err != nil {
+		logEntry.Errorf("failed to remove item from post queue, err: %v", err)
+		h.reply(c, "移出发布队列失败", logEntry)
+
+		return
+	}
+	if !removed {
+		h.reply(c, fmt.Sprintf("发布队列中没有 #%d", id), logEntry)
+		return
+	}
+
+	h.reply(c, fmt.Sprintf("已将 #%d 移出发布队列", id), logEntry)
+}
+
+func (h *Handler) reply(c *handler.Context, text string, logEntry *logrus.Entry) {
+	message := tgbotapi.NewMessage(c.Update.Message.Chat.ID, text)
+	message.ParseMode = "HTML"
+	message.ReplyToMessageID = c.Update.Message.MessageID
+	message.DisableWebPagePreview = true
+
+	_, err := c.Bot.Send(message)
+	if err != nil {
+		logEntry.Errorf("failed to reply, err: %v", err)
+	}
+}
+
+// ParseScheduleArgument 解析 /schedule 命令的参数 <time> <url>，时间支持以下格式：
+//
+// 1. 15:04，今天的该时间已经过去时为明天；
+// 2. 2006-01-02 15:04；
+// 3. +2h30m，相对于 now 的时长。
+func ParseScheduleArgument(argument string, now time.Time, location *time.Location) (time.Time, string, error) {
+	separatorIndex := strings.LastIndex(argument, " ")
+	if separatorIndex == -1 {
+		return time.Time{}, "", ErrInvalidScheduleTime
+	}
+
+	timeText := strings.TrimSpace(argument[:separatorIndex])
+	link := strings.TrimSpace(argument[separatorIndex+1:])
+
+	if strings.HasPrefix(timeText, "+") {
+		duration, err := time.ParseDuration(strings.TrimPrefix(timeText, "+"))
+		if err != nil || duration <= 0 {
+			return time.Time{}, "", ErrInvalidScheduleTime
+		}
+
+		return now.Add(duration), link, nil
+	}
+
+	scheduledAt, err := time.ParseInLocation(timeLayout, timeText, location)
+	if err == nil {
+		return scheduledAt, link, nil
+	}
+
+	clockTime, err := time.ParseInLocation("15:04", timeText, location)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidScheduleTime
+	}
+
+	localNow := now.In(location)
+
+	scheduledAt = time.Date(localNow.Year(), localNow.Month(), localNow.Day(), clockTime.Hour(), clockTime.Minute(), 0, 0, location)
+	if !scheduledAt.After(now) {
+		scheduledAt = scheduledAt.AddDate(0, 0, 1)
+	}
+
+	return scheduledAt, link, nil
+}
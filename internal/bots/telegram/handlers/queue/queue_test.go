@@ -0,0 +1,57 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nekomeowww/perobot/internal/models/postqueue"
+)
+
+func TestParseScheduleArgument(t *testing.T) {
+	location, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+
+	now := time.Date(2023, 1, 1, 20, 0, 0, 0, location)
+	link := "https://www.pixiv.net/artworks/1234"
+
+	t.Run("DateTime", func(t *testing.T) {
+		scheduledAt, parsedLink, err := ParseScheduleArgument("2023-01-03 08:30 "+link, now, location)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2023, 1, 3, 8, 30, 0, 0, location), scheduledAt)
+		assert.Equal(t, link, parsedLink)
+	})
+
+	t.Run("ClockTime", func(t *testing.T) {
+		scheduledAt, _, err := ParseScheduleArgument("21:30 "+link, now, location)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2023, 1, 1, 21, 30, 0, 0, location), scheduledAt)
+
+		scheduledAt, _, err = ParseScheduleArgument("08:00 "+link, now, location)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2023, 1, 2, 8, 0, 0, 0, location), scheduledAt)
+	})
+
+	t.Run("Relative", func(t *testing.T) {
+		scheduledAt, _, err := ParseScheduleArgument("+2h30m "+link, now, location)
+		require.NoError(t, err)
+		assert.Equal(t, now.Add(150*time.Minute), scheduledAt)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, argument := range []string{"", link, "tomorrow " + link, "+-1h " + link} {
+			_, _, err := ParseScheduleArgument(argument, now, location)
+			assert.ErrorIs(t, err, ErrInvalidScheduleTime, argument)
+		}
+	})
+}
+
+func TestFormatItem(t *testing.T) {
+	item := postqueue.Item{ID: 3, URL: `https://example.com/a?b=1&c="<d>`}
+
+	assert.Equal(t,
+		`#3 2023-01-01 20:00 <a href="https://example.com/a?b=1&amp;c=&#34;&lt;d&gt;">https://example.com/a?b=1&amp;c=&#34;&lt;d&gt;</a>`,
+		formatItem(item, "2023-01-01 20:00"))
+}
@@ -0,0 +1,186 @@
+package artstation
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/samber/lo"
+	"go.uber.org/fx"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	"github.com/nekomeowww/perobot/internal/models/published"
+	"github.com/nekomeowww/perobot/internal/thirdparty"
+	artstation_public_types "github.com/nekomeowww/perobot/pkg/artstation/public/types"
+	"github.com/nekomeowww/perobot/pkg/logger"
+)
+
+type NewProviderParam struct {
+	fx.In
+
+	Logger     *logger.Logger
+	ArtStation *thirdparty.ArtStationPublic
+}
+
+// Client Provider 所使用的 ArtStation 接口，由 thirdparty.ArtStationPublic 实现
+type Client interface {
+	GetProject(hashID string) (*artstation_public_types.Project, error)
+	Download(link string, w io.Writer) error
+}
+
+// Provider 发布 ArtStation 作品中的图片，视频无法下载，以链接的形式附加在说明文字中
+type Provider struct {
+	Logger     *logger.Logger
+	ArtStation Client
+}
+
+func NewProvider() func(param NewProviderParam) *Provider {
+	return func(param NewProviderParam) *Provider {
+		return &Provider{
+			Logger:     param.Logger,
+			ArtStation: param.ArtStation,
+		}
+	}
+}
+
+func (p *Provider) Source() published.Source {
+	return published.SourceArtStation
+}
+
+// Match 返回作品链接中的 hash
+func (p *Provider) Match(link string) string {
+	return ProjectHashFromText(publishing.LinkWithoutQuery(link))
+}
+
+func (p *Provider) FetchPost(postID string) (*publishing.Post, error) {
+	project, err := p.ArtStation.GetProject(postID)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, nil
+	}
+
+	post := &publishing.Post{
+		ID:        postID,
+		URL:       lo.Ternary(project.Permalink != "", project.Permalink, fmt.Sprintf("https://www.artstation.com/artwork/%s", postID)),
+		Sensitive: project.AdultContent,
+		Raw:       project,
+	}
+	if post.Sensitive {
+		post.SensitiveLabel = "Mature"
+	}
+	if project.User != nil {
+		post.Author = publishing.Author{
+			Name:   project.User.Name(),
+			Handle: project.User.Username,
+			URL:    project.User.Permalink,
+		}
+	}
+
+	return post, nil
+}
+
+// ListMedia 列出作品中的图片，优先使用 4k 尺寸，不存在时使用 large 尺寸
+func (p *Provider) ListMedia(post *publishing.Post) ([]*publishing.Media, error) {
+	project, ok := post.Raw.(*artstation_public_types.Project)
+	if !ok {
+		return nil, errors.New("post is not an artstation project")
+	}
+
+	medias := make([]*publishing.Media, 0, len(project.Assets))
+	for _, asset := range project.Assets {
+		if !asset.IsImage() {
+			continue
+		}
+
+		imageURL4K := asset.ImageURL4K()
+		ext := path.Ext(publishing.URLBase(asset.ImageURL))
+
+		medias = append(medias, &publishing.Media{
+			Type:             publishing.MediaTypePhoto,
+			CacheKey:         asset.ImageURL,
+			PreviewURLs:      lo.Uniq([]string{imageURL4K, asset.ImageURL}),
+			OriginalURL:      imageURL4K,
+			FileName:         publishing.URLBase(asset.ImageURL),
+			OriginalFileName: fmt.Sprintf("artstation-by-%s-%s-%d%s", post.Author.Handle, post.ID, len(medias), ext),
+			Width:            asset.Width,
+			Height:           asset.Height,
+		})
+	}
+
+	return medias, nil
+}
+
+// RenderCaption 说明文字包含作品的标题、视频的链接和标签
+func (p *Provider) RenderCaption(post *publishing.Post, labels []string) string {
+	authorInfo := "未知"
+	if post.Author.Name != "" {
+		authorInfo = fmt.Sprintf(`<a href="%s">%s</a>`, post.Author.URL, html.EscapeString(post.Author.Name))
+	}
+
+	var content string
+	tags := make([]string, 0)
+
+	project, ok := post.Raw.(*artstation_public_types.Project)
+	if ok {
+		content = html.EscapeString(project.Title)
+
+		videoLinks := make([]string, 0)
+		for _, asset := range project.Assets {
+			if !asset.IsVideo() {
+				continue
+			}
+
+			playerURL := asset.PlayerURL()
+			if playerURL == "" {
+				continue
+			}
+
+			videoLinks = append(videoLinks, fmt.Sprintf(`<a href="%s">视频 %d</a>`, html.EscapeString(playerURL), len(videoLinks)+1))
+		}
+		if len(videoLinks) > 0 {
+			content = strings.TrimSpace(content + "\n\n" + strings.Join(videoLinks, " "))
+		}
+
+		for _, tag := range project.Tags {
+			hashtag := publishing.Hashtag(tag)
+			if hashtag == "" {
+				continue
+			}
+
+			tags = append(tags, "#"+hashtag)
+		}
+	}
+
+	tags = append(lo.Uniq(tags), labels...)
+
+	return publishing.FormatCaption(authorInfo, content, tags, "ArtStation", post.URL)
+}
+
+func (p *Provider) Download(link string, w io.Writer) error {
+	return p.ArtStation.Download(link, w)
+}
+
+var (
+	// ArtworkLinkRegexp 作品链接 https://www.artstation.com/artwork/<hash>
+	ArtworkLinkRegexp = regexp.MustCompile(`^https://(?:www\.)?artstation\.com/artwork/([A-Za-z0-9]+)$`)
+	// PortfolioProjectLinkRegexp 个人作品集中的作品链接 https://<user>.artstation.com/projects/<hash>
+	PortfolioProjectLinkRegexp = regexp.MustCompile(`^https://[A-Za-z0-9-]+\.artstation\.com/projects/([A-Za-z0-9]+)$`)
+)
+
+// ProjectHashFromText 返回作品链接中的 hash，不是作品链接时返回空字符串
+func ProjectHashFromText(text string) string {
+	for _, linkRegexp := range []*regexp.Regexp{ArtworkLinkRegexp, PortfolioProjectLinkRegexp} {
+		matches := linkRegexp.FindStringSubmatch(text)
+		if len(matches) == 2 {
+			return matches[1]
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,106 @@
+package artstation
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	"github.com/nekomeowww/perobot/internal/lib"
+	artstation_public_types "github.com/nekomeowww/perobot/pkg/artstation/public/types"
+)
+
+const testProject = `{
+	"id": 1,
+	"hash_id": "AbCdE1",
+	"title": "Castle & Keep",
+	"tags": ["environment", "concept art", "Environment"],
+	"permalink": "https://www.artstation.com/artwork/AbCdE1",
+	"adult_content": true,
+	"user": { "username": "alice", "full_name": "Alice", "permalink": "https://www.artstation.com/alice" },
+	"assets": [
+		{ "id": 9, "asset_type": "cover", "has_image": true, "image_url": "https://cdna.artstation.com/p/assets/covers/images/000/000/009/large/cover.jpg", "position": 0 },
+		{ "id": 10, "asset_type": "image", "has_image": true, "image_url": "https://cdna.artstation.com/p/assets/images/images/000/000/010/large/alice-castle.jpg?1700000000", "width": 3840, "height": 2160, "position": 0 },
+		{ "id": 11, "asset_type": "video", "has_image": false, "player_embedded": "<iframe width=\"920\" src=\"https://www.youtube.com/embed/abc\"></iframe>", "position": 1 }
+	]
+}`
+
+// fakeClient 返回固定作品的 Client，请求和解析由 artstation_public 的测试覆盖
+type fakeClient struct {
+	t *testing.T
+}
+
+func (c *fakeClient) GetProject(hashID string) (*artstation_public_types.Project, error) {
+	if hashID != "AbCdE1" {
+		return nil, nil
+	}
+
+	var project artstation_public_types.Project
+	require.NoError(c.t, json.Unmarshal([]byte(testProject), &project))
+
+	return &project, nil
+}
+
+func (c *fakeClient) Download(link string, w io.Writer) error {
+	return errors.New("not implemented")
+}
+
+func TestProjectHashFromText(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("AbCdE1", ProjectHashFromText("https://www.artstation.com/artwork/AbCdE1"))
+	assert.Equal("AbCdE1", ProjectHashFromText("https://alice.artstation.com/projects/AbCdE1"))
+	assert.Empty(ProjectHashFromText("https://www.artstation.com/alice"))
+}
+
+func TestMatch(t *testing.T) {
+	assert := assert.New(t)
+
+	provider := &Provider{}
+
+	assert.Equal("AbCdE1", provider.Match("https://www.artstation.com/artwork/AbCdE1?utm_source=twitter"))
+	assert.Equal("AbCdE1", provider.Match("https://www.ArtStation.com/artwork/AbCdE1"))
+	assert.Equal("AbCdE1", provider.Match("https://alice.artstation.com/projects/AbCdE1#comments"))
+	// 作品的哈希区分大小写
+	assert.NotEqual("abcde1", provider.Match("https://www.artstation.com/artwork/AbCdE1"))
+	assert.Empty(provider.Match("https://www.artstation.com/search?q=AbCdE1"))
+}
+
+func TestFetchPostAndListMedia(t *testing.T) {
+	provider := &Provider{Logger: lib.NewLogger()(), ArtStation: &fakeClient{t: t}}
+
+	postID := provider.Match("https://www.artstation.com/artwork/AbCdE1?utm_source=share")
+	require.Equal(t, "AbCdE1", postID)
+
+	post, err := provider.FetchPost(postID)
+	require.NoError(t, err)
+	require.NotNil(t, post)
+	assert.True(t, post.Sensitive)
+	assert.Equal(t, "Alice", post.Author.Name)
+
+	medias, err := provider.ListMedia(post)
+	require.NoError(t, err)
+	require.Len(t, medias, 1)
+	assert.Equal(t, publishing.MediaTypePhoto, medias[0].Type)
+	assert.Equal(t, []string{
+		"https://cdna.artstation.com/p/assets/images/images/000/000/010/4k/alice-castle.jpg?1700000000",
+		"https://cdna.artstation.com/p/assets/images/images/000/000/010/large/alice-castle.jpg?1700000000",
+	}, medias[0].PreviewURLs)
+	assert.Equal(t, "artstation-by-alice-AbCdE1-0.jpg", medias[0].OriginalFileName)
+
+	assert.Equal(t, ""+
+		`<a href="https://www.artstation.com/alice">Alice</a>：`+"\n\n"+
+		"Castle &amp; Keep\n\n"+
+		`<a href="https://www.youtube.com/embed/abc">视频 1</a>`+"\n\n"+
+		"#environment #concept_art #Environment\n\n"+
+		`来自 <a href="https://www.artstation.com/artwork/AbCdE1">ArtStation</a>`,
+		provider.RenderCaption(post, nil))
+
+	post, err = provider.FetchPost("missing")
+	require.NoError(t, err)
+	assert.Nil(t, post)
+}
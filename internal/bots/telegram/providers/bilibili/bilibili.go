@@ -0,0 +1,165 @@
+package bilibili
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"go.uber.org/fx"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	"github.com/nekomeowww/perobot/internal/models/published"
+	"github.com/nekomeowww/perobot/internal/thirdparty"
+	bilibili_public_types "github.com/nekomeowww/perobot/pkg/bilibili/public/types"
+	"github.com/nekomeowww/perobot/pkg/logger"
+)
+
+type NewProviderParam struct {
+	fx.In
+
+	Logger   *logger.Logger
+	Bilibili *thirdparty.BilibiliPublic
+}
+
+// Client Provider 所使用的 B 站接口，由 thirdparty.BilibiliPublic 实现
+type Client interface {
+	GetDynamicDetail(id string) (*bilibili_public_types.DynamicItem, error)
+	Download(link string, w io.Writer) error
+}
+
+// Provider 发布 B 站图文动态中的原图
+type Provider struct {
+	Logger   *logger.Logger
+	Bilibili Client
+}
+
+func NewProvider() func(param NewProviderParam) *Provider {
+	return func(param NewProviderParam) *Provider {
+		return &Provider{
+			Logger:   param.Logger,
+			Bilibili: param.Bilibili,
+		}
+	}
+}
+
+func (p *Provider) Source() published.Source {
+	return published.SourceBilibili
+}
+
+// Match 返回动态的 ID，t.bilibili.com 和 opus 链接中的 ID 相同
+func (p *Provider) Match(link string) string {
+	return DynamicIDFromText(publishing.LinkWithoutQuery(link))
+}
+
+func (p *Provider) FetchPost(postID string) (*publishing.Post, error) {
+	item, err := p.Bilibili.GetDynamicDetail(postID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, nil
+	}
+
+	// 没有附带图片的转发使用原动态
+	if item.Type == bilibili_public_types.DynamicTypeForward && len(item.Pictures()) == 0 && item.Orig != nil {
+		item = item.Orig
+	}
+
+	post := &publishing.Post{
+		ID:  postID,
+		URL: fmt.Sprintf("https://www.bilibili.com/opus/%s", item.IDStr),
+		Raw: item,
+	}
+	if author := item.Author(); author != nil {
+		post.Author = publishing.Author{
+			Name:   author.Name,
+			Handle: fmt.Sprintf("%d", author.Mid),
+			URL:    author.SpaceURL(),
+		}
+	}
+
+	return post, nil
+}
+
+// ListMedia 列出动态中的原图，GIF 作为动图发布
+func (p *Provider) ListMedia(post *publishing.Post) ([]*publishing.Media, error) {
+	item, ok := post.Raw.(*bilibili_public_types.DynamicItem)
+	if !ok {
+		return nil, errors.New("post is not a bilibili dynamic")
+	}
+
+	pictures := item.Pictures()
+
+	medias := make([]*publishing.Media, 0, len(pictures))
+	for i, picture := range pictures {
+		if picture.URL == "" {
+			continue
+		}
+
+		originalURL := strings.Replace(picture.URL, "http://", "https://", 1)
+		ext := path.Ext(publishing.URLBase(originalURL))
+
+		media := &publishing.Media{
+			Type:             publishing.MediaTypePhoto,
+			CacheKey:         originalURL,
+			PreviewURLs:      []string{originalURL},
+			OriginalURL:      originalURL,
+			FileName:         publishing.URLBase(originalURL),
+			OriginalFileName: fmt.Sprintf("bilibili-by-%s-%s-%d%s", post.Author.Handle, post.ID, i, ext),
+			Width:            picture.Width,
+			Height:           picture.Height,
+		}
+		if strings.EqualFold(ext, ".gif") {
+			media.Type = publishing.MediaTypeAnimation
+		} else {
+			// 原图过大无法发送时使用图床缩小到宽度为 2000 的版本
+			media.PreviewURLs = append(media.PreviewURLs, originalURL+"@2000w.jpg")
+		}
+
+		medias = append(medias, media)
+	}
+
+	return medias, nil
+}
+
+func (p *Provider) RenderCaption(post *publishing.Post, labels []string) string {
+	authorInfo := "未知"
+	if post.Author.Name != "" {
+		authorInfo = fmt.Sprintf(`<a href="%s">%s</a>`, post.Author.URL, html.EscapeString(post.Author.Name))
+	}
+
+	var content string
+	item, ok := post.Raw.(*bilibili_public_types.DynamicItem)
+	if ok {
+		content = item.TextInHTML()
+	}
+
+	return publishing.FormatCaption(authorInfo, content, labels, "哔哩哔哩", post.URL)
+}
+
+func (p *Provider) Download(link string, w io.Writer) error {
+	return p.Bilibili.Download(link, w)
+}
+
+var (
+	// DynamicLinkRegexp 动态链接 https://t.bilibili.com/<id>，移动版为 https://m.bilibili.com/dynamic/<id>
+	DynamicLinkRegexp = regexp.MustCompile(`^https://(?:t\.bilibili\.com|m\.bilibili\.com/dynamic)/(\d+)$`)
+	// OpusLinkRegexp 新版图文链接 https://www.bilibili.com/opus/<id>
+	OpusLinkRegexp = regexp.MustCompile(`^https://(?:www\.|m\.)?bilibili\.com/opus/(\d+)$`)
+)
+
+// DynamicIDFromText 返回链接中动态的 ID，不是动态链接时返回空字符串
+func DynamicIDFromText(text string) string {
+	for _, linkRegexp := range []*regexp.Regexp{DynamicLinkRegexp, OpusLinkRegexp} {
+		matches := linkRegexp.FindStringSubmatch(text)
+		if len(matches) == 2 {
+			return matches[1]
+		}
+	}
+
+	return ""
+}
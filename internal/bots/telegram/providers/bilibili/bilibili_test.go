@@ -0,0 +1,123 @@
+package bilibili
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	"github.com/nekomeowww/perobot/internal/lib"
+	bilibili_public_types "github.com/nekomeowww/perobot/pkg/bilibili/public/types"
+)
+
+const testForwardDynamic = `{
+	"code": 0,
+	"message": "0",
+	"data": {
+		"item": {
+			"id_str": "900000000000000002",
+			"type": "DYNAMIC_TYPE_FORWARD",
+			"modules": {
+				"module_author": { "mid": 1, "name": "路人" },
+				"module_dynamic": { "desc": { "text": "转发动态", "rich_text_nodes": [] }, "major": null }
+			},
+			"orig": {
+				"id_str": "900000000000000001",
+				"type": "DYNAMIC_TYPE_DRAW",
+				"modules": {
+					"module_author": { "mid": 12345, "name": "画师" },
+					"module_dynamic": {
+						"desc": { "text": "新图", "rich_text_nodes": [{ "type": "RICH_TEXT_NODE_TYPE_TEXT", "text": "新图" }] },
+						"major": {
+							"type": "MAJOR_TYPE_DRAW",
+							"draw": {
+								"items": [
+									{ "src": "http://i0.hdslb.com/bfs/new_dyn/abc.png", "width": 2000, "height": 3000 },
+									{ "src": "https://i0.hdslb.com/bfs/new_dyn/def.gif", "width": 320, "height": 240 }
+								]
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}`
+
+// fakeClient 返回固定动态的 Client，请求和解析由 bilibili_public 的测试覆盖
+type fakeClient struct {
+	t *testing.T
+}
+
+func (c *fakeClient) GetDynamicDetail(id string) (*bilibili_public_types.DynamicItem, error) {
+	if id != "900000000000000002" {
+		return nil, nil
+	}
+
+	var detailResp bilibili_public_types.DynamicDetailResp
+	require.NoError(c.t, json.Unmarshal([]byte(testForwardDynamic), &detailResp))
+
+	return detailResp.Data.Item, nil
+}
+
+func (c *fakeClient) Download(link string, w io.Writer) error {
+	return errors.New("not implemented")
+}
+
+func TestDynamicIDFromText(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("900000000000000001", DynamicIDFromText("https://t.bilibili.com/900000000000000001"))
+	assert.Equal("900000000000000001", DynamicIDFromText("https://www.bilibili.com/opus/900000000000000001"))
+	assert.Equal("900000000000000001", DynamicIDFromText("https://m.bilibili.com/dynamic/900000000000000001"))
+	assert.Empty(DynamicIDFromText("https://www.bilibili.com/video/BV1xx411c7mD"))
+	assert.Empty(DynamicIDFromText("https://space.bilibili.com/12345"))
+}
+
+func TestMatch(t *testing.T) {
+	assert := assert.New(t)
+
+	provider := &Provider{}
+
+	assert.Equal("900000000000000001", provider.Match("https://t.bilibili.com/900000000000000001?share_source=pc_native"))
+	assert.Equal("900000000000000001", provider.Match("https://www.bilibili.com/opus/900000000000000001?spm_id_from=333.999.0.0#reply"))
+	assert.Equal("900000000000000001", provider.Match("https://M.Bilibili.com/dynamic/900000000000000001"))
+	assert.Empty(provider.Match("https://www.bilibili.com/video/BV1xx411c7mD?p=1"))
+	assert.Empty(provider.Match("https://t.bilibili.com/?tab=all"))
+}
+
+func TestFetchPostAndListMedia(t *testing.T) {
+	provider := &Provider{Logger: lib.NewLogger()(), Bilibili: &fakeClient{t: t}}
+
+	postID := provider.Match("https://t.bilibili.com/900000000000000002?share_source=pc_native")
+	require.Equal(t, "900000000000000002", postID)
+
+	post, err := provider.FetchPost(postID)
+	require.NoError(t, err)
+	require.NotNil(t, post)
+	assert.Equal(t, "https://www.bilibili.com/opus/900000000000000001", post.URL)
+	assert.Equal(t, "画师", post.Author.Name)
+
+	medias, err := provider.ListMedia(post)
+	require.NoError(t, err)
+	require.Len(t, medias, 2)
+
+	assert.Equal(t, publishing.MediaTypePhoto, medias[0].Type)
+	assert.Equal(t, []string{"https://i0.hdslb.com/bfs/new_dyn/abc.png", "https://i0.hdslb.com/bfs/new_dyn/abc.png@2000w.jpg"}, medias[0].PreviewURLs)
+	assert.Equal(t, "bilibili-by-12345-900000000000000002-0.png", medias[0].OriginalFileName)
+	assert.Equal(t, publishing.MediaTypeAnimation, medias[1].Type)
+
+	assert.Equal(t, ""+
+		`<a href="https://space.bilibili.com/12345">画师</a>：`+"\n\n"+
+		"新图\n\n"+
+		`来自 <a href="https://www.bilibili.com/opus/900000000000000001">哔哩哔哩</a>`,
+		provider.RenderCaption(post, nil))
+
+	post, err = provider.FetchPost("900000000000000000")
+	require.NoError(t, err)
+	assert.Nil(t, post)
+}
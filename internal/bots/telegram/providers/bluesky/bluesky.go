@@ -0,0 +1,305 @@
+package bluesky
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+
+	"go.uber.org/fx"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	"github.com/nekomeowww/perobot/internal/models/published"
+	"github.com/nekomeowww/perobot/internal/thirdparty"
+	bluesky_public "github.com/nekomeowww/perobot/pkg/bluesky/public"
+	bluesky_public_types "github.com/nekomeowww/perobot/pkg/bluesky/public/types"
+	"github.com/nekomeowww/perobot/pkg/logger"
+)
+
+type NewProviderParam struct {
+	fx.In
+
+	Logger  *logger.Logger
+	Bluesky *thirdparty.BlueskyPublic
+}
+
+// Client Provider 所使用的 Bluesky 接口，由 thirdparty.BlueskyPublic 实现
+type Client interface {
+	GetPostThread(uri string) (*bluesky_public_types.GetPostThreadResp, error)
+	ResolveDID(did string) (*bluesky_public_types.DIDDocument, error)
+	VideoPlaylist(playlistURL string) ([]bluesky_public_types.PlaylistVariant, error)
+	Download(link string, w io.Writer) error
+}
+
+// Provider 发布 Bluesky 帖子中的图片和视频
+type Provider struct {
+	Logger  *logger.Logger
+	Bluesky Client
+}
+
+func NewProvider() func(param NewProviderParam) *Provider {
+	return func(param NewProviderParam) *Provider {
+		return &Provider{
+			Logger:  param.Logger,
+			Bluesky: param.Bluesky,
+		}
+	}
+}
+
+func (p *Provider) Source() published.Source {
+	return published.SourceBluesky
+}
+
+// Match 返回 <handle 或 DID>/<rkey> 形式的帖子 ID
+func (p *Provider) Match(link string) string {
+	return PostIDFromText(publishing.LinkWithoutQuery(link))
+}
+
+func (p *Provider) FetchPost(postID string) (*publishing.Post, error) {
+	actor, rkey, ok := strings.Cut(postID, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid bluesky post id: %s", postID)
+	}
+
+	resp, err := p.Bluesky.GetPostThread(bluesky_public.PostURI(actor, rkey))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Thread == nil || resp.Thread.Post == nil {
+		return nil, nil
+	}
+
+	blueskyPost := resp.Thread.Post
+	if blueskyPost.Author == nil || blueskyPost.Record == nil {
+		return nil, errors.New("bluesky post has no author or record")
+	}
+
+	sensitiveLabelValues := blueskyPost.SensitiveLabelValues()
+
+	return &publishing.Post{
+		ID:  postID,
+		URL: fmt.Sprintf("https://bsky.app/profile/%s/post/%s", blueskyPost.Author.Handle, rkey),
+		Author: publishing.Author{
+			Name:   blueskyPost.Author.Name(),
+			Handle: blueskyPost.Author.Handle,
+			URL:    fmt.Sprintf("https://bsky.app/profile/%s", blueskyPost.Author.Handle),
+		},
+		Sensitive:      len(sensitiveLabelValues) > 0,
+		SensitiveLabel: strings.Join(sensitiveLabelValues, ", "),
+		Raw:            blueskyPost,
+	}, nil
+}
+
+// ListMedia 列出帖子中的图片和视频，引用其他帖子时只发布帖子本身附带的媒体
+func (p *Provider) ListMedia(post *publishing.Post) ([]*publishing.Media, error) {
+	blueskyPost, ok := post.Raw.(*bluesky_public_types.PostView)
+	if !ok {
+		return nil, errors.New("post is not a bluesky post")
+	}
+
+	mediaView := blueskyPost.Embed.MediaView()
+	if mediaView == nil {
+		return []*publishing.Media{}, nil
+	}
+
+	// 原图和视频的原始文件保存在作者的 PDS 上，无法解析时图片使用 CDN 压缩过的大图
+	pdsEndpoint := p.pdsEndpoint(blueskyPost.Author.DID)
+	mediaEmbed := blueskyPost.Record.Embed.MediaEmbed()
+
+	switch mediaView.Type {
+	case bluesky_public_types.EmbedTypeImagesView:
+		return p.imageMedias(post, blueskyPost, mediaView, mediaEmbed, pdsEndpoint), nil
+	case bluesky_public_types.EmbedTypeVideoView:
+		videoMedia := p.videoMedia(post, blueskyPost, mediaView, mediaEmbed, pdsEndpoint)
+		if videoMedia == nil {
+			return []*publishing.Media{}, nil
+		}
+
+		return []*publishing.Media{videoMedia}, nil
+	default:
+		return []*publishing.Media{}, nil
+	}
+}
+
+func (p *Provider) imageMedias(
+	post *publishing.Post,
+	blueskyPost *bluesky_public_types.PostView,
+	mediaView *bluesky_public_types.EmbedView,
+	mediaEmbed *bluesky_public_types.Embed,
+	pdsEndpoint string,
+) []*publishing.Media {
+	medias := make([]*publishing.Media, 0, len(mediaView.Images))
+	for i, image := range mediaView.Images {
+		if image.Fullsize == "" {
+			continue
+		}
+
+		// 帖子记录中的图片与 AppView 返回的图片顺序相同
+		var blob *bluesky_public_types.Blob
+		if mediaEmbed != nil && i < len(mediaEmbed.Images) {
+			blob = mediaEmbed.Images[i].Image
+		}
+
+		originalURL := image.Fullsize
+		originalExt := ".jpg"
+		if pdsEndpoint != "" && blob.CID() != "" {
+			originalURL = bluesky_public.BlobURL(pdsEndpoint, blueskyPost.Author.DID, blob.CID())
+			originalExt = mimeTypeExt(blob.MimeType, originalExt)
+		}
+
+		media := &publishing.Media{
+			Type:             publishing.MediaTypePhoto,
+			CacheKey:         image.Fullsize,
+			PreviewURLs:      []string{image.Fullsize},
+			OriginalURL:      originalURL,
+			FileName:         fmt.Sprintf("%s-%d.jpg", postRKey(post), i),
+			OriginalFileName: originalFileName(post, i, originalExt),
+			Description:      image.Alt,
+		}
+		if image.AspectRatio != nil {
+			media.Width = image.AspectRatio.Width
+			media.Height = image.AspectRatio.Height
+		}
+
+		medias = append(medias, media)
+	}
+
+	return medias
+}
+
+// videoMedia 视频在 CDN 上只有 HLS 播放列表，无法直接发送，需要从 PDS 下载上传时的原始文件
+func (p *Provider) videoMedia(
+	post *publishing.Post,
+	blueskyPost *bluesky_public_types.PostView,
+	mediaView *bluesky_public_types.EmbedView,
+	mediaEmbed *bluesky_public_types.Embed,
+	pdsEndpoint string,
+) *publishing.Media {
+	cid := mediaView.CID
+	mimeType := ""
+	if mediaEmbed != nil && mediaEmbed.Video != nil {
+		cid = mediaEmbed.Video.CID()
+		mimeType = mediaEmbed.Video.MimeType
+	}
+	if pdsEndpoint == "" || cid == "" {
+		p.Logger.WithField("bluesky_post_id", post.ID).Warn("unable to locate the original video file, skipping...")
+		return nil
+	}
+
+	blobURL := bluesky_public.BlobURL(pdsEndpoint, blueskyPost.Author.DID, cid)
+	ext := mimeTypeExt(mimeType, ".mp4")
+
+	media := &publishing.Media{
+		Type:             publishing.MediaTypeVideo,
+		CacheKey:         blobURL,
+		PreviewURLs:      []string{blobURL},
+		OriginalURL:      blobURL,
+		FileName:         fmt.Sprintf("%s-%s%s", postRKey(post), cid, ext),
+		OriginalFileName: originalFileName(post, 0, ext),
+		PosterURL:        mediaView.Thumbnail,
+		Description:      mediaView.Alt,
+	}
+
+	media.Width, media.Height = p.videoDimensions(mediaView)
+
+	return media
+}
+
+// videoDimensions 返回视频的宽高，帖子中没有视频尺寸时使用播放列表中最高清晰度的分辨率
+func (p *Provider) videoDimensions(mediaView *bluesky_public_types.EmbedView) (int, int) {
+	if mediaView.AspectRatio != nil && mediaView.AspectRatio.Width > 0 && mediaView.AspectRatio.Height > 0 {
+		return mediaView.AspectRatio.Width, mediaView.AspectRatio.Height
+	}
+	if mediaView.Playlist == "" {
+		return 0, 0
+	}
+
+	variants, err := p.Bluesky.VideoPlaylist(mediaView.Playlist)
+	if err != nil {
+		p.Logger.WithField("playlist_url", mediaView.Playlist).Warnf("failed to get video playlist, err: %v", err)
+		return 0, 0
+	}
+	if len(variants) == 0 {
+		return 0, 0
+	}
+
+	return variants[0].Width, variants[0].Height
+}
+
+// pdsEndpoint 返回作者数据所在的 PDS 地址，无法解析时返回空字符串
+func (p *Provider) pdsEndpoint(did string) string {
+	didDocument, err := p.Bluesky.ResolveDID(did)
+	if err != nil {
+		p.Logger.WithField("did", did).Warnf("failed to resolve did, err: %v", err)
+		return ""
+	}
+
+	return didDocument.PDSEndpoint()
+}
+
+func (p *Provider) RenderCaption(post *publishing.Post, labels []string) string {
+	authorInfo := "未知"
+	if post.Author.Handle != "" {
+		authorInfo = fmt.Sprintf(`<a href="%s">%s (@%s)</a>`, post.Author.URL, html.EscapeString(post.Author.Name), post.Author.Handle)
+	}
+
+	var content string
+	blueskyPost, ok := post.Raw.(*bluesky_public_types.PostView)
+	if ok && blueskyPost.Record != nil {
+		content = blueskyPost.Record.TextInHTML()
+	}
+
+	return publishing.FormatCaption(authorInfo, content, labels, "Bluesky", post.URL)
+}
+
+func (p *Provider) Download(link string, w io.Writer) error {
+	return p.Bluesky.Download(link, w)
+}
+
+// postRKey 返回帖子 ID 中的 rkey
+func postRKey(post *publishing.Post) string {
+	_, rkey, _ := strings.Cut(post.ID, "/")
+	return rkey
+}
+
+// originalFileName 返回讨论群组中原图、原视频的文件名
+func originalFileName(post *publishing.Post, index int, ext string) string {
+	return fmt.Sprintf("bluesky-by-%s-%s-%d%s", post.Author.Handle, postRKey(post), index, ext)
+}
+
+// mimeTypeExt 返回原始文件的扩展名，未知的类型返回 fallback
+func mimeTypeExt(mimeType string, fallback string) string {
+	switch mimeType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/webp":
+		return ".webp"
+	case "image/gif":
+		return ".gif"
+	case "video/mp4":
+		return ".mp4"
+	case "video/quicktime":
+		return ".mov"
+	case "video/webm":
+		return ".webm"
+	default:
+		return fallback
+	}
+}
+
+var (
+	BlueskyPostLinkRegexp = regexp.MustCompile(`https://bsky.app/profile/([^/]+)/post/([A-Za-z0-9._:~-]+)`)
+)
+
+func PostIDFromText(text string) string {
+	matches := BlueskyPostLinkRegexp.FindStringSubmatch(text)
+	if len(matches) != 3 {
+		return ""
+	}
+
+	return matches[1] + "/" + matches[2]
+}
@@ -0,0 +1,132 @@
+package bluesky
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	"github.com/nekomeowww/perobot/internal/lib"
+	bluesky_public_types "github.com/nekomeowww/perobot/pkg/bluesky/public/types"
+)
+
+const testVideoPostThread = `{
+	"thread": {
+		"$type": "app.bsky.feed.defs#threadViewPost",
+		"post": {
+			"uri": "at://did:plc:alice/app.bsky.feed.post/3kvideo",
+			"cid": "bafyreipost",
+			"author": { "did": "did:plc:alice", "handle": "alice.bsky.social", "displayName": "" },
+			"record": {
+				"$type": "app.bsky.feed.post",
+				"text": "timelapse <b>",
+				"embed": {
+					"$type": "app.bsky.embed.video",
+					"video": { "$type": "blob", "ref": { "$link": "bafkreivideo" }, "mimeType": "video/mp4", "size": 4096 }
+				}
+			},
+			"embed": {
+				"$type": "app.bsky.embed.video#view",
+				"cid": "bafkreivideo",
+				"playlist": "https://video.bsky.app/watch/did%3Aplc%3Aalice/bafkreivideo/playlist.m3u8",
+				"thumbnail": "https://video.bsky.app/watch/did%3Aplc%3Aalice/bafkreivideo/thumbnail.jpg"
+			},
+			"labels": []
+		}
+	}
+}`
+
+// fakeClient 返回固定帖子的 Client，请求和解析由 bluesky_public 的测试覆盖
+type fakeClient struct {
+	t *testing.T
+}
+
+func (c *fakeClient) GetPostThread(uri string) (*bluesky_public_types.GetPostThreadResp, error) {
+	if uri != "at://alice.bsky.social/app.bsky.feed.post/3kvideo" {
+		return &bluesky_public_types.GetPostThreadResp{
+			Thread: &bluesky_public_types.ThreadViewPost{Type: bluesky_public_types.ThreadTypeNotFoundPost, URI: uri, NotFound: true},
+		}, nil
+	}
+
+	var resp bluesky_public_types.GetPostThreadResp
+	require.NoError(c.t, json.Unmarshal([]byte(testVideoPostThread), &resp))
+
+	return &resp, nil
+}
+
+func (c *fakeClient) ResolveDID(did string) (*bluesky_public_types.DIDDocument, error) {
+	return &bluesky_public_types.DIDDocument{
+		ID:      did,
+		Service: []*bluesky_public_types.DIDService{{ID: "#atproto_pds", Type: "AtprotoPersonalDataServer", ServiceEndpoint: "https://pds.example.com"}},
+	}, nil
+}
+
+func (c *fakeClient) VideoPlaylist(playlistURL string) ([]bluesky_public_types.PlaylistVariant, error) {
+	return []bluesky_public_types.PlaylistVariant{
+		{Bandwidth: 2850000, Width: 1280, Height: 720, URL: "720p/video.m3u8"},
+		{Bandwidth: 1088000, Width: 640, Height: 360, URL: "360p/video.m3u8"},
+	}, nil
+}
+
+func (c *fakeClient) Download(link string, w io.Writer) error {
+	return errors.New("not implemented")
+}
+
+func TestPostIDFromText(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("alice.bsky.social/3kabcde", PostIDFromText("https://bsky.app/profile/alice.bsky.social/post/3kabcde"))
+	assert.Equal("did:plc:alice/3kabcde", PostIDFromText("https://bsky.app/profile/did:plc:alice/post/3kabcde"))
+	assert.Empty(PostIDFromText("https://bsky.app/profile/alice.bsky.social"))
+}
+
+func TestMatch(t *testing.T) {
+	assert := assert.New(t)
+
+	provider := &Provider{}
+
+	assert.Equal("alice.bsky.social/3kabcde", provider.Match("https://bsky.app/profile/alice.bsky.social/post/3kabcde?ref_src=embed"))
+	assert.Equal("alice.bsky.social/3kabcde", provider.Match("https://Bsky.App/profile/alice.bsky.social/post/3kabcde#reply"))
+	assert.Equal("did:plc:alice/3kabcde", provider.Match("https://bsky.app/profile/did:plc:alice/post/3kabcde"))
+	assert.Empty(provider.Match("https://bsky.app/search?q=https://bsky.app/profile/alice.bsky.social/post/3kabcde"))
+}
+
+func TestFetchPostAndListMedia(t *testing.T) {
+	provider := &Provider{Logger: lib.NewLogger()(), Bluesky: &fakeClient{t: t}}
+
+	postID := provider.Match("https://bsky.app/profile/alice.bsky.social/post/3kvideo?ref=share")
+	require.Equal(t, "alice.bsky.social/3kvideo", postID)
+
+	post, err := provider.FetchPost(postID)
+	require.NoError(t, err)
+	require.NotNil(t, post)
+	assert.Equal(t, "https://bsky.app/profile/alice.bsky.social/post/3kvideo", post.URL)
+	assert.Equal(t, "alice.bsky.social", post.Author.Name)
+	assert.False(t, post.Sensitive)
+
+	medias, err := provider.ListMedia(post)
+	require.NoError(t, err)
+	require.Len(t, medias, 1)
+
+	blobURL := "https://pds.example.com/xrpc/com.atproto.sync.getBlob?cid=bafkreivideo&did=did%3Aplc%3Aalice"
+	assert.Equal(t, publishing.MediaTypeVideo, medias[0].Type)
+	assert.Equal(t, []string{blobURL}, medias[0].PreviewURLs)
+	assert.Equal(t, blobURL, medias[0].OriginalURL)
+	assert.Equal(t, "bluesky-by-alice.bsky.social-3kvideo-0.mp4", medias[0].OriginalFileName)
+	assert.Equal(t, 1280, medias[0].Width)
+	assert.Equal(t, 720, medias[0].Height)
+
+	assert.Equal(t, ""+
+		`<a href="https://bsky.app/profile/alice.bsky.social">alice.bsky.social (@alice.bsky.social)</a>：`+"\n\n"+
+		"timelapse &lt;b&gt;\n\n"+
+		`来自 <a href="https://bsky.app/profile/alice.bsky.social/post/3kvideo">Bluesky</a>`,
+		provider.RenderCaption(post, nil))
+
+	post, err = provider.FetchPost("alice.bsky.social/deleted")
+	require.NoError(t, err)
+	assert.Nil(t, post)
+}
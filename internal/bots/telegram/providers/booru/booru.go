@@ -0,0 +1,336 @@
+package booru
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/samber/lo"
+	"go.uber.org/fx"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	"github.com/nekomeowww/perobot/internal/models/published"
+	"github.com/nekomeowww/perobot/internal/thirdparty"
+	"github.com/nekomeowww/perobot/pkg/booru"
+	"github.com/nekomeowww/perobot/pkg/logger"
+)
+
+// Kind 站点所使用的 API
+type Kind string
+
+const (
+	KindDanbooru Kind = "danbooru"
+	KindGelbooru Kind = "gelbooru"
+	KindMoebooru Kind = "moebooru"
+)
+
+// Site 支持的站点
+type Site struct {
+	Kind Kind
+	// Name 说明文字中显示的站点名称
+	Name string
+}
+
+// Sites 支持的站点，键为站点的域名
+var Sites = map[string]Site{
+	"danbooru.donmai.us":  {Kind: KindDanbooru, Name: "Danbooru"},
+	"safebooru.donmai.us": {Kind: KindDanbooru, Name: "Safebooru"},
+	"gelbooru.com":        {Kind: KindGelbooru, Name: "Gelbooru"},
+	"yande.re":            {Kind: KindMoebooru, Name: "yande.re"},
+	"konachan.com":        {Kind: KindMoebooru, Name: "Konachan"},
+	"konachan.net":        {Kind: KindMoebooru, Name: "Konachan"},
+}
+
+// maxHashtagsPerCategory 每个分类最多转换为话题标签的标签数，避免说明文字超出长度限制
+const maxHashtagsPerCategory = 10
+
+// aiGeneratedTags 各个站点用于标记 AI 生成或 AI 辅助作品的标签
+var aiGeneratedTags = []string{"ai-generated", "ai_generated", "ai-assisted", "ai_assisted"}
+
+type NewProviderParam struct {
+	fx.In
+
+	Logger *logger.Logger
+	Booru  *thirdparty.Booru
+}
+
+// Provider 发布 Danbooru、Gelbooru 和 Moebooru 类站点（yande.re、Konachan）上的作品
+type Provider struct {
+	Logger *logger.Logger
+	Booru  *thirdparty.Booru
+}
+
+func NewProvider() func(param NewProviderParam) *Provider {
+	return func(param NewProviderParam) *Provider {
+		return &Provider{
+			Logger: param.Logger,
+			Booru:  param.Booru,
+		}
+	}
+}
+
+func (p *Provider) Source() published.Source {
+	return published.SourceBooru
+}
+
+// Match 返回 <站点域名>/<ID> 形式的作品 ID
+func (p *Provider) Match(link string) string {
+	host, id := PostFromText(link)
+	if host == "" {
+		return ""
+	}
+
+	return host + "/" + id
+}
+
+func (p *Provider) FetchPost(postID string) (*publishing.Post, error) {
+	host, id, site, err := splitPostID(postID)
+	if err != nil {
+		return nil, err
+	}
+
+	siteURL := "https://" + host
+
+	var booruPost *booru.Post
+
+	switch site.Kind {
+	case KindDanbooru:
+		booruPost, err = p.Booru.DanbooruPost(siteURL, id)
+	case KindGelbooru:
+		booruPost, err = p.Booru.GelbooruPost(siteURL, id)
+	case KindMoebooru:
+		booruPost, err = p.Booru.MoebooruPost(siteURL, id)
+	default:
+		return nil, fmt.Errorf("unknown booru site kind: %s", site.Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if booruPost == nil {
+		return nil, nil
+	}
+
+	return newPost(postID, host, site, booruPost), nil
+}
+
+// newPost 作者为作品的画师标签，分级为 questionable 和 explicit 的作品视为敏感内容
+func newPost(postID string, host string, site Site, booruPost *booru.Post) *publishing.Post {
+	post := &publishing.Post{
+		ID:  postID,
+		URL: postURL(host, site, booruPost.ID),
+		Metadata: publishing.Metadata{
+			AIGenerated: booruPost.HasAnyTag(aiGeneratedTags...),
+		},
+		Sensitive: booruPost.Rating.IsSensitive(),
+		Raw:       booruPost,
+	}
+	if post.Sensitive {
+		post.SensitiveLabel = string(booruPost.Rating)
+	}
+
+	artists := booruPost.TagsOf(booru.TagCategoryArtist)
+	if len(artists) > 0 {
+		post.Author = publishing.Author{
+			Name:   strings.Join(lo.Map(artists, func(tag string, _ int) string { return tagName(tag) }), ", "),
+			Handle: artists[0],
+			URL:    tagSearchURL(host, site, artists[0]),
+		}
+	}
+
+	return post
+}
+
+// ListMedia 每个作品只有一个文件，WebM、Flash 和压缩包等无法在 Telegram 中发送的文件会被跳过
+func (p *Provider) ListMedia(post *publishing.Post) ([]*publishing.Media, error) {
+	booruPost, ok := post.Raw.(*booru.Post)
+	if !ok {
+		return nil, errors.New("post is not a booru post")
+	}
+
+	var mediaType publishing.MediaType
+	switch strings.ToLower(booruPost.FileExt) {
+	case "jpg", "jpeg", "png", "webp":
+		mediaType = publishing.MediaTypePhoto
+	case "gif":
+		mediaType = publishing.MediaTypeAnimation
+	case "mp4":
+		mediaType = publishing.MediaTypeVideo
+	default:
+		p.Logger.WithField("booru_post_id", post.ID).Warnf("unsupported file extension %s, skipping...", booruPost.FileExt)
+		return []*publishing.Media{}, nil
+	}
+
+	if booruPost.FileURL == "" {
+		p.Logger.WithField("booru_post_id", post.ID).Warn("original file is hidden by the site, skipping...")
+		return []*publishing.Media{}, nil
+	}
+
+	_, id, _, _ := splitPostID(post.ID)
+
+	media := &publishing.Media{
+		Type:             mediaType,
+		CacheKey:         booruPost.FileURL,
+		PreviewURLs:      []string{booruPost.FileURL},
+		OriginalURL:      booruPost.FileURL,
+		FileName:         fmt.Sprintf("%s.%s", id, booruPost.FileExt),
+		OriginalFileName: fmt.Sprintf("booru-by-%s-%s.%s", lo.Ternary(post.Author.Handle != "", publishing.Hashtag(post.Author.Handle), "unknown"), id, booruPost.FileExt),
+		Width:            booruPost.Width,
+		Height:           booruPost.Height,
+	}
+	if mediaType == publishing.MediaTypePhoto {
+		// 原图过大无法发送时依次尝试站点提供的缩小版本
+		media.PreviewURLs = append(media.PreviewURLs, booruPost.SampleURLs...)
+	} else {
+		media.PosterURL = booruPost.PreviewURL
+	}
+
+	return []*publishing.Media{media}, nil
+}
+
+// RenderCaption 说明文字中包含作品的出处，以及画师、角色和作品的话题标签
+func (p *Provider) RenderCaption(post *publishing.Post, labels []string) string {
+	authorInfo := "未知"
+	if post.Author.Name != "" {
+		authorInfo = fmt.Sprintf(`<a href="%s">%s</a>`, post.Author.URL, html.EscapeString(post.Author.Name))
+	}
+
+	var content string
+	tags := make([]string, 0)
+
+	booruPost, ok := post.Raw.(*booru.Post)
+	if ok {
+		content = sourceInHTML(booruPost.Source)
+
+		for _, category := range []booru.TagCategory{booru.TagCategoryArtist, booru.TagCategoryCharacter, booru.TagCategoryCopyright} {
+			categoryTags := booruPost.TagsOf(category)
+			if len(categoryTags) > maxHashtagsPerCategory {
+				categoryTags = categoryTags[:maxHashtagsPerCategory]
+			}
+
+			for _, tag := range categoryTags {
+				hashtag := publishing.Hashtag(tag)
+				if hashtag == "" {
+					continue
+				}
+
+				tags = append(tags, "#"+hashtag)
+			}
+		}
+	}
+
+	tags = append(lo.Uniq(tags), labels...)
+
+	_, _, site, _ := splitPostID(post.ID)
+
+	return publishing.FormatCaption(authorInfo, content, tags, site.Name, post.URL)
+}
+
+func (p *Provider) Download(link string, w io.Writer) error {
+	return p.Booru.Download(link, w)
+}
+
+// sourceInHTML 出处为链接时显示为指向出处的链接，否则显示原本的文字
+func sourceInHTML(source string) string {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return ""
+	}
+
+	parsedURL, err := url.Parse(source)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return "原始出处：" + html.EscapeString(source)
+	}
+
+	return fmt.Sprintf(`原始出处：<a href="%s">%s</a>`, html.EscapeString(source), html.EscapeString(parsedURL.Host))
+}
+
+// tagName 返回标签显示用的名称，站点使用 _ 代替标签中的空格
+func tagName(tag string) string {
+	return strings.ReplaceAll(tag, "_", " ")
+}
+
+// postURL 返回作品在站点上的链接
+func postURL(host string, site Site, id string) string {
+	switch site.Kind {
+	case KindGelbooru:
+		return fmt.Sprintf("https://%s/index.php?page=post&s=view&id=%s", host, id)
+	case KindMoebooru:
+		return fmt.Sprintf("https://%s/post/show/%s", host, id)
+	default:
+		return fmt.Sprintf("https://%s/posts/%s", host, id)
+	}
+}
+
+// tagSearchURL 返回站点上搜索标签 tag 的链接
+func tagSearchURL(host string, site Site, tag string) string {
+	switch site.Kind {
+	case KindGelbooru:
+		return fmt.Sprintf("https://%s/index.php?page=post&s=list&tags=%s", host, url.QueryEscape(tag))
+	case KindMoebooru:
+		return fmt.Sprintf("https://%s/post?tags=%s", host, url.QueryEscape(tag))
+	default:
+		return fmt.Sprintf("https://%s/posts?tags=%s", host, url.QueryEscape(tag))
+	}
+}
+
+func splitPostID(postID string) (string, string, Site, error) {
+	host, id, ok := strings.Cut(postID, "/")
+	if !ok {
+		return "", "", Site{}, fmt.Errorf("invalid booru post id: %s", postID)
+	}
+
+	site, ok := Sites[host]
+	if !ok {
+		return "", "", Site{}, fmt.Errorf("unsupported booru site: %s", host)
+	}
+
+	return host, id, site, nil
+}
+
+var (
+	// DanbooruPostPathRegexp Danbooru 的作品链接 https://danbooru.donmai.us/posts/<id>
+	DanbooruPostPathRegexp = regexp.MustCompile(`^/posts/(\d+)$`)
+	// MoebooruPostPathRegexp Moebooru 的作品链接 https://yande.re/post/show/<id>，ID 后可能带有标签
+	MoebooruPostPathRegexp = regexp.MustCompile(`^/post/show/(\d+)(?:/.*)?$`)
+	// GelbooruPostIDRegexp Gelbooru 的作品链接 https://gelbooru.com/index.php?page=post&s=view&id=<id> 中的 ID
+	GelbooruPostIDRegexp = regexp.MustCompile(`^\d+$`)
+)
+
+// PostFromText 返回链接所在站点的域名和作品 ID，不是支持的站点上的作品链接时返回空字符串
+func PostFromText(text string) (string, string) {
+	parsedURL, err := url.Parse(strings.TrimSpace(text))
+	if err != nil || parsedURL.Scheme != "https" {
+		return "", ""
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(parsedURL.Host), "www.")
+
+	site, ok := Sites[host]
+	if !ok {
+		return "", ""
+	}
+
+	switch site.Kind {
+	case KindDanbooru:
+		matches := DanbooruPostPathRegexp.FindStringSubmatch(parsedURL.Path)
+		if len(matches) == 2 {
+			return host, matches[1]
+		}
+	case KindMoebooru:
+		matches := MoebooruPostPathRegexp.FindStringSubmatch(parsedURL.Path)
+		if len(matches) == 2 {
+			return host, matches[1]
+		}
+	case KindGelbooru:
+		query := parsedURL.Query()
+		if parsedURL.Path == "/index.php" && query.Get("page") == "post" && query.Get("s") == "view" && GelbooruPostIDRegexp.MatchString(query.Get("id")) {
+			return host, query.Get("id")
+		}
+	}
+
+	return "", ""
+}
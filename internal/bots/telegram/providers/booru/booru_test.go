@@ -0,0 +1,104 @@
+package booru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	"github.com/nekomeowww/perobot/internal/lib"
+	"github.com/nekomeowww/perobot/pkg/booru"
+)
+
+func TestMatch(t *testing.T) {
+	provider := &Provider{Logger: lib.NewLogger()()}
+
+	assert := assert.New(t)
+
+	assert.Equal("danbooru.donmai.us/1234567", provider.Match("https://danbooru.donmai.us/posts/1234567?q=1girl"))
+	assert.Equal("safebooru.donmai.us/1234567", provider.Match("https://safebooru.donmai.us/posts/1234567"))
+	assert.Equal("gelbooru.com/7654321", provider.Match("https://gelbooru.com/index.php?page=post&s=view&id=7654321&tags=all"))
+	assert.Equal("gelbooru.com/7654321", provider.Match("https://www.gelbooru.com/index.php?id=7654321&page=post&s=view"))
+	assert.Equal("yande.re/1000", provider.Match("https://yande.re/post/show/1000"))
+	assert.Equal("konachan.com/2000", provider.Match("https://konachan.com/post/show/2000/original-sky"))
+	assert.Empty(provider.Match("https://danbooru.donmai.us/posts?tags=1girl"))
+	assert.Empty(provider.Match("https://gelbooru.com/index.php?page=post&s=list&tags=all"))
+	assert.Empty(provider.Match("https://example.com/posts/1234567"))
+}
+
+func TestListMediaAndRenderCaption(t *testing.T) {
+	provider := &Provider{Logger: lib.NewLogger()()}
+
+	booruPost := &booru.Post{
+		ID:         "1234567",
+		Rating:     booru.RatingExplicit,
+		FileURL:    "https://cdn.donmai.us/original/ab/cd/abcd.png",
+		SampleURLs: []string{"https://cdn.donmai.us/sample/ab/cd/sample-abcd.jpg"},
+		PreviewURL: "https://cdn.donmai.us/180x180/ab/cd/abcd.jpg",
+		FileExt:    "png",
+		Width:      2000,
+		Height:     3000,
+		Source:     "https://www.pixiv.net/artworks/100000000",
+		Tags: map[booru.TagCategory][]string{
+			booru.TagCategoryArtist:    {"alice_(artist)"},
+			booru.TagCategoryCharacter: {"hatsune_miku"},
+			booru.TagCategoryCopyright: {"vocaloid"},
+			booru.TagCategoryGeneral:   {"1girl"},
+		},
+	}
+
+	post := newPost("danbooru.donmai.us/1234567", "danbooru.donmai.us", Sites["danbooru.donmai.us"], booruPost)
+	assert.Equal(t, "https://danbooru.donmai.us/posts/1234567", post.URL)
+	assert.True(t, post.Sensitive)
+	assert.Equal(t, "explicit", post.SensitiveLabel)
+	assert.Equal(t, "alice (artist)", post.Author.Name)
+	assert.Equal(t, "https://danbooru.donmai.us/posts?tags=alice_%28artist%29", post.Author.URL)
+
+	medias, err := provider.ListMedia(post)
+	require.NoError(t, err)
+	require.Len(t, medias, 1)
+	assert.Equal(t, publishing.MediaTypePhoto, medias[0].Type)
+	assert.Equal(t, []string{booruPost.FileURL, booruPost.SampleURLs[0]}, medias[0].PreviewURLs)
+	assert.Equal(t, booruPost.FileURL, medias[0].OriginalURL)
+	assert.Equal(t, "booru-by-alice_artist-1234567.png", medias[0].OriginalFileName)
+
+	assert.Equal(t, ""+
+		`<a href="https://danbooru.donmai.us/posts?tags=alice_%28artist%29">alice (artist)</a>：`+"\n\n"+
+		`原始出处：<a href="https://www.pixiv.net/artworks/100000000">www.pixiv.net</a>`+"\n\n"+
+		"#alice_artist #hatsune_miku #vocaloid #NSFW\n\n"+
+		`来自 <a href="https://danbooru.donmai.us/posts/1234567">Danbooru</a>`,
+		provider.RenderCaption(post, []string{"#NSFW"}))
+
+	booruPost.FileExt = "webm"
+
+	medias, err = provider.ListMedia(post)
+	require.NoError(t, err)
+	assert.Empty(t, medias)
+}
+
+func TestAIGenerated(t *testing.T) {
+	booruPost := &booru.Post{
+		ID:     "1234567",
+		Rating: booru.RatingGeneral,
+		Tags: map[booru.TagCategory][]string{
+			booru.TagCategoryGeneral: {"1girl"},
+		},
+	}
+
+	post := newPost("danbooru.donmai.us/1234567", "danbooru.donmai.us", Sites["danbooru.donmai.us"], booruPost)
+	assert.False(t, post.Metadata.AIGenerated)
+
+	booruPost.Tags[booru.TagCategoryMeta] = []string{"AI-generated"}
+
+	post = newPost("danbooru.donmai.us/1234567", "danbooru.donmai.us", Sites["danbooru.donmai.us"], booruPost)
+	assert.True(t, post.Metadata.AIGenerated)
+}
+
+func TestSourceInHTML(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(sourceInHTML(""))
+	assert.Equal("原始出处：Comic Market 100 &lt;C100&gt;", sourceInHTML("Comic Market 100 <C100>"))
+	assert.Equal(`原始出处：<a href="https://twitter.com/alice/status/1">twitter.com</a>`, sourceInHTML("https://twitter.com/alice/status/1"))
+}
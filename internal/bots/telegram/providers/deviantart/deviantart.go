@@ -0,0 +1,204 @@
+package deviantart
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+	"go.uber.org/fx"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	"github.com/nekomeowww/perobot/internal/models/published"
+	"github.com/nekomeowww/perobot/internal/thirdparty"
+	deviantart_public_types "github.com/nekomeowww/perobot/pkg/deviantart/public/types"
+	"github.com/nekomeowww/perobot/pkg/logger"
+)
+
+type NewProviderParam struct {
+	fx.In
+
+	Logger     *logger.Logger
+	DeviantArt *thirdparty.DeviantArtPublic
+}
+
+// Client Provider 所使用的 DeviantArt 接口，由 thirdparty.DeviantArtPublic 实现
+type Client interface {
+	GetOEmbed(deviationURL string) (*deviantart_public_types.OEmbed, error)
+	Download(link string, w io.Writer) error
+}
+
+// Provider 发布 DeviantArt 的图片作品
+type Provider struct {
+	Logger     *logger.Logger
+	DeviantArt Client
+}
+
+func NewProvider() func(param NewProviderParam) *Provider {
+	return func(param NewProviderParam) *Provider {
+		return &Provider{
+			Logger:     param.Logger,
+			DeviantArt: param.DeviantArt,
+		}
+	}
+}
+
+func (p *Provider) Source() published.Source {
+	return published.SourceDeviantArt
+}
+
+// Match 返回作品链接末尾的数字 ID，作品改名或作者改名后链接会变化，数字 ID 不会
+func (p *Provider) Match(link string) string {
+	return DeviationIDFromText(publishing.LinkWithoutQuery(link))
+}
+
+// FetchPost 通过 fav.me 短链接查询作品的 oEmbed 信息，短链接由数字 ID 的 36 进制表示组成，会重定向到作品页面
+func (p *Provider) FetchPost(postID string) (*publishing.Post, error) {
+	deviationID, err := strconv.ParseInt(postID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deviantart deviation id: %s", postID)
+	}
+
+	deviationURL := "https://fav.me/d" + strconv.FormatInt(deviationID, 36)
+
+	oEmbed, err := p.DeviantArt.GetOEmbed(deviationURL)
+	if err != nil {
+		return nil, err
+	}
+	if oEmbed == nil {
+		return nil, nil
+	}
+
+	username := usernameFromAuthorURL(oEmbed.AuthorURL)
+
+	post := &publishing.Post{
+		ID:  postID,
+		URL: deviationURL,
+		Author: publishing.Author{
+			Name:   lo.Ternary(oEmbed.AuthorName != "", oEmbed.AuthorName, username),
+			Handle: username,
+			URL:    oEmbed.AuthorURL,
+		},
+		Sensitive: oEmbed.IsAdult(),
+		Raw:       oEmbed,
+	}
+	if post.Sensitive {
+		post.SensitiveLabel = "Mature"
+	}
+
+	return post, nil
+}
+
+// ListMedia 优先使用未经缩放的原图，作品不允许下载原图时使用 fullview 图片，文学等非图片作品没有媒体
+func (p *Provider) ListMedia(post *publishing.Post) ([]*publishing.Media, error) {
+	oEmbed, ok := post.Raw.(*deviantart_public_types.OEmbed)
+	if !ok {
+		return nil, errors.New("post is not a deviantart deviation")
+	}
+	if !oEmbed.IsPhoto() {
+		p.Logger.WithField("deviantart_deviation_id", post.ID).Warnf("deviation of type %s has no image, skipping...", oEmbed.Type)
+		return []*publishing.Media{}, nil
+	}
+
+	fullSizeURL := oEmbed.FullSizeURL()
+	ext := "." + lo.Ternary(oEmbed.ImageType != "", oEmbed.ImageType, "jpg")
+	width, height := oEmbed.Size()
+
+	photo := &publishing.Media{
+		Type:             publishing.MediaTypePhoto,
+		CacheKey:         oEmbed.URL,
+		PreviewURLs:      lo.Uniq([]string{fullSizeURL, oEmbed.URL}),
+		OriginalURL:      fullSizeURL,
+		FileName:         post.ID + ext,
+		OriginalFileName: fmt.Sprintf("deviantart-by-%s-%s%s", lo.Ternary(post.Author.Handle != "", post.Author.Handle, "unknown"), post.ID, ext),
+		Width:            width,
+		Height:           height,
+	}
+	if oEmbed.ImageType != "gif" || fullSizeURL == oEmbed.URL {
+		return []*publishing.Media{photo}, nil
+	}
+
+	// fullview 图片是 GIF 的静态版本，只有原图是动图，原图无法下载时发布 fullview 图片
+	return []*publishing.Media{
+		{
+			Type:             publishing.MediaTypeAnimation,
+			CacheKey:         fullSizeURL,
+			PreviewURLs:      []string{fullSizeURL},
+			OriginalURL:      fullSizeURL,
+			FileName:         photo.FileName,
+			OriginalFileName: photo.OriginalFileName,
+			Width:            width,
+			Height:           height,
+			PosterURL:        oEmbed.URL,
+			Fallback:         []*publishing.Media{photo},
+		},
+	}, nil
+}
+
+func (p *Provider) RenderCaption(post *publishing.Post, labels []string) string {
+	authorInfo := "未知"
+	if post.Author.Name != "" && post.Author.URL != "" {
+		authorInfo = fmt.Sprintf(`<a href="%s">%s</a>`, post.Author.URL, html.EscapeString(post.Author.Name))
+	}
+
+	var title string
+	tags := make([]string, 0)
+
+	oEmbed, ok := post.Raw.(*deviantart_public_types.OEmbed)
+	if ok {
+		title = html.EscapeString(oEmbed.Title)
+
+		for _, tag := range oEmbed.TagList() {
+			hashtag := publishing.Hashtag(tag)
+			if hashtag == "" {
+				continue
+			}
+
+			tags = append(tags, "#"+hashtag)
+		}
+	}
+
+	tags = append(lo.Uniq(tags), labels...)
+
+	return publishing.FormatCaption(authorInfo, title, tags, "DeviantArt", post.URL)
+}
+
+func (p *Provider) Download(link string, w io.Writer) error {
+	return p.DeviantArt.Download(link, w)
+}
+
+// usernameFromAuthorURL 返回作者主页链接 https://www.deviantart.com/<user> 中的用户名，无法解析时返回空字符串
+func usernameFromAuthorURL(authorURL string) string {
+	parsedURL, err := url.Parse(authorURL)
+	if err != nil {
+		return ""
+	}
+
+	return strings.ToLower(strings.Trim(parsedURL.Path, "/"))
+}
+
+var (
+	// DeviationLinkRegexp 作品链接 https://www.deviantart.com/<user>/art/<name>-<id>，名称可以省略
+	DeviationLinkRegexp = regexp.MustCompile(`^https://(?:www\.)?deviantart\.com/[A-Za-z0-9_-]+/art/(?:[A-Za-z0-9-]*-)?(\d+)$`)
+	// LegacyDeviationLinkRegexp 旧版的作品链接 https://<user>.deviantart.com/art/<name>-<id>
+	LegacyDeviationLinkRegexp = regexp.MustCompile(`^https://[A-Za-z0-9-]+\.deviantart\.com/art/(?:[A-Za-z0-9-]*-)?(\d+)$`)
+	// DeviationIDLinkRegexp 只包含数字 ID 的作品链接 https://www.deviantart.com/deviation/<id>
+	DeviationIDLinkRegexp = regexp.MustCompile(`^https://(?:www\.)?deviantart\.com/deviation/(\d+)$`)
+)
+
+// DeviationIDFromText 返回作品链接末尾的数字 ID，不是作品链接时返回空字符串
+func DeviationIDFromText(text string) string {
+	for _, linkRegexp := range []*regexp.Regexp{DeviationLinkRegexp, LegacyDeviationLinkRegexp, DeviationIDLinkRegexp} {
+		matches := linkRegexp.FindStringSubmatch(text)
+		if len(matches) == 2 {
+			return matches[1]
+		}
+	}
+
+	return ""
+}
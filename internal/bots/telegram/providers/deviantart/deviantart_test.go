@@ -0,0 +1,114 @@
+package deviantart
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	"github.com/nekomeowww/perobot/internal/lib"
+	deviantart_public_types "github.com/nekomeowww/perobot/pkg/deviantart/public/types"
+)
+
+const testOEmbed = `{
+	"type": "photo",
+	"title": "Castle <WIP>",
+	"url": "https://images-wixmp-0.wixmp.com/f/uuid/castle.gif/v1/fill/w_1024,h_1280,q_80,strp/castle_by_alice.jpg?token=abc",
+	"author_name": "Alice",
+	"author_url": "https://www.deviantart.com/alice",
+	"safety": "adult",
+	"tags": "fantasy, digital art",
+	"width": 1024,
+	"height": 1280,
+	"imagetype": "gif"
+}`
+
+// fakeClient 返回固定作品的 Client，请求和解析由 deviantart_public 的测试覆盖
+type fakeClient struct {
+	t *testing.T
+}
+
+func (c *fakeClient) GetOEmbed(deviationURL string) (*deviantart_public_types.OEmbed, error) {
+	if deviationURL != "https://fav.me/d21i3v9" {
+		return nil, nil
+	}
+
+	var oEmbed deviantart_public_types.OEmbed
+	require.NoError(c.t, json.Unmarshal([]byte(testOEmbed), &oEmbed))
+
+	return &oEmbed, nil
+}
+
+func (c *fakeClient) Download(link string, w io.Writer) error {
+	return errors.New("not implemented")
+}
+
+func TestDeviationIDFromText(t *testing.T) {
+	assert := assert.New(t)
+
+	// 改名前后的链接指向同一个作品
+	assert.Equal("123456789", DeviationIDFromText("https://www.deviantart.com/Alice/art/Castle-123456789"))
+	assert.Equal("123456789", DeviationIDFromText("https://www.deviantart.com/alice-renamed/art/Castle-at-Night-123456789"))
+	assert.Equal("123456789", DeviationIDFromText("https://www.deviantart.com/alice/art/123456789"))
+	assert.Equal("123456789", DeviationIDFromText("https://alice.deviantart.com/art/Castle-123456789"))
+	assert.Equal("123456789", DeviationIDFromText("https://www.deviantart.com/deviation/123456789"))
+	// 名称中的数字不是作品 ID
+	assert.Equal("123456789", DeviationIDFromText("https://www.deviantart.com/alice/art/Castle-2-123456789"))
+	assert.Empty(DeviationIDFromText("https://www.deviantart.com/alice/art/Castle"))
+	assert.Empty(DeviationIDFromText("https://www.deviantart.com/alice/gallery"))
+}
+
+func TestMatch(t *testing.T) {
+	assert := assert.New(t)
+
+	provider := &Provider{}
+
+	assert.Equal("123456789", provider.Match("https://www.deviantart.com/alice/art/Castle-123456789?utm_campaign=share"))
+	assert.Equal("123456789", provider.Match("https://Alice.DeviantArt.com/art/Castle-123456789#comments"))
+	assert.Equal("123456789", provider.Match("https://www.deviantart.com/deviation/123456789?view=full"))
+	assert.Empty(provider.Match("https://www.deviantart.com/alice/gallery?catpath=/"))
+}
+
+func TestFetchPostAndListMedia(t *testing.T) {
+	provider := &Provider{Logger: lib.NewLogger()(), DeviantArt: &fakeClient{t: t}}
+
+	postID := provider.Match("https://www.deviantart.com/alice/art/Castle-123456789?utm_source=share")
+	require.Equal(t, "123456789", postID)
+
+	post, err := provider.FetchPost(postID)
+	require.NoError(t, err)
+	require.NotNil(t, post)
+	assert.True(t, post.Sensitive)
+	assert.Equal(t, "Mature", post.SensitiveLabel)
+
+	medias, err := provider.ListMedia(post)
+	require.NoError(t, err)
+	require.Len(t, medias, 1)
+	assert.Equal(t, publishing.MediaTypeAnimation, medias[0].Type)
+	assert.Equal(t, "https://images-wixmp-0.wixmp.com/f/uuid/castle.gif?token=abc", medias[0].OriginalURL)
+	assert.Equal(t, "deviantart-by-alice-123456789.gif", medias[0].OriginalFileName)
+	require.Len(t, medias[0].Fallback, 1)
+	assert.Equal(t, publishing.MediaTypePhoto, medias[0].Fallback[0].Type)
+	assert.Equal(t, []string{
+		"https://images-wixmp-0.wixmp.com/f/uuid/castle.gif?token=abc",
+		"https://images-wixmp-0.wixmp.com/f/uuid/castle.gif/v1/fill/w_1024,h_1280,q_80,strp/castle_by_alice.jpg?token=abc",
+	}, medias[0].Fallback[0].PreviewURLs)
+
+	assert.Equal(t, ""+
+		`<a href="https://www.deviantart.com/alice">Alice</a>：`+"\n\n"+
+		"Castle &lt;WIP&gt;\n\n"+
+		"#fantasy #digital_art\n\n"+
+		`来自 <a href="https://fav.me/d21i3v9">DeviantArt</a>`,
+		provider.RenderCaption(post, nil))
+
+	post, err = provider.FetchPost("1")
+	require.NoError(t, err)
+	assert.Nil(t, post)
+
+	_, err = provider.FetchPost("alice/Castle-123456789")
+	assert.Error(t, err)
+}
@@ -0,0 +1,358 @@
+package fediverse
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"go.uber.org/fx"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	"github.com/nekomeowww/perobot/internal/models/published"
+	"github.com/nekomeowww/perobot/internal/thirdparty"
+	"github.com/nekomeowww/perobot/pkg/logger"
+	mastodon_public_types "github.com/nekomeowww/perobot/pkg/mastodon/public/types"
+	misskey_public_types "github.com/nekomeowww/perobot/pkg/misskey/public/types"
+)
+
+// Kind 实例所使用的 API
+type Kind string
+
+const (
+	// KindStatuses Mastodon 以及兼容 Mastodon API 的实例
+	KindStatuses Kind = "statuses"
+	// KindNotes Misskey 以及兼容 Misskey API 的实例
+	KindNotes Kind = "notes"
+)
+
+type NewProviderParam struct {
+	fx.In
+
+	Logger   *logger.Logger
+	Mastodon *thirdparty.MastodonPublic
+	Misskey  *thirdparty.MisskeyPublic
+}
+
+// Provider 发布任意 Mastodon、Misskey 实例上嘟文、笔记中的图片、视频和 GIF
+type Provider struct {
+	Logger   *logger.Logger
+	Mastodon *thirdparty.MastodonPublic
+	Misskey  *thirdparty.MisskeyPublic
+}
+
+func NewProvider() func(param NewProviderParam) *Provider {
+	return func(param NewProviderParam) *Provider {
+		return &Provider{
+			Logger:   param.Logger,
+			Mastodon: param.Mastodon,
+			Misskey:  param.Misskey,
+		}
+	}
+}
+
+func (p *Provider) Source() published.Source {
+	return published.SourceFediverse
+}
+
+// Match 返回 <实例域名>/<statuses 或 notes>/<ID> 形式的作品 ID
+func (p *Provider) Match(link string) string {
+	host, kind, id := PostFromText(publishing.LinkWithoutQuery(link))
+	if host == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s/%s/%s", host, kind, id)
+}
+
+func (p *Provider) FetchPost(postID string) (*publishing.Post, error) {
+	host, kind, id, err := splitPostID(postID)
+	if err != nil {
+		return nil, err
+	}
+
+	instanceURL := "https://" + host
+
+	switch kind {
+	case KindStatuses:
+		status, err := p.Mastodon.GetStatus(instanceURL, id)
+		if err != nil {
+			return nil, err
+		}
+		if status == nil {
+			return nil, nil
+		}
+
+		return statusPost(postID, host, status), nil
+	case KindNotes:
+		note, err := p.Misskey.ShowNote(instanceURL, id)
+		if err != nil {
+			return nil, err
+		}
+		if note == nil {
+			return nil, nil
+		}
+
+		return notePost(postID, host, note), nil
+	default:
+		return nil, fmt.Errorf("unknown fediverse post kind: %s", kind)
+	}
+}
+
+// statusPost 转嘟使用原嘟文
+func statusPost(postID string, host string, status *mastodon_public_types.Status) *publishing.Post {
+	if status.Reblog != nil {
+		status = status.Reblog
+	}
+
+	post := &publishing.Post{
+		ID:             postID,
+		URL:            firstNonEmpty(status.URL, status.URI),
+		Sensitive:      status.Sensitive,
+		SensitiveLabel: status.SpoilerText,
+		Raw:            status,
+	}
+	if post.URL == "" {
+		post.URL = fmt.Sprintf("https://%s/statuses/%s", host, status.ID)
+	}
+	if status.Account != nil {
+		post.Author = publishing.Author{
+			Name:   status.Account.Name(),
+			Handle: status.Account.FullAcct(host),
+			URL:    status.Account.URL,
+		}
+	}
+
+	return post
+}
+
+// notePost 只是转发的笔记使用被转发的笔记
+func notePost(postID string, host string, note *misskey_public_types.Note) *publishing.Post {
+	if note.IsPureRenote() {
+		note = note.Renote
+	}
+
+	post := &publishing.Post{
+		ID:             postID,
+		URL:            firstNonEmpty(note.URL, note.URI, fmt.Sprintf("https://%s/notes/%s", host, note.ID)),
+		Sensitive:      note.HasSensitiveFiles(),
+		SensitiveLabel: note.CW,
+		Raw:            note,
+	}
+	if note.User != nil {
+		// 其他实例的用户在本实例的主页为 /@<user>@<domain>
+		profilePath := "@" + note.User.Username
+		if note.User.Host != "" {
+			profilePath += "@" + note.User.Host
+		}
+
+		post.Author = publishing.Author{
+			Name:   note.User.DisplayName(),
+			Handle: note.User.FullAcct(host),
+			URL:    fmt.Sprintf("https://%s/%s", host, profilePath),
+		}
+	}
+
+	return post
+}
+
+func (p *Provider) ListMedia(post *publishing.Post) ([]*publishing.Media, error) {
+	switch raw := post.Raw.(type) {
+	case *mastodon_public_types.Status:
+		return withDownload(statusMedias(post, raw), p.downloadStatusMedia), nil
+	case *misskey_public_types.Note:
+		return withDownload(noteMedias(post, raw), p.downloadNoteMedia), nil
+	default:
+		return nil, errors.New("post is not a fediverse post")
+	}
+}
+
+// statusMedias 列出嘟文中的图片、视频和 GIF，音频和未知类型的附件会被跳过
+func statusMedias(post *publishing.Post, status *mastodon_public_types.Status) []*publishing.Media {
+	medias := make([]*publishing.Media, 0, len(status.MediaAttachments))
+	for i, attachment := range status.MediaAttachments {
+		if attachment.URL == "" {
+			continue
+		}
+
+		var mediaType publishing.MediaType
+		switch attachment.Type {
+		case mastodon_public_types.MediaAttachmentTypeImage:
+			mediaType = publishing.MediaTypePhoto
+		case mastodon_public_types.MediaAttachmentTypeGifv:
+			mediaType = publishing.MediaTypeAnimation
+		case mastodon_public_types.MediaAttachmentTypeVideo:
+			mediaType = publishing.MediaTypeVideo
+		default:
+			continue
+		}
+
+		width, height, duration := attachment.OriginalSize()
+
+		media := &publishing.Media{
+			Type:             mediaType,
+			CacheKey:         attachment.URL,
+			PreviewURLs:      []string{attachment.URL},
+			OriginalURL:      attachment.URL,
+			FileName:         fmt.Sprintf("%s-%s", postIDBase(post), publishing.URLBase(attachment.URL)),
+			OriginalFileName: originalFileName(post, i, attachment.URL),
+			Width:            width,
+			Height:           height,
+			Duration:         int(duration),
+			Description:      attachment.Description,
+		}
+		if mediaType != publishing.MediaTypePhoto {
+			media.PosterURL = attachment.PreviewURL
+		}
+
+		medias = append(medias, media)
+	}
+
+	return medias
+}
+
+// noteMedias 列出笔记中的图片、视频和 GIF，其他类型的附件会被跳过
+func noteMedias(post *publishing.Post, note *misskey_public_types.Note) []*publishing.Media {
+	medias := make([]*publishing.Media, 0, len(note.Files))
+	for i, file := range note.Files {
+		if file.URL == "" {
+			continue
+		}
+
+		var mediaType publishing.MediaType
+		switch {
+		case file.Type == "image/gif":
+			mediaType = publishing.MediaTypeAnimation
+		case file.IsImage():
+			mediaType = publishing.MediaTypePhoto
+		case file.IsVideo():
+			mediaType = publishing.MediaTypeVideo
+		default:
+			continue
+		}
+
+		media := &publishing.Media{
+			Type:             mediaType,
+			CacheKey:         file.URL,
+			PreviewURLs:      []string{file.URL},
+			OriginalURL:      file.URL,
+			FileName:         fmt.Sprintf("%s-%s", postIDBase(post), publishing.URLBase(file.URL)),
+			OriginalFileName: originalFileName(post, i, file.URL),
+			Width:            file.Properties.Width,
+			Height:           file.Properties.Height,
+			Description:      file.Comment,
+		}
+		if mediaType != publishing.MediaTypePhoto {
+			media.PosterURL = file.ThumbnailURL
+		}
+
+		medias = append(medias, media)
+	}
+
+	return medias
+}
+
+// RenderCaption 作者、链接和实例的域名都来自实例返回的数据，写入说明文字之前需要转义
+func (p *Provider) RenderCaption(post *publishing.Post, labels []string) string {
+	authorInfo := "未知"
+	if post.Author.Handle != "" {
+		authorInfo = fmt.Sprintf(`<a href="%s">%s (@%s)</a>`, html.EscapeString(post.Author.URL), html.EscapeString(post.Author.Name), html.EscapeString(post.Author.Handle))
+	}
+
+	var content string
+	switch raw := post.Raw.(type) {
+	case *mastodon_public_types.Status:
+		content = raw.ContentInHTML()
+	case *misskey_public_types.Note:
+		content = html.EscapeString(raw.Text)
+	}
+
+	// 说明文字中的来源显示为作品所在的实例
+	host, _, _, _ := splitPostID(post.ID)
+
+	return publishing.FormatCaption(authorInfo, content, labels, html.EscapeString(host), html.EscapeString(post.URL))
+}
+
+// Download 列出的媒体都设置了 Media.Download，按照作品的类型使用 Mastodon 或 Misskey 的客户端下载，
+// 这里只在没有设置时使用 Mastodon 的客户端
+func (p *Provider) Download(link string, w io.Writer) error {
+	return p.downloadStatusMedia(link, w)
+}
+
+func (p *Provider) downloadStatusMedia(link string, w io.Writer) error {
+	return p.Mastodon.Download(link, w)
+}
+
+func (p *Provider) downloadNoteMedia(link string, w io.Writer) error {
+	return p.Misskey.Download(link, w)
+}
+
+// withDownload 为媒体设置下载所使用的函数
+func withDownload(medias []*publishing.Media, download func(link string, w io.Writer) error) []*publishing.Media {
+	for _, media := range medias {
+		media.Download = download
+	}
+
+	return medias
+}
+
+func splitPostID(postID string) (string, Kind, string, error) {
+	parts := strings.SplitN(postID, "/", 3)
+	if len(parts) != 3 {
+		return "", "", "", fmt.Errorf("invalid fediverse post id: %s", postID)
+	}
+
+	return parts[0], Kind(parts[1]), parts[2], nil
+}
+
+// postIDBase 返回作品 ID 中实例上的 ID
+func postIDBase(post *publishing.Post) string {
+	_, _, id, _ := splitPostID(post.ID)
+	return id
+}
+
+// originalFileName 返回讨论群组中原图、原视频的文件名
+func originalFileName(post *publishing.Post, index int, link string) string {
+	return fmt.Sprintf("fediverse-by-%s-%s-%d%s", post.Author.Handle, postIDBase(post), index, path.Ext(publishing.URLBase(link)))
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+var (
+	// MastodonStatusLinkRegexp Mastodon 的嘟文链接 https://<instance>/@<user>/<id>，其他实例的用户为 @<user>@<domain>
+	MastodonStatusLinkRegexp = regexp.MustCompile(`^https://([^/]+)/@[^/]+/(\d+)$`)
+	// MastodonStatusActivityLinkRegexp Mastodon 嘟文的 ActivityPub 链接 https://<instance>/users/<user>/statuses/<id>
+	MastodonStatusActivityLinkRegexp = regexp.MustCompile(`^https://([^/]+)/users/[^/]+/statuses/(\d+)$`)
+	// PleromaNoticeLinkRegexp Pleroma、Akkoma 的嘟文链接 https://<instance>/notice/<id>，实例兼容 Mastodon API
+	PleromaNoticeLinkRegexp = regexp.MustCompile(`^https://([^/]+)/notice/([A-Za-z0-9]+)$`)
+	// MisskeyNoteLinkRegexp Misskey 的笔记链接 https://<instance>/notes/<id>
+	MisskeyNoteLinkRegexp = regexp.MustCompile(`^https://([^/]+)/notes/([A-Za-z0-9]+)$`)
+)
+
+// PostFromText 返回链接所在的实例域名、实例使用的 API 和作品在实例上的 ID，不是作品链接时返回空字符串
+func PostFromText(text string) (string, Kind, string) {
+	for _, linkRegexp := range []*regexp.Regexp{MastodonStatusLinkRegexp, MastodonStatusActivityLinkRegexp, PleromaNoticeLinkRegexp} {
+		matches := linkRegexp.FindStringSubmatch(text)
+		if len(matches) == 3 {
+			return matches[1], KindStatuses, matches[2]
+		}
+	}
+
+	matches := MisskeyNoteLinkRegexp.FindStringSubmatch(text)
+	if len(matches) == 3 {
+		return matches[1], KindNotes, matches[2]
+	}
+
+	return "", "", ""
+}
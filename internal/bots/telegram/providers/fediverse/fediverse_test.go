@@ -0,0 +1,154 @@
+package fediverse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	mastodon_public_types "github.com/nekomeowww/perobot/pkg/mastodon/public/types"
+	misskey_public_types "github.com/nekomeowww/perobot/pkg/misskey/public/types"
+)
+
+func TestPostFromText(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		link string
+		host string
+		kind Kind
+		id   string
+	}{
+		{"https://mastodon.social/@alice/109876543210", "mastodon.social", KindStatuses, "109876543210"},
+		{"https://mastodon.social/@bob@pawoo.net/109876543210", "mastodon.social", KindStatuses, "109876543210"},
+		{"https://pawoo.net/users/bob/statuses/109876543210", "pawoo.net", KindStatuses, "109876543210"},
+		{"https://akko.example/notice/AbCdEf123", "akko.example", KindStatuses, "AbCdEf123"},
+		{"https://misskey.io/notes/9abcdefghi", "misskey.io", KindNotes, "9abcdefghi"},
+		{"https://mastodon.social/@alice", "", "", ""},
+		{"https://twitter.com/alice/status/1234", "", "", ""},
+	}
+
+	for _, c := range cases {
+		host, kind, id := PostFromText(c.link)
+		assert.Equal(c.host, host, c.link)
+		assert.Equal(c.kind, kind, c.link)
+		assert.Equal(c.id, id, c.link)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	assert := assert.New(t)
+
+	provider := &Provider{}
+
+	assert.Equal("misskey.io/notes/9abcdefghi", provider.Match("https://misskey.io/notes/9abcdefghi?from=timeline"))
+	assert.Equal("mastodon.social/statuses/109876543210", provider.Match("https://Mastodon.Social/@alice/109876543210#reply"))
+	// 自建实例可能使用非默认端口
+	assert.Equal("social.example:8443/statuses/109876543210", provider.Match("https://social.example:8443/@alice/109876543210"))
+	// 用户信息不属于实例的域名
+	assert.Equal("mastodon.social/statuses/109876543210", provider.Match("https://alice@mastodon.social/@alice/109876543210"))
+	assert.Empty(provider.Match("https://mastodon.social/@alice?max_id=109876543210"))
+	assert.Empty(provider.Match("http://mastodon.social/@alice/109876543210"))
+}
+
+func TestStatus(t *testing.T) {
+	status := &mastodon_public_types.Status{
+		ID:  "1",
+		URL: "https://mastodon.social/@alice/2",
+		Reblog: &mastodon_public_types.Status{
+			ID:          "2",
+			URL:         "https://mastodon.social/@alice/2",
+			Content:     "<p>new <b>piece</b></p>",
+			Sensitive:   true,
+			SpoilerText: "blood",
+			Account:     &mastodon_public_types.Account{Username: "alice", Acct: "alice", DisplayName: "Alice & co", URL: "https://mastodon.social/@alice"},
+			MediaAttachments: []*mastodon_public_types.MediaAttachment{
+				{Type: mastodon_public_types.MediaAttachmentTypeImage, URL: "https://files.mastodon.social/original/a.png", Description: "cat"},
+				{Type: mastodon_public_types.MediaAttachmentTypeAudio, URL: "https://files.mastodon.social/original/b.mp3"},
+				{
+					Type:       mastodon_public_types.MediaAttachmentTypeGifv,
+					URL:        "https://files.mastodon.social/original/c.mp4",
+					PreviewURL: "https://files.mastodon.social/small/c.png",
+					Meta:       &mastodon_public_types.MediaAttachmentMeta{Original: &mastodon_public_types.MediaAttachmentMetaSize{Width: 640, Height: 480, Duration: 3.5}},
+				},
+			},
+		},
+	}
+
+	post := statusPost("mastodon.social/statuses/1", "mastodon.social", status)
+	assert.True(t, post.Sensitive)
+	assert.Equal(t, "blood", post.SensitiveLabel)
+	assert.Equal(t, "alice@mastodon.social", post.Author.Handle)
+
+	medias, err := (&Provider{}).ListMedia(post)
+	require.NoError(t, err)
+	require.Len(t, medias, 2)
+
+	assert.Equal(t, publishing.MediaTypePhoto, medias[0].Type)
+	assert.Equal(t, "cat", medias[0].Description)
+	assert.Equal(t, "1-a.png", medias[0].FileName)
+	assert.Equal(t, "fediverse-by-alice@mastodon.social-1-0.png", medias[0].OriginalFileName)
+	assert.NotNil(t, medias[0].Download)
+
+	assert.Equal(t, publishing.MediaTypeAnimation, medias[1].Type)
+	assert.Equal(t, "https://files.mastodon.social/small/c.png", medias[1].PosterURL)
+	assert.Equal(t, 640, medias[1].Width)
+	assert.Equal(t, 3, medias[1].Duration)
+
+	assert.Equal(t, ""+
+		`<a href="https://mastodon.social/@alice">Alice &amp; co (@alice@mastodon.social)</a>：`+"\n\n"+
+		"new piece\n\n"+
+		`来自 <a href="https://mastodon.social/@alice/2">mastodon.social</a>`,
+		(&Provider{}).RenderCaption(post, nil))
+}
+
+func TestNote(t *testing.T) {
+	note := &misskey_public_types.Note{
+		ID:   "9abcdefghi",
+		Text: "描いた <3",
+		User: &misskey_public_types.UserLite{Username: "bob", Host: "mstdn.jp"},
+		Files: []*misskey_public_types.DriveFile{
+			{Type: "image/gif", URL: "https://media.misskey.io/files/a.gif", ThumbnailURL: "https://media.misskey.io/files/thumbnail-a.webp"},
+			{Type: "image/webp", URL: "https://media.misskey.io/files/b.webp", IsSensitive: true, Comment: "落書き"},
+			{Type: "application/zip", URL: "https://media.misskey.io/files/c.zip"},
+		},
+	}
+
+	post := notePost("misskey.io/notes/9abcdefghi", "misskey.io", note)
+	assert.Equal(t, "https://misskey.io/notes/9abcdefghi", post.URL)
+	assert.Equal(t, "bob@mstdn.jp", post.Author.Handle)
+	assert.Equal(t, "https://misskey.io/@bob@mstdn.jp", post.Author.URL)
+	assert.True(t, post.Sensitive)
+
+	medias, err := (&Provider{}).ListMedia(post)
+	require.NoError(t, err)
+	require.Len(t, medias, 2)
+	assert.Equal(t, publishing.MediaTypeAnimation, medias[0].Type)
+	assert.Equal(t, "https://media.misskey.io/files/thumbnail-a.webp", medias[0].PosterURL)
+	assert.Equal(t, publishing.MediaTypePhoto, medias[1].Type)
+	assert.Equal(t, "落書き", medias[1].Description)
+	assert.NotNil(t, medias[1].Download)
+
+	assert.Contains(t, (&Provider{}).RenderCaption(post, []string{"#AI生成"}), "描いた &lt;3\n\n#AI生成")
+
+	renote := &misskey_public_types.Note{ID: "9zzzzzzzzz", Renote: note}
+	post = notePost("misskey.io/notes/9zzzzzzzzz", "misskey.io", renote)
+	assert.Equal(t, note, post.Raw)
+}
+
+func TestRenderCaptionEscapes(t *testing.T) {
+	// 实例返回的链接和用户名中的引号与尖括号不能破坏说明文字的 HTML
+	note := &misskey_public_types.Note{
+		ID:   "9abcdefghi",
+		URL:  `https://misskey.io/notes/9abcdefghi?a=1&b="><b>`,
+		User: &misskey_public_types.UserLite{Username: `bob"<i>`},
+	}
+
+	post := notePost(`misskey.io/notes/9abcdefghi`, "misskey.io", note)
+
+	assert.Equal(t,
+		`<a href="https://misskey.io/@bob&#34;&lt;i&gt;">bob&#34;&lt;i&gt; (@bob&#34;&lt;i&gt;@misskey.io)</a>`+"\n\n"+
+			`来自 <a href="https://misskey.io/notes/9abcdefghi?a=1&amp;b=&#34;&gt;&lt;b&gt;">misskey.io</a>`,
+		(&Provider{}).RenderCaption(post, nil))
+}
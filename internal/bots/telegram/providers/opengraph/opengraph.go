@@ -0,0 +1,204 @@
+package opengraph
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/samber/lo"
+	"go.uber.org/fx"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	"github.com/nekomeowww/perobot/internal/configs"
+	"github.com/nekomeowww/perobot/internal/models/published"
+	"github.com/nekomeowww/perobot/internal/thirdparty"
+	"github.com/nekomeowww/perobot/pkg/bots/telegram"
+	"github.com/nekomeowww/perobot/pkg/logger"
+	opengraph_public_types "github.com/nekomeowww/perobot/pkg/opengraph/public/types"
+)
+
+const (
+	// maxDescriptionLength 说明文字中网页描述的最大长度，超出的部分会被截断
+	maxDescriptionLength = 300
+)
+
+type NewProviderParam struct {
+	fx.In
+
+	Logger    *logger.Logger
+	OpenGraph *thirdparty.OpenGraphPublic
+}
+
+// Client Provider 所使用的网页元数据接口，由 thirdparty.OpenGraphPublic 实现
+type Client interface {
+	GetPage(pageURL string) (*opengraph_public_types.Page, error)
+	Download(link string, w io.Writer) error
+}
+
+// Provider 没有专门支持的网页的兜底来源，发布网页 og:image 或 twitter:image 中的图片，只在启用的频道中匹配允许的域名
+type Provider struct {
+	Logger    *logger.Logger
+	OpenGraph Client
+}
+
+func NewProvider() func(param NewProviderParam) *Provider {
+	return func(param NewProviderParam) *Provider {
+		return &Provider{
+			Logger:    param.Logger,
+			OpenGraph: param.OpenGraph,
+		}
+	}
+}
+
+func (p *Provider) Source() published.Source {
+	return published.SourceOpenGraph
+}
+
+// MatchChannel 只有频道启用了兜底发布，并且链接的域名在允许的域名中时才匹配
+func (p *Provider) MatchChannel(channelConfig configs.ChannelConfig, link string) bool {
+	parsedURL, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+
+	return channelConfig.OpenGraph.AllowsHost(parsedURL.Hostname())
+}
+
+// Match 返回去掉锚点的网页链接，查询参数可能决定了网页的内容，因此会保留
+func (p *Provider) Match(link string) string {
+	return PageIDFromText(link)
+}
+
+func (p *Provider) FetchPost(postID string) (*publishing.Post, error) {
+	page, err := p.OpenGraph.GetPage(postID)
+	if err != nil {
+		return nil, err
+	}
+	if page == nil {
+		return nil, nil
+	}
+
+	parsedURL, err := url.Parse(postID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &publishing.Post{
+		ID:  postID,
+		URL: postID,
+		Author: publishing.Author{
+			Name:   lo.Ternary(page.SiteName != "", page.SiteName, parsedURL.Hostname()),
+			Handle: parsedURL.Hostname(),
+			URL:    fmt.Sprintf("%s://%s/", parsedURL.Scheme, parsedURL.Host),
+		},
+		Raw: page,
+	}, nil
+}
+
+// FilterPost 链接只在匹配时检查过域名，重定向之后的网页和其中所有图片的域名同样需要在频道允许的域名中
+func (p *Provider) FilterPost(channelConfig configs.ChannelConfig, post *publishing.Post) string {
+	page, ok := post.Raw.(*opengraph_public_types.Page)
+	if !ok {
+		return "无法读取网页的元数据"
+	}
+
+	pageHost := urlHostname(page.URL)
+	if !channelConfig.OpenGraph.AllowsHost(pageHost) {
+		return fmt.Sprintf("网页重定向到了频道不允许的域名 <code>%s</code>", html.EscapeString(pageHost))
+	}
+
+	for _, image := range page.Images {
+		imageHost := urlHostname(image.URL)
+		if !channelConfig.OpenGraph.AllowsHost(imageHost) {
+			return fmt.Sprintf("网页中的图片位于频道不允许的域名 <code>%s</code>", html.EscapeString(imageHost))
+		}
+	}
+
+	return ""
+}
+
+// ListMedia 列出网页元数据中的图片，没有图片的网页没有媒体
+func (p *Provider) ListMedia(post *publishing.Post) ([]*publishing.Media, error) {
+	page, ok := post.Raw.(*opengraph_public_types.Page)
+	if !ok {
+		return nil, errors.New("post is not an opengraph page")
+	}
+
+	medias := make([]*publishing.Media, 0, len(page.Images))
+	for i, image := range page.Images {
+		ext := strings.ToLower(path.Ext(publishing.URLBase(image.URL)))
+		if ext == "" {
+			ext = ".jpg"
+		}
+
+		medias = append(medias, &publishing.Media{
+			Type:             publishing.MediaTypePhoto,
+			CacheKey:         image.URL,
+			PreviewURLs:      []string{image.URL},
+			OriginalURL:      image.URL,
+			FileName:         fmt.Sprintf("%d%s", i, ext),
+			OriginalFileName: fmt.Sprintf("opengraph-%s-%d%s", post.Author.Handle, i, ext),
+			Width:            image.Width,
+			Height:           image.Height,
+			Description:      image.Alt,
+		})
+	}
+
+	return medias, nil
+}
+
+func (p *Provider) RenderCaption(post *publishing.Post, labels []string) string {
+	authorInfo := fmt.Sprintf(`<a href="%s">%s</a>`, post.Author.URL, html.EscapeString(post.Author.Name))
+
+	contents := make([]string, 0, 2)
+
+	page, ok := post.Raw.(*opengraph_public_types.Page)
+	if ok {
+		if page.Title != "" {
+			contents = append(contents, "<b>"+html.EscapeString(page.Title)+"</b>")
+		}
+		if page.Description != "" && page.Description != page.Title {
+			contents = append(contents, html.EscapeString(telegram.TruncateText(page.Description, maxDescriptionLength)))
+		}
+	}
+
+	return publishing.FormatCaption(authorInfo, strings.Join(contents, "\n\n"), labels, html.EscapeString(post.Author.Handle), post.URL)
+}
+
+func (p *Provider) Download(link string, w io.Writer) error {
+	return p.OpenGraph.Download(link, w)
+}
+
+// urlHostname 返回链接中不包含端口的域名，无法解析时返回空字符串
+func urlHostname(link string) string {
+	parsedURL, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	return parsedURL.Hostname()
+}
+
+// PageIDFromText 返回去掉锚点、域名转换为小写的网页链接，不是 http 或 https 链接时返回空字符串
+func PageIDFromText(text string) string {
+	parsedURL, err := url.Parse(strings.TrimSpace(text))
+	if err != nil {
+		return ""
+	}
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return ""
+	}
+	if parsedURL.Host == "" || parsedURL.User != nil {
+		return ""
+	}
+
+	parsedURL.Host = strings.ToLower(parsedURL.Host)
+	parsedURL.Fragment = ""
+	parsedURL.RawFragment = ""
+
+	return parsedURL.String()
+}
@@ -0,0 +1,59 @@
+package pixiv
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/nekomeowww/perobot/internal/configs"
+	pixiv_public_types "github.com/nekomeowww/perobot/pkg/pixiv/public/types"
+)
+
+// FilterIllust 按照频道的过滤规则检查作品，被拒绝时返回可以直接展示给用户的原因，允许发布时返回空字符串
+func FilterIllust(filters configs.PixivFiltersConfig, illust *pixiv_public_types.Illust) string {
+	if len(filters.AllowedAuthors) > 0 && !lo.Contains(filters.AllowedAuthors, illust.UserID) {
+		return "频道只允许发布指定作者的作品"
+	}
+	if lo.Contains(filters.BlockedAuthors, illust.UserID) {
+		return "作者在频道的屏蔽列表中"
+	}
+	if filters.MaxXRestrict != nil && illust.XRestrict > *filters.MaxXRestrict {
+		return fmt.Sprintf("频道不允许发布 %s 作品", illust.RestrictLabel())
+	}
+	if illustTypeName := illust.IllustTypeName(); lo.Contains(filters.BlockedIllustTypes, illustTypeName) {
+		return fmt.Sprintf("频道不允许发布 %s 类型的作品", illustTypeName)
+	}
+
+	if blockedTag, ok := findMatchingTag(illust.Tags, filters.BlockedTags); ok {
+		return fmt.Sprintf("作品包含屏蔽的标签 <code>%s</code>", html.EscapeString(blockedTag))
+	}
+	if len(filters.AllowedTags) > 0 {
+		if _, ok := findMatchingTag(illust.Tags, filters.AllowedTags); !ok {
+			return "作品不包含频道允许的标签"
+		}
+	}
+
+	return ""
+}
+
+// findMatchingTag 返回 candidates 中第一个与作品标签或其英文翻译相同的标签，不区分大小写
+func findMatchingTag(tags *pixiv_public_types.Tags, candidates []string) (string, bool) {
+	if tags == nil || len(candidates) == 0 {
+		return "", false
+	}
+
+	for _, candidate := range candidates {
+		for _, tag := range tags.Tags {
+			if strings.EqualFold(tag.Tag, candidate) {
+				return candidate, true
+			}
+			if tag.Translation.En != "" && strings.EqualFold(tag.Translation.En, candidate) {
+				return candidate, true
+			}
+		}
+	}
+
+	return "", false
+}
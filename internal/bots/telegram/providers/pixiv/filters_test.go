@@ -0,0 +1,75 @@
+package pixiv
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nekomeowww/perobot/internal/configs"
+	pixiv_public_types "github.com/nekomeowww/perobot/pkg/pixiv/public/types"
+)
+
+func newTestIllust() *pixiv_public_types.Illust {
+	illust := &pixiv_public_types.Illust{
+		UserID:     "12345",
+		IllustType: pixiv_public_types.IllustTypeIllust,
+		XRestrict:  pixiv_public_types.XRestrictAllAges,
+		AiType:     pixiv_public_types.AiTypeNotAIGenerated,
+		Tags: &pixiv_public_types.Tags{
+			Tags: []*pixiv_public_types.Tag{
+				{Tag: "オリジナル"},
+				{Tag: "女の子"},
+			},
+		},
+	}
+	illust.Tags.Tags[0].Translation.En = "original"
+
+	return illust
+}
+
+func TestFilterIllust(t *testing.T) {
+	t.Run("NoFilters", func(t *testing.T) {
+		assert.Empty(t, FilterIllust(configs.PixivFiltersConfig{}, newTestIllust()))
+	})
+
+	t.Run("MaxXRestrict", func(t *testing.T) {
+		filters := configs.PixivFiltersConfig{MaxXRestrict: lo.ToPtr(pixiv_public_types.XRestrictR18)}
+
+		illust := newTestIllust()
+		illust.XRestrict = pixiv_public_types.XRestrictR18
+		assert.Empty(t, FilterIllust(filters, illust))
+
+		illust.XRestrict = pixiv_public_types.XRestrictR18G
+		assert.Contains(t, FilterIllust(filters, illust), "R-18G")
+	})
+
+	t.Run("BlockedIllustTypes", func(t *testing.T) {
+		filters := configs.PixivFiltersConfig{BlockedIllustTypes: []string{"ugoira"}}
+
+		illust := newTestIllust()
+		assert.Empty(t, FilterIllust(filters, illust))
+
+		illust.IllustType = pixiv_public_types.IllustTypeUgoira
+		assert.Contains(t, FilterIllust(filters, illust), "ugoira")
+	})
+
+	t.Run("Tags", func(t *testing.T) {
+		illust := newTestIllust()
+
+		assert.Contains(t, FilterIllust(configs.PixivFiltersConfig{BlockedTags: []string{"女の子"}}, illust), "女の子")
+		assert.Contains(t, FilterIllust(configs.PixivFiltersConfig{BlockedTags: []string{"Original"}}, illust), "Original")
+		assert.Empty(t, FilterIllust(configs.PixivFiltersConfig{BlockedTags: []string{"R-18G"}}, illust))
+
+		assert.Empty(t, FilterIllust(configs.PixivFiltersConfig{AllowedTags: []string{"original", "風景"}}, illust))
+		assert.NotEmpty(t, FilterIllust(configs.PixivFiltersConfig{AllowedTags: []string{"風景"}}, illust))
+	})
+
+	t.Run("Authors", func(t *testing.T) {
+		illust := newTestIllust()
+
+		assert.NotEmpty(t, FilterIllust(configs.PixivFiltersConfig{BlockedAuthors: []string{"12345"}}, illust))
+		assert.Empty(t, FilterIllust(configs.PixivFiltersConfig{AllowedAuthors: []string{"12345"}}, illust))
+		assert.NotEmpty(t, FilterIllust(configs.PixivFiltersConfig{AllowedAuthors: []string{"67890"}}, illust))
+	})
+}
@@ -0,0 +1,201 @@
+package pixiv
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	"go.uber.org/fx"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	"github.com/nekomeowww/perobot/internal/configs"
+	"github.com/nekomeowww/perobot/internal/models/published"
+	"github.com/nekomeowww/perobot/internal/thirdparty"
+	"github.com/nekomeowww/perobot/pkg/logger"
+	pixiv_public_types "github.com/nekomeowww/perobot/pkg/pixiv/public/types"
+	"github.com/nekomeowww/perobot/pkg/spool"
+)
+
+type NewProviderParam struct {
+	fx.In
+
+	Logger *logger.Logger
+	Pixiv  *thirdparty.PixivPublic
+	Spool  *spool.Store
+}
+
+// Provider 发布 Pixiv 插画、漫画和动图
+type Provider struct {
+	Logger *logger.Logger
+	Pixiv  *thirdparty.PixivPublic
+	Spool  *spool.Store
+}
+
+func NewProvider() func(param NewProviderParam) *Provider {
+	return func(param NewProviderParam) *Provider {
+		return &Provider{
+			Logger: param.Logger,
+			Pixiv:  param.Pixiv,
+			Spool:  param.Spool,
+		}
+	}
+}
+
+func (p *Provider) Source() published.Source {
+	return published.SourcePixiv
+}
+
+// Match 返回 Pixiv 插画的 ID
+func (p *Provider) Match(link string) string {
+	return IllustIDFromText(publishing.LinkWithoutQuery(link))
+}
+
+func (p *Provider) FetchPost(illustID string) (*publishing.Post, error) {
+	var illustDetailResp *pixiv_public_types.IllustDetailResp
+	_, _, err := lo.AttemptWithDelay(1, time.Second, func(index int, duration time.Duration) error {
+		var err error
+		illustDetailResp, err = p.Pixiv.IllustDetail(illustID)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if illustDetailResp == nil || illustDetailResp.Body == nil {
+		return nil, nil
+	}
+
+	illust := illustDetailResp.Body
+
+	return &publishing.Post{
+		ID:  illustID,
+		URL: fmt.Sprintf("https://www.pixiv.net/artworks/%s", illustID),
+		Author: publishing.Author{
+			Name: illust.UserName,
+			URL:  fmt.Sprintf("https://www.pixiv.net/users/%s", illust.UserID),
+		},
+		Metadata: publishing.Metadata{
+			AIGenerated: illust.IsAIGenerated(),
+		},
+		Sensitive:      illust.IsSensitive(),
+		SensitiveLabel: illust.RestrictLabel(),
+		Raw:            illust,
+	}, nil
+}
+
+// FilterPost 按照频道的 Pixiv 过滤规则检查作品
+func (p *Provider) FilterPost(channelConfig configs.ChannelConfig, post *publishing.Post) string {
+	illust, ok := post.Raw.(*pixiv_public_types.Illust)
+	if !ok {
+		return ""
+	}
+
+	return FilterIllust(channelConfig.Filters.Pixiv, illust)
+}
+
+// ListMedia 列出插画的每一页，动图转换为 GIF 以动画的形式发布，转换失败时退回到发布静态的第一帧
+func (p *Provider) ListMedia(post *publishing.Post) ([]*publishing.Media, error) {
+	illust, ok := post.Raw.(*pixiv_public_types.Illust)
+	if !ok {
+		return nil, errors.New("post is not a pixiv illust")
+	}
+
+	var illustDetailPagesResp *pixiv_public_types.IllustDetailPagesResp
+	_, _, err := lo.AttemptWithDelay(1, time.Second, func(index int, duration time.Duration) error {
+		var err error
+		illustDetailPagesResp, err = p.Pixiv.IllustDetailPages(post.ID)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if illustDetailPagesResp == nil {
+		return nil, errors.New("pixiv illust detail pages not found")
+	}
+
+	urlItems := lo.Filter(illustDetailPagesResp.Body, func(item *pixiv_public_types.IllustDetailPagesRespItem, _ int) bool {
+		return item.Urls.Regular != "" && item.Urls.Original != ""
+	})
+
+	pages := lo.Map(urlItems, func(item *pixiv_public_types.IllustDetailPagesRespItem, _ int) *publishing.Media {
+		return &publishing.Media{
+			Type:             publishing.MediaTypePhoto,
+			CacheKey:         item.Urls.Regular,
+			PreviewURLs:      []string{item.Urls.Regular},
+			OriginalURL:      item.Urls.Original,
+			FileName:         fmt.Sprintf("%s-%s", post.ID, filepath.Base(item.Urls.Regular)),
+			OriginalFileName: originalFileName(post, item.Urls.Original),
+		}
+	})
+	if illust.IllustType != pixiv_public_types.IllustTypeUgoira {
+		return pages, nil
+	}
+
+	ugoiraMedia, err := p.ugoiraMedia(post, illust)
+	if err != nil {
+		p.Logger.WithField("pixiv_illust_id", post.ID).Warnf("failed to get ugoira meta, falling back to static image, err: %v", err)
+		return pages, nil
+	}
+
+	ugoiraMedia.Fallback = pages
+
+	return []*publishing.Media{ugoiraMedia}, nil
+}
+
+func (p *Provider) RenderCaption(post *publishing.Post, labels []string) string {
+	illustAuthorInfo := "未知"
+	if post.Author.Name != "" {
+		illustAuthorInfo = fmt.Sprintf(`<a href="%s">%s</a>`, post.Author.URL, post.Author.Name)
+	}
+
+	var title string
+	tags := make([]string, 0)
+
+	illust, ok := post.Raw.(*pixiv_public_types.Illust)
+	if ok {
+		title = illust.Title
+		if illust.Tags != nil {
+			for _, tag := range illust.Tags.Tags {
+				tags = append(tags, fmt.Sprintf("#%s", strings.ReplaceAll(tag.Tag, "-", "")))
+			}
+		}
+	}
+
+	tags = append(tags, labels...)
+
+	return publishing.FormatCaption(illustAuthorInfo, title, tags, "Pixiv", post.URL)
+}
+
+func (p *Provider) Download(link string, w io.Writer) error {
+	return p.Pixiv.GetImageTo(link, w)
+}
+
+// originalFileName 返回讨论群组中原图的文件名
+func originalFileName(post *publishing.Post, link string) string {
+	return fmt.Sprintf("pixiv-by-%s-%s-%s", post.Author.Name, post.ID, filepath.Base(link))
+}
+
+var (
+	PixivIllustIDRegexp = regexp.MustCompile(`https://www.pixiv.net/(.*\/)?artworks/(\d+)`)
+)
+
+func IllustIDFromText(text string) string {
+	matches := PixivIllustIDRegexp.FindStringSubmatch(text)
+	if len(matches) != 3 {
+		return ""
+	}
+
+	return matches[2]
+}
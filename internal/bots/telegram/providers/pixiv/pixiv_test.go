@@ -0,0 +1,30 @@
+package pixiv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIllustIDFromText(t *testing.T) {
+	artworkID := IllustIDFromText("https://www.pixiv.net/artworks/1234")
+	assert.Equal(t, "1234", artworkID)
+
+	artworkID = IllustIDFromText("https://www.pixiv.net/en/artworks/1234")
+	assert.Equal(t, "1234", artworkID)
+
+	artworkID = IllustIDFromText("https://www.pixiv.net/jp/artworks/1234")
+	assert.Equal(t, "1234", artworkID)
+}
+
+func TestMatch(t *testing.T) {
+	assert := assert.New(t)
+
+	provider := &Provider{}
+
+	assert.Equal("1234", provider.Match("https://www.pixiv.net/artworks/1234?p=2"))
+	assert.Equal("1234", provider.Match("https://www.pixiv.net/en/artworks/1234#manga"))
+	assert.Equal("1234", provider.Match("https://WWW.Pixiv.net/artworks/1234"))
+	assert.Empty(provider.Match("https://www.pixiv.net/users/1234?tab=illust"))
+	assert.Empty(provider.Match("https://www.pixiv.net/member_illust.php?illust_id=1234"))
+}
@@ -0,0 +1,71 @@
+package pixiv
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	pixiv_public_types "github.com/nekomeowww/perobot/pkg/pixiv/public/types"
+	"github.com/nekomeowww/perobot/pkg/pixiv/ugoira"
+	"github.com/nekomeowww/perobot/pkg/spool"
+)
+
+// ugoiraMedia 将动图预览尺寸的帧压缩包转换为 GIF 作为频道中的动画，讨论群组中回复原始尺寸的帧压缩包
+func (p *Provider) ugoiraMedia(post *publishing.Post, illust *pixiv_public_types.Illust) (*publishing.Media, error) {
+	ugoiraMetaResp, err := p.Pixiv.UgoiraMeta(post.ID)
+	if err != nil {
+		return nil, err
+	}
+	if ugoiraMetaResp.Body == nil || ugoiraMetaResp.Body.Src == "" {
+		return nil, errors.New("ugoira meta is empty")
+	}
+
+	frames := ugoiraMetaResp.Body.Frames
+
+	duration := 0
+	for _, frame := range frames {
+		duration += frame.Delay
+	}
+
+	media := &publishing.Media{
+		Type:        publishing.MediaTypeAnimation,
+		CacheKey:    ugoiraMetaResp.Body.Src,
+		PreviewURLs: []string{ugoiraMetaResp.Body.Src},
+		OriginalURL: ugoiraMetaResp.Body.OriginalSrc,
+		FileName:    fmt.Sprintf("%s-ugoira.gif", post.ID),
+		Width:       illust.Width,
+		Height:      illust.Height,
+		Duration:    duration / 1000,
+		ConvertPreview: func(preview *spool.File) (*spool.File, image.Image, error) {
+			return p.renderUgoira(preview, frames)
+		},
+	}
+	if media.OriginalURL != "" {
+		media.OriginalFileName = originalFileName(post, media.OriginalURL)
+	}
+
+	return media, nil
+}
+
+// renderUgoira 将帧压缩包转换为 GIF，并返回用于计算感知哈希的第一帧
+func (p *Provider) renderUgoira(zipFile *spool.File, frames []*pixiv_public_types.UgoiraFrame) (*spool.File, image.Image, error) {
+	zipData, err := zipFile.Bytes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var first image.Image
+
+	animation, err := p.Spool.Download(func(w io.Writer) error {
+		first, err = ugoira.EncodeGIF(w, zipData, frames)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p.Logger.Infof("ugoira with %d frames rendered to %d bytes of gif", len(frames), animation.Size())
+	return animation, first, nil
+}
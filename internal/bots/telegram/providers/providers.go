@@ -0,0 +1,72 @@
+package providers
+
+import (
+	"go.uber.org/fx"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/providers/artstation"
+	"github.com/nekomeowww/perobot/internal/bots/telegram/providers/bilibili"
+	"github.com/nekomeowww/perobot/internal/bots/telegram/providers/bluesky"
+	"github.com/nekomeowww/perobot/internal/bots/telegram/providers/booru"
+	"github.com/nekomeowww/perobot/internal/bots/telegram/providers/deviantart"
+	"github.com/nekomeowww/perobot/internal/bots/telegram/providers/fediverse"
+	"github.com/nekomeowww/perobot/internal/bots/telegram/providers/opengraph"
+	"github.com/nekomeowww/perobot/internal/bots/telegram/providers/pixiv"
+	"github.com/nekomeowww/perobot/internal/bots/telegram/providers/reddit"
+	"github.com/nekomeowww/perobot/internal/bots/telegram/providers/twitter"
+	"github.com/nekomeowww/perobot/internal/bots/telegram/providers/weibo"
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+)
+
+func NewModules() fx.Option {
+	return fx.Options(
+		fx.Provide(twitter.NewProvider()),
+		fx.Provide(pixiv.NewProvider()),
+		fx.Provide(bluesky.NewProvider()),
+		fx.Provide(booru.NewProvider()),
+		fx.Provide(weibo.NewProvider()),
+		fx.Provide(bilibili.NewProvider()),
+		fx.Provide(artstation.NewProvider()),
+		fx.Provide(deviantart.NewProvider()),
+		fx.Provide(reddit.NewProvider()),
+		fx.Provide(fediverse.NewProvider()),
+		fx.Provide(opengraph.NewProvider()),
+		fx.Provide(NewProviders()),
+	)
+}
+
+type NewProvidersParam struct {
+	fx.In
+
+	TwitterProvider    *twitter.Provider
+	PixivProvider      *pixiv.Provider
+	BlueskyProvider    *bluesky.Provider
+	BooruProvider      *booru.Provider
+	WeiboProvider      *weibo.Provider
+	BilibiliProvider   *bilibili.Provider
+	ArtStationProvider *artstation.Provider
+	DeviantArtProvider *deviantart.Provider
+	RedditProvider     *reddit.Provider
+	FediverseProvider  *fediverse.Provider
+	OpenGraphProvider  *opengraph.Provider
+}
+
+// NewProviders 所有支持发布的来源，按顺序匹配链接
+func NewProviders() func(param NewProvidersParam) publishing.Providers {
+	return func(param NewProvidersParam) publishing.Providers {
+		return publishing.Providers{
+			param.TwitterProvider,
+			param.PixivProvider,
+			param.BlueskyProvider,
+			param.BooruProvider,
+			param.WeiboProvider,
+			param.BilibiliProvider,
+			param.ArtStationProvider,
+			param.DeviantArtProvider,
+			param.RedditProvider,
+			// 任意实例的链接只能通过路径判断，放在最后以免误判其他来源的链接
+			param.FediverseProvider,
+			// 兜底匹配任意网页，只在启用的频道中生效，必须放在最后
+			param.OpenGraphProvider,
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package reddit
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/samber/lo"
+	"go.uber.org/fx"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	"github.com/nekomeowww/perobot/internal/models/published"
+	"github.com/nekomeowww/perobot/internal/thirdparty"
+	"github.com/nekomeowww/perobot/pkg/logger"
+	reddit_public_types "github.com/nekomeowww/perobot/pkg/reddit/public/types"
+)
+
+type NewProviderParam struct {
+	fx.In
+
+	Logger *logger.Logger
+	Reddit *thirdparty.RedditPublic
+}
+
+// Client Provider 所使用的 Reddit 接口，由 thirdparty.RedditPublic 实现
+type Client interface {
+	GetLink(id string) (*reddit_public_types.Link, error)
+	Download(link string, w io.Writer) error
+}
+
+// Provider 发布 Reddit 图片帖子、相册帖子和视频帖子中的媒体
+type Provider struct {
+	Logger *logger.Logger
+	Reddit Client
+}
+
+func NewProvider() func(param NewProviderParam) *Provider {
+	return func(param NewProviderParam) *Provider {
+		return &Provider{
+			Logger: param.Logger,
+			Reddit: param.Reddit,
+		}
+	}
+}
+
+func (p *Provider) Source() published.Source {
+	return published.SourceReddit
+}
+
+// Match 返回帖子的 base36 ID
+func (p *Provider) Match(link string) string {
+	return LinkIDFromText(publishing.LinkWithoutQuery(link))
+}
+
+func (p *Provider) FetchPost(postID string) (*publishing.Post, error) {
+	link, err := p.Reddit.GetLink(postID)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil {
+		return nil, nil
+	}
+
+	// 转发的帖子使用原帖，转发到 NSFW 版块的帖子同样视为 NSFW
+	over18 := link.Over18
+	if len(link.CrosspostParentList) > 0 {
+		link = link.CrosspostParentList[0]
+		over18 = over18 || link.Over18
+	}
+
+	post := &publishing.Post{
+		ID:        postID,
+		URL:       link.PostURL(),
+		Sensitive: over18,
+		Raw:       link,
+	}
+	if post.Sensitive {
+		post.SensitiveLabel = "NSFW"
+	}
+	if link.Author != "" && link.Author != "[deleted]" {
+		post.Author = publishing.Author{
+			Name:   "u/" + link.Author,
+			Handle: link.Author,
+			URL:    link.AuthorURL(),
+		}
+	}
+
+	return post, nil
+}
+
+// ListMedia 列出帖子中的媒体，外部链接帖子没有媒体
+func (p *Provider) ListMedia(post *publishing.Post) ([]*publishing.Media, error) {
+	link, ok := post.Raw.(*reddit_public_types.Link)
+	if !ok {
+		return nil, errors.New("post is not a reddit link")
+	}
+
+	switch {
+	case link.IsGallery:
+		return p.galleryMedias(post, link), nil
+	case link.SecureMedia != nil && link.SecureMedia.RedditVideo != nil:
+		videoMedia := p.videoMedia(post, link)
+		if videoMedia == nil {
+			return []*publishing.Media{}, nil
+		}
+
+		return []*publishing.Media{videoMedia}, nil
+	case link.PostHint == reddit_public_types.PostHintImage:
+		return []*publishing.Media{imageMedia(post, link)}, nil
+	default:
+		return []*publishing.Media{}, nil
+	}
+}
+
+// galleryMedias 按照相册的顺序列出图片，尚未处理完成或已被删除的图片会被跳过
+func (p *Provider) galleryMedias(post *publishing.Post, link *reddit_public_types.Link) []*publishing.Media {
+	if link.GalleryData == nil {
+		return []*publishing.Media{}
+	}
+
+	medias := make([]*publishing.Media, 0, len(link.GalleryData.Items))
+	for _, item := range link.GalleryData.Items {
+		metadata, ok := link.MediaMetadata[item.MediaID]
+		if !ok || !metadata.IsValid() {
+			p.Logger.WithField("reddit_media_id", item.MediaID).Warn("gallery item is not available, skipping...")
+			continue
+		}
+
+		if metadata.E == reddit_public_types.MediaMetadataTypeAnimatedImage {
+			previewURLs := lo.Compact([]string{metadata.S.MP4, metadata.S.GIF})
+			if len(previewURLs) == 0 {
+				continue
+			}
+
+			medias = append(medias, &publishing.Media{
+				Type:             publishing.MediaTypeAnimation,
+				CacheKey:         previewURLs[0],
+				PreviewURLs:      previewURLs,
+				OriginalURL:      previewURLs[0],
+				FileName:         item.MediaID + path.Ext(publishing.URLBase(previewURLs[0])),
+				OriginalFileName: originalFileName(post, len(medias), path.Ext(publishing.URLBase(previewURLs[0]))),
+				Width:            metadata.S.X,
+				Height:           metadata.S.Y,
+				Description:      item.Caption,
+			})
+
+			continue
+		}
+
+		originalURL := metadata.OriginalURL(item.MediaID)
+
+		medias = append(medias, &publishing.Media{
+			Type:             publishing.MediaTypePhoto,
+			CacheKey:         originalURL,
+			PreviewURLs:      lo.Compact([]string{originalURL, metadata.S.U}),
+			OriginalURL:      originalURL,
+			FileName:         publishing.URLBase(originalURL),
+			OriginalFileName: originalFileName(post, len(medias), path.Ext(originalURL)),
+			Width:            metadata.S.X,
+			Height:           metadata.S.Y,
+			Description:      item.Caption,
+		})
+	}
+
+	return medias
+}
+
+// videoMedia 发布 v.redd.it 视频的 MP4 版本，音轨是单独的文件，发布的视频没有声音
+func (p *Provider) videoMedia(post *publishing.Post, link *reddit_public_types.Link) *publishing.Media {
+	redditVideo := link.SecureMedia.RedditVideo
+	if redditVideo.FallbackURL == "" {
+		p.Logger.WithField("reddit_link_id", link.ID).Warn("video has no mp4 fallback url, skipping...")
+		return nil
+	}
+
+	media := &publishing.Media{
+		Type:             lo.Ternary(redditVideo.IsGIF, publishing.MediaTypeAnimation, publishing.MediaTypeVideo),
+		CacheKey:         redditVideo.FallbackURL,
+		PreviewURLs:      []string{redditVideo.FallbackURL},
+		OriginalURL:      redditVideo.FallbackURL,
+		FileName:         fmt.Sprintf("%s.mp4", link.ID),
+		OriginalFileName: originalFileName(post, 0, ".mp4"),
+		Width:            redditVideo.Width,
+		Height:           redditVideo.Height,
+		Duration:         redditVideo.Duration,
+	}
+	if previewSource := link.PreviewSource(); previewSource != nil {
+		media.PosterURL = previewSource.URL
+	}
+
+	return media
+}
+
+// imageMedia 单张图片的帖子，预览图作为原图无法发送时的备选，GIF 优先使用 Reddit 转换的 MP4
+func imageMedia(post *publishing.Post, link *reddit_public_types.Link) *publishing.Media {
+	ext := strings.ToLower(path.Ext(publishing.URLBase(link.URL)))
+
+	media := &publishing.Media{
+		Type:             publishing.MediaTypePhoto,
+		CacheKey:         link.URL,
+		PreviewURLs:      []string{link.URL},
+		OriginalURL:      link.URL,
+		FileName:         publishing.URLBase(link.URL),
+		OriginalFileName: originalFileName(post, 0, ext),
+	}
+
+	previewSource := link.PreviewSource()
+	if previewSource != nil {
+		media.Width = previewSource.Width
+		media.Height = previewSource.Height
+	}
+
+	if ext == ".gif" {
+		media.Type = publishing.MediaTypeAnimation
+		if mp4Source := link.PreviewMP4Source(); mp4Source != nil {
+			media.PreviewURLs = []string{mp4Source.URL, link.URL}
+		}
+		if previewSource != nil {
+			media.PosterURL = previewSource.URL
+		}
+	} else if previewSource != nil {
+		media.PreviewURLs = lo.Uniq(append(media.PreviewURLs, previewSource.URL))
+	}
+
+	return media
+}
+
+func (p *Provider) RenderCaption(post *publishing.Post, labels []string) string {
+	authorInfo := "未知"
+	if post.Author.Handle != "" {
+		authorInfo = fmt.Sprintf(`<a href="%s">%s</a>`, post.Author.URL, html.EscapeString(post.Author.Name))
+	}
+
+	var title string
+	sourceName := "Reddit"
+
+	link, ok := post.Raw.(*reddit_public_types.Link)
+	if ok {
+		title = html.EscapeString(link.Title)
+		if link.Subreddit != "" {
+			sourceName = "r/" + link.Subreddit
+		}
+	}
+
+	return publishing.FormatCaption(authorInfo, title, labels, sourceName, post.URL)
+}
+
+func (p *Provider) Download(link string, w io.Writer) error {
+	return p.Reddit.Download(link, w)
+}
+
+// originalFileName 返回讨论群组中原图、原视频的文件名
+func originalFileName(post *publishing.Post, index int, ext string) string {
+	return fmt.Sprintf("reddit-by-%s-%s-%d%s", lo.Ternary(post.Author.Handle != "", post.Author.Handle, "deleted"), post.ID, index, ext)
+}
+
+var (
+	// CommentsLinkRegexp 帖子链接 https://www.reddit.com/r/<sub>/comments/<id>/<title>/，也可以省略版块和标题
+	CommentsLinkRegexp = regexp.MustCompile(`^https://(?:(?:www|old|new|m)\.)?reddit\.com/(?:r/[A-Za-z0-9_]+/|user/[A-Za-z0-9_-]+/)?comments/([a-z0-9]+)(?:/[^/]*)?/?$`)
+	// ShortLinkRegexp 帖子的短链接 https://redd.it/<id>
+	ShortLinkRegexp = regexp.MustCompile(`^https://redd\.it/([a-z0-9]+)/?$`)
+)
+
+// LinkIDFromText 返回链接中帖子的 ID，不是帖子链接时返回空字符串
+func LinkIDFromText(text string) string {
+	for _, linkRegexp := range []*regexp.Regexp{CommentsLinkRegexp, ShortLinkRegexp} {
+		matches := linkRegexp.FindStringSubmatch(text)
+		if len(matches) == 2 {
+			return matches[1]
+		}
+	}
+
+	return ""
+}
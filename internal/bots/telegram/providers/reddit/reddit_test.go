@@ -0,0 +1,168 @@
+package reddit
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	"github.com/nekomeowww/perobot/internal/lib"
+	reddit_public_types "github.com/nekomeowww/perobot/pkg/reddit/public/types"
+)
+
+const testGalleryLink = `{
+	"id": "gal123",
+	"title": "Sketches <1>",
+	"author": "alice",
+	"subreddit": "Art",
+	"permalink": "/r/Art/comments/gal123/sketches_1/",
+	"url": "https://www.reddit.com/gallery/gal123",
+	"over_18": true,
+	"is_gallery": true,
+	"gallery_data": {
+		"items": [
+			{ "media_id": "second", "caption": "page 2" },
+			{ "media_id": "missing" },
+			{ "media_id": "first" },
+			{ "media_id": "anim" }
+		]
+	},
+	"media_metadata": {
+		"first": { "status": "valid", "e": "Image", "m": "image/png", "s": { "u": "https://preview.redd.it/first.png?width=2000&s=sig", "x": 2000, "y": 3000 } },
+		"second": { "status": "valid", "e": "Image", "m": "image/jpg", "s": { "u": "https://preview.redd.it/second.jpg?width=1000&s=sig", "x": 1000, "y": 1000 } },
+		"missing": { "status": "failed" },
+		"anim": { "status": "valid", "e": "AnimatedImage", "m": "image/gif", "s": { "gif": "https://i.redd.it/anim.gif", "mp4": "https://preview.redd.it/anim.gif?format=mp4&s=sig", "x": 320, "y": 240 } }
+	}
+}`
+
+const testVideoLink = `{
+	"id": "vid123",
+	"title": "crosspost",
+	"author": "bob",
+	"subreddit": "animation",
+	"permalink": "/r/animation/comments/vid123/crosspost/",
+	"crosspost_parent_list": [{
+		"id": "orig12",
+		"title": "Walk cycle",
+		"author": "alice",
+		"subreddit": "Art",
+		"permalink": "/r/Art/comments/orig12/walk_cycle/",
+		"post_hint": "hosted:video",
+		"secure_media": {
+			"reddit_video": { "fallback_url": "https://v.redd.it/orig12/DASH_720.mp4?source=fallback", "width": 1280, "height": 720, "duration": 8, "is_gif": false }
+		},
+		"preview": { "images": [{ "source": { "url": "https://external-preview.redd.it/poster.jpg", "width": 1280, "height": 720 } }] }
+	}]
+}`
+
+// fakeClient 返回固定帖子的 Client，请求和解析由 reddit_public 的测试覆盖
+type fakeClient struct {
+	t *testing.T
+}
+
+func (c *fakeClient) GetLink(id string) (*reddit_public_types.Link, error) {
+	links := map[string]string{
+		"gal123": testGalleryLink,
+		"vid123": testVideoLink,
+	}
+
+	raw, ok := links[id]
+	if !ok {
+		return nil, nil
+	}
+
+	var link reddit_public_types.Link
+	require.NoError(c.t, json.Unmarshal([]byte(raw), &link))
+
+	return &link, nil
+}
+
+func (c *fakeClient) Download(link string, w io.Writer) error {
+	return errors.New("not implemented")
+}
+
+func TestLinkIDFromText(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("abc123", LinkIDFromText("https://www.reddit.com/r/Art/comments/abc123/my_oc/"))
+	assert.Equal("abc123", LinkIDFromText("https://old.reddit.com/r/Art/comments/abc123"))
+	assert.Equal("abc123", LinkIDFromText("https://reddit.com/comments/abc123"))
+	assert.Equal("abc123", LinkIDFromText("https://redd.it/abc123"))
+	assert.Empty(LinkIDFromText("https://www.reddit.com/r/Art/comments/abc123/my_oc/def456/"))
+	assert.Empty(LinkIDFromText("https://www.reddit.com/r/Art/"))
+}
+
+func TestMatch(t *testing.T) {
+	assert := assert.New(t)
+
+	provider := &Provider{}
+
+	assert.Equal("abc123", provider.Match("https://www.reddit.com/r/Art/comments/abc123/my_oc/?utm_source=share&utm_medium=web2x"))
+	assert.Equal("abc123", provider.Match("https://Old.Reddit.com/r/Art/comments/abc123#siteTable"))
+	assert.Equal("abc123", provider.Match("https://redd.it/abc123?context=3"))
+	assert.Empty(provider.Match("https://www.reddit.com/r/Art/?sort=top"))
+	assert.Empty(provider.Match("https://www.reddit.com/media?url=https://i.redd.it/abc123.jpg"))
+}
+
+func TestGallery(t *testing.T) {
+	provider := &Provider{Logger: lib.NewLogger()(), Reddit: &fakeClient{t: t}}
+
+	postID := provider.Match("https://www.reddit.com/r/Art/comments/gal123/sketches_1/?utm_source=share")
+	require.Equal(t, "gal123", postID)
+
+	post, err := provider.FetchPost(postID)
+	require.NoError(t, err)
+	require.NotNil(t, post)
+	assert.True(t, post.Sensitive)
+	assert.Equal(t, "NSFW", post.SensitiveLabel)
+
+	medias, err := provider.ListMedia(post)
+	require.NoError(t, err)
+	require.Len(t, medias, 3)
+
+	assert.Equal(t, publishing.MediaTypePhoto, medias[0].Type)
+	assert.Equal(t, []string{"https://i.redd.it/second.jpg", "https://preview.redd.it/second.jpg?width=1000&s=sig"}, medias[0].PreviewURLs)
+	assert.Equal(t, "page 2", medias[0].Description)
+	assert.Equal(t, "reddit-by-alice-gal123-0.jpg", medias[0].OriginalFileName)
+
+	assert.Equal(t, "https://i.redd.it/first.png", medias[1].OriginalURL)
+	assert.Equal(t, "reddit-by-alice-gal123-1.png", medias[1].OriginalFileName)
+
+	assert.Equal(t, publishing.MediaTypeAnimation, medias[2].Type)
+	assert.Equal(t, []string{"https://preview.redd.it/anim.gif?format=mp4&s=sig", "https://i.redd.it/anim.gif"}, medias[2].PreviewURLs)
+
+	assert.Equal(t, ""+
+		`<a href="https://www.reddit.com/user/alice">u/alice</a>：`+"\n\n"+
+		"Sketches &lt;1&gt;\n\n"+
+		"#NSFW\n\n"+
+		`来自 <a href="https://www.reddit.com/r/Art/comments/gal123/sketches_1/">r/Art</a>`,
+		provider.RenderCaption(post, []string{"#NSFW"}))
+}
+
+func TestCrosspostedVideo(t *testing.T) {
+	provider := &Provider{Logger: lib.NewLogger()(), Reddit: &fakeClient{t: t}}
+
+	post, err := provider.FetchPost("vid123")
+	require.NoError(t, err)
+	require.NotNil(t, post)
+	assert.False(t, post.Sensitive)
+	assert.Equal(t, "https://www.reddit.com/r/Art/comments/orig12/walk_cycle/", post.URL)
+	assert.Equal(t, "alice", post.Author.Handle)
+
+	medias, err := provider.ListMedia(post)
+	require.NoError(t, err)
+	require.Len(t, medias, 1)
+	assert.Equal(t, publishing.MediaTypeVideo, medias[0].Type)
+	assert.Equal(t, "https://v.redd.it/orig12/DASH_720.mp4?source=fallback", medias[0].OriginalURL)
+	assert.Equal(t, "https://external-preview.redd.it/poster.jpg", medias[0].PosterURL)
+	assert.Equal(t, 8, medias[0].Duration)
+	assert.Equal(t, "reddit-by-alice-vid123-0.mp4", medias[0].OriginalFileName)
+
+	post, err = provider.FetchPost("deleted")
+	require.NoError(t, err)
+	assert.Nil(t, post)
+}
@@ -0,0 +1,227 @@
+package twitter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/imroc/req/v3"
+	"github.com/samber/lo"
+	"go.uber.org/fx"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	"github.com/nekomeowww/perobot/internal/models/published"
+	twitter_model "github.com/nekomeowww/perobot/internal/models/twitter"
+	"github.com/nekomeowww/perobot/pkg/logger"
+	twitter_public_types "github.com/nekomeowww/perobot/pkg/twitter/public/types"
+)
+
+type NewProviderParam struct {
+	fx.In
+
+	Logger       *logger.Logger
+	TwitterModel *twitter_model.Model
+}
+
+// Provider 发布推文中的图片、视频和 GIF
+type Provider struct {
+	Logger    *logger.Logger
+	Twitter   *twitter_model.Model
+	ReqClient *req.Client
+}
+
+func NewProvider() func(param NewProviderParam) *Provider {
+	return func(param NewProviderParam) *Provider {
+		return &Provider{
+			Logger:    param.Logger,
+			Twitter:   param.TwitterModel,
+			ReqClient: req.C(),
+		}
+	}
+}
+
+func (p *Provider) Source() published.Source {
+	return published.SourceTwitter
+}
+
+// Match 返回推文的 ID
+func (p *Provider) Match(link string) string {
+	return TweetIDFromText(publishing.LinkWithoutQuery(link))
+}
+
+func (p *Provider) FetchPost(tweetID string) (*publishing.Post, error) {
+	var tweet *twitter_public_types.TweetResultsResult
+	_, _, err := lo.AttemptWithDelay(10, time.Second, func(index int, duration time.Duration) error {
+		var err error
+		tweet, err = p.Twitter.GetOneTweet(tweetID)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if tweet == nil {
+		return nil, nil
+	}
+
+	post := &publishing.Post{
+		ID:        tweetID,
+		URL:       fmt.Sprintf("https://twitter.com/i/web/status/%s", tweetID),
+		Sensitive: tweet.PossiblySensitive(),
+		Raw:       tweet,
+	}
+
+	tweetAuthor := tweet.User()
+	if tweetAuthor != nil {
+		post.URL = fmt.Sprintf("https://twitter.com/%s/status/%s", tweetAuthor.ScreenName, tweetID)
+		post.Author = publishing.Author{
+			Name:   tweetAuthor.Name,
+			Handle: tweetAuthor.ScreenName,
+			URL:    fmt.Sprintf("https://twitter.com/%s", tweetAuthor.ScreenName),
+		}
+	}
+
+	return post, nil
+}
+
+func (p *Provider) ListMedia(post *publishing.Post) ([]*publishing.Media, error) {
+	tweet, ok := post.Raw.(*twitter_public_types.TweetResultsResult)
+	if !ok {
+		return nil, errors.New("post is not a tweet")
+	}
+
+	medias := make([]*publishing.Media, 0)
+	for i, media := range tweet.ExtendedMedias() {
+		switch media.Type {
+		case twitter_public_types.TweetLegacyExtendedEntityMediaTypePhoto:
+			if media.MediaURLHTTPS == "" {
+				continue
+			}
+
+			medias = append(medias, &publishing.Media{
+				Type:             publishing.MediaTypePhoto,
+				CacheKey:         media.MediaURLHTTPS,
+				PreviewURLs:      []string{media.MediaURLHTTPS},
+				OriginalURL:      tweetImageTo4kImage(media.MediaURLHTTPS),
+				FileName:         fmt.Sprintf("%s-%s", post.ID, filepath.Base(media.MediaURLHTTPS)),
+				OriginalFileName: originalFileName(post, i, media.MediaURLHTTPS),
+			})
+		case twitter_public_types.TweetLegacyExtendedEntityMediaTypeVideo,
+			twitter_public_types.TweetLegacyExtendedEntityMediaTypeAnimatedGIF:
+			videoMedia := p.videoMedia(post, i, media)
+			if videoMedia != nil {
+				medias = append(medias, videoMedia)
+			}
+		}
+	}
+
+	return medias, nil
+}
+
+// videoMedia 原视频使用码率最高的 MP4 变体，预览使用码率最高且满足上传限制的变体，HLS 播放列表无法直接发送
+func (p *Provider) videoMedia(post *publishing.Post, index int, media *twitter_public_types.ExtendedEntityMedia) *publishing.Media {
+	if media.VideoInfo == nil {
+		return nil
+	}
+
+	variants := media.VideoInfo.MP4Variants()
+	if len(variants) == 0 {
+		p.Logger.WithField("media_url", media.MediaURLHTTPS).Warn("no mp4 variant found in video")
+		return nil
+	}
+
+	mediaType := publishing.MediaTypeVideo
+	if media.Type == twitter_public_types.TweetLegacyExtendedEntityMediaTypeAnimatedGIF {
+		mediaType = publishing.MediaTypeAnimation
+	}
+
+	width, height := videoDimensions(media)
+
+	return &publishing.Media{
+		Type:     mediaType,
+		CacheKey: media.MediaURLHTTPS,
+		PreviewURLs: lo.Map(variants, func(variant twitter_public_types.ExtendedEntityMediaVideoVariant, _ int) string {
+			return variant.URL
+		}),
+		OriginalURL:      variants[0].URL,
+		FileName:         fmt.Sprintf("%s-%s", post.ID, publishing.URLBase(variants[0].URL)),
+		OriginalFileName: originalFileName(post, index, variants[0].URL),
+		// 推文中视频的 media_url_https 是视频的封面图
+		PosterURL: media.MediaURLHTTPS,
+		Width:     width,
+		Height:    height,
+		Duration:  media.VideoInfo.DurationMillis / 1000,
+	}
+}
+
+func (p *Provider) RenderCaption(post *publishing.Post, labels []string) string {
+	tweetAuthorInfo := "未知"
+	if post.Author.Handle != "" {
+		tweetAuthorInfo = fmt.Sprintf(`<a href="%s">%s (@%s)</a>`, post.Author.URL, post.Author.Name, post.Author.Handle)
+	}
+
+	var content string
+	tweet, ok := post.Raw.(*twitter_public_types.TweetResultsResult)
+	if ok {
+		content = tweet.DisplayTextWithURLsMappedEmbeddedInHTML()
+	}
+
+	return publishing.FormatCaption(tweetAuthorInfo, content, labels, "Twitter", post.URL)
+}
+
+func (p *Provider) Download(link string, w io.Writer) error {
+	resp, err := p.ReqClient.R().SetOutput(w).Get(link)
+	if err != nil {
+		return err
+	}
+	if !resp.IsSuccess() {
+		return fmt.Errorf("failed to fetch media from tweet, status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// videoDimensions 返回视频的宽高，推文中没有视频尺寸时使用 video_info 中的宽高比
+func videoDimensions(media *twitter_public_types.ExtendedEntityMedia) (int, int) {
+	if media.Sizes.Large.W > 0 && media.Sizes.Large.H > 0 {
+		return media.Sizes.Large.W, media.Sizes.Large.H
+	}
+	if media.VideoInfo != nil && len(media.VideoInfo.AspectRatio) == 2 {
+		return media.VideoInfo.AspectRatio[0], media.VideoInfo.AspectRatio[1]
+	}
+
+	return 0, 0
+}
+
+// originalFileName 返回讨论群组中原图、原视频的文件名
+func originalFileName(post *publishing.Post, index int, link string) string {
+	return fmt.Sprintf("twitter-by-%s-%s-%d%s", post.Author.Handle, post.ID, index, path.Ext(publishing.URLBase(link)))
+}
+
+var (
+	TweetLinkIDRegexp = regexp.MustCompile(`https://twitter.com/([^/]+)/status/(\d+)`)
+)
+
+func TweetIDFromText(text string) string {
+	matches := TweetLinkIDRegexp.FindStringSubmatch(text)
+	if len(matches) != 3 {
+		return ""
+	}
+
+	return matches[2]
+}
+
+// tweetImageTo4kImage 将推文中的图片链接转换为 4096x4096 的图片链接
+func tweetImageTo4kImage(imageLink string) string {
+	ext := filepath.Ext(imageLink)
+	linkWithoutExt := strings.TrimSuffix(imageLink, ext)
+	return fmt.Sprintf("%s?format=%s&name=4096x4096", linkWithoutExt, strings.TrimPrefix(ext, "."))
+}
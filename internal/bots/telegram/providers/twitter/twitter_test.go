@@ -0,0 +1,56 @@
+package twitter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	twitter_public_types "github.com/nekomeowww/perobot/pkg/twitter/public/types"
+)
+
+func TestTweetIDFromText(t *testing.T) {
+	assert := assert.New(t)
+
+	tweetID := TweetIDFromText("https://twitter.com/test_10/status/1234?query=test")
+	assert.Equal("1234", tweetID)
+
+	tweetID = TweetIDFromText("https://twitter.com/testaccount/status/1234?query=test")
+	assert.Equal("1234", tweetID)
+
+	tweetID = TweetIDFromText("https://twitter.com/testaccount/status/1234")
+	assert.Equal("1234", tweetID)
+}
+
+func TestMatch(t *testing.T) {
+	assert := assert.New(t)
+
+	provider := &Provider{}
+
+	assert.Equal("1234", provider.Match("https://twitter.com/alice/status/1234?s=20&t=abc"))
+	assert.Equal("1234", provider.Match("https://Twitter.com/alice/status/1234#reply"))
+	assert.Equal("1234", provider.Match("https://twitter.com/alice/status/1234/photo/1"))
+	assert.Equal("1234", provider.Match(" https://twitter.com/alice/status/1234\n"))
+	assert.Empty(provider.Match("https://twitter.com/alice?s=20"))
+	assert.Empty(provider.Match("twitter.com/alice/status/1234"))
+}
+
+func TestVideoDimensions(t *testing.T) {
+	assert := assert.New(t)
+
+	media := &twitter_public_types.ExtendedEntityMedia{
+		VideoInfo: &twitter_public_types.ExtendedEntityMediaVideoInfo{AspectRatio: []int{16, 9}},
+	}
+
+	width, height := videoDimensions(media)
+	assert.Equal(16, width)
+	assert.Equal(9, height)
+
+	media.Sizes.Large = twitter_public_types.EntityMediaSize{W: 1280, H: 720}
+	width, height = videoDimensions(media)
+	assert.Equal(1280, width)
+	assert.Equal(720, height)
+
+	width, height = videoDimensions(&twitter_public_types.ExtendedEntityMedia{})
+	assert.Zero(width)
+	assert.Zero(height)
+}
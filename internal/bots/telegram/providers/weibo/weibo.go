@@ -0,0 +1,209 @@
+package weibo
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"path"
+	"regexp"
+
+	"go.uber.org/fx"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	"github.com/nekomeowww/perobot/internal/models/published"
+	"github.com/nekomeowww/perobot/internal/thirdparty"
+	"github.com/nekomeowww/perobot/pkg/logger"
+	weibo_public "github.com/nekomeowww/perobot/pkg/weibo/public"
+	weibo_public_types "github.com/nekomeowww/perobot/pkg/weibo/public/types"
+)
+
+type NewProviderParam struct {
+	fx.In
+
+	Logger *logger.Logger
+	Weibo  *thirdparty.WeiboPublic
+}
+
+// Client Provider 所使用的微博接口，由 thirdparty.WeiboPublic 实现
+type Client interface {
+	GetStatus(id string) (*weibo_public_types.Status, error)
+	GetLongText(id string) (string, error)
+	Download(link string, w io.Writer) error
+}
+
+// Provider 发布微博中的原图、GIF 和视频
+type Provider struct {
+	Logger *logger.Logger
+	Weibo  Client
+}
+
+func NewProvider() func(param NewProviderParam) *Provider {
+	return func(param NewProviderParam) *Provider {
+		return &Provider{
+			Logger: param.Logger,
+			Weibo:  param.Weibo,
+		}
+	}
+}
+
+func (p *Provider) Source() published.Source {
+	return published.SourceWeibo
+}
+
+// Match 返回微博的数字 ID，链接中 base62 形式的 ID 会被转换为数字 ID，以便不同形式的链接被识别为同一条微博
+func (p *Provider) Match(link string) string {
+	return MIDFromText(publishing.LinkWithoutQuery(link))
+}
+
+func (p *Provider) FetchPost(postID string) (*publishing.Post, error) {
+	status, err := p.Weibo.GetStatus(postID)
+	if err != nil {
+		return nil, err
+	}
+	if status == nil {
+		return nil, nil
+	}
+
+	// 没有附带图片和视频的转发使用被转发的微博
+	if !status.HasMedia() && status.RetweetedStatus != nil && status.RetweetedStatus.HasMedia() {
+		status = status.RetweetedStatus
+	}
+	if status.IsLongText {
+		longText, err := p.Weibo.GetLongText(status.ID)
+		if err != nil {
+			p.Logger.WithField("weibo_id", status.ID).Warnf("failed to get long text, falling back to the truncated text, err: %v", err)
+		} else if longText != "" {
+			status.Text = longText
+		}
+	}
+
+	post := &publishing.Post{
+		ID:  postID,
+		URL: status.URL(),
+		Raw: status,
+	}
+	if status.User != nil {
+		post.Author = publishing.Author{
+			Name:   status.User.ScreenName,
+			Handle: fmt.Sprintf("%d", status.User.ID),
+			URL:    status.User.ProfileURL(),
+		}
+	}
+
+	return post, nil
+}
+
+// ListMedia 列出微博中的图片和视频，实况照片只发布其中的图片
+func (p *Provider) ListMedia(post *publishing.Post) ([]*publishing.Media, error) {
+	status, ok := post.Raw.(*weibo_public_types.Status)
+	if !ok {
+		return nil, errors.New("post is not a weibo status")
+	}
+
+	medias := make([]*publishing.Media, 0, len(status.Pics)+1)
+	for i, pic := range status.Pics {
+		largeURL := pic.LargeURL()
+		if largeURL == "" {
+			continue
+		}
+
+		ext := path.Ext(publishing.URLBase(largeURL))
+
+		media := &publishing.Media{
+			Type:             publishing.MediaTypePhoto,
+			CacheKey:         largeURL,
+			PreviewURLs:      []string{largeURL},
+			OriginalURL:      largeURL,
+			FileName:         publishing.URLBase(largeURL),
+			OriginalFileName: originalFileName(post, i, ext),
+		}
+		media.Width, media.Height = pic.Size()
+
+		if pic.IsGIF() {
+			media.Type = publishing.MediaTypeAnimation
+		} else {
+			// 原图过大无法发送时使用最长边为 2000 的版本
+			media.PreviewURLs = append(media.PreviewURLs, weibo_public_types.ResizedURL(largeURL, "mw2000"))
+		}
+
+		medias = append(medias, media)
+	}
+
+	if status.PageInfo.IsVideo() {
+		videoURLs := status.PageInfo.VideoURLs()
+		if len(videoURLs) == 0 {
+			p.Logger.WithField("weibo_id", status.ID).Warn("video has no playable url, skipping...")
+			return medias, nil
+		}
+
+		medias = append(medias, &publishing.Media{
+			Type:             publishing.MediaTypeVideo,
+			CacheKey:         videoURLs[0],
+			PreviewURLs:      videoURLs,
+			OriginalURL:      videoURLs[0],
+			FileName:         fmt.Sprintf("%s.mp4", status.ID),
+			OriginalFileName: originalFileName(post, len(medias), ".mp4"),
+			PosterURL:        status.PageInfo.PosterURL(),
+			Duration:         status.PageInfo.Duration(),
+		})
+	}
+
+	return medias, nil
+}
+
+func (p *Provider) RenderCaption(post *publishing.Post, labels []string) string {
+	authorInfo := "未知"
+	if post.Author.Name != "" {
+		authorInfo = fmt.Sprintf(`<a href="%s">%s</a>`, post.Author.URL, html.EscapeString(post.Author.Name))
+	}
+
+	var content string
+	status, ok := post.Raw.(*weibo_public_types.Status)
+	if ok {
+		content = status.TextInHTML()
+	}
+
+	return publishing.FormatCaption(authorInfo, content, labels, "微博", post.URL)
+}
+
+// Download 新浪图床需要带上微博的 Referer 才能下载
+func (p *Provider) Download(link string, w io.Writer) error {
+	return p.Weibo.Download(link, w)
+}
+
+// originalFileName 返回讨论群组中原图、原视频的文件名
+func originalFileName(post *publishing.Post, index int, ext string) string {
+	return fmt.Sprintf("weibo-by-%s-%s-%d%s", post.Author.Handle, post.ID, index, ext)
+}
+
+var (
+	// WeiboStatusLinkRegexp 网页版的微博链接 https://weibo.com/<uid>/<bid> 和 https://weibo.com/detail/<mid>
+	WeiboStatusLinkRegexp = regexp.MustCompile(`^https://(?:www\.)?weibo\.com/(?:detail|\d+)/([A-Za-z0-9]+)$`)
+	// MobileWeiboStatusLinkRegexp 移动版的微博链接 https://m.weibo.cn/detail/<mid>、https://m.weibo.cn/status/<bid> 和 https://m.weibo.cn/<uid>/<mid>
+	MobileWeiboStatusLinkRegexp = regexp.MustCompile(`^https://m\.weibo\.cn/(?:detail|status|\d+)/([A-Za-z0-9]+)$`)
+	// MIDRegexp 微博的数字 ID，base62 形式的 ID 只有 9 位左右，不会与之混淆
+	MIDRegexp = regexp.MustCompile(`^\d{10,}$`)
+)
+
+// MIDFromText 返回微博链接中微博的数字 ID，不是微博链接时返回空字符串
+func MIDFromText(text string) string {
+	for _, linkRegexp := range []*regexp.Regexp{WeiboStatusLinkRegexp, MobileWeiboStatusLinkRegexp} {
+		matches := linkRegexp.FindStringSubmatch(text)
+		if len(matches) != 2 {
+			continue
+		}
+		if MIDRegexp.MatchString(matches[1]) {
+			return matches[1]
+		}
+
+		mid, err := weibo_public.BIDToMID(matches[1])
+		if err != nil {
+			return ""
+		}
+
+		return mid
+	}
+
+	return ""
+}
@@ -0,0 +1,127 @@
+package weibo
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	"github.com/nekomeowww/perobot/internal/lib"
+	weibo_public_types "github.com/nekomeowww/perobot/pkg/weibo/public/types"
+)
+
+const testRetweetStatus = `{
+	"ok": 1,
+	"data": {
+		"id": "3501756485200075",
+		"bid": "z0JH2lOMb",
+		"text": "转发微博",
+		"user": { "id": 1111111111, "screen_name": "路人" },
+		"retweeted_status": {
+			"id": "3501756485200000",
+			"bid": "z0JH2lOKa",
+			"text": "视频和动图 ...<a href=\"/status/3501756485200000\">全文</a>",
+			"isLongText": true,
+			"user": { "id": 1234567890, "screen_name": "画师<3" },
+			"pics": [
+				{ "pid": "006gif", "url": "https://wx1.sinaimg.cn/orj360/006gif.gif" }
+			],
+			"page_info": {
+				"type": "video",
+				"page_pic": { "url": "https://wx1.sinaimg.cn/orj480/006poster.jpg" },
+				"urls": { "mp4_720p_mp4": "https://f.video.weibocdn.com/720.mp4" },
+				"media_info": { "stream_url": "https://f.video.weibocdn.com/ld.mp4", "duration": 12.5 }
+			}
+		}
+	}
+}`
+
+// fakeClient 返回固定微博的 Client，请求和解析由 weibo_public 的测试覆盖
+type fakeClient struct {
+	t *testing.T
+}
+
+func (c *fakeClient) GetStatus(id string) (*weibo_public_types.Status, error) {
+	if id != "3501756485200075" {
+		return nil, nil
+	}
+
+	var statusResp weibo_public_types.StatusResp
+	require.NoError(c.t, json.Unmarshal([]byte(testRetweetStatus), &statusResp))
+
+	return statusResp.Data, nil
+}
+
+func (c *fakeClient) GetLongText(id string) (string, error) {
+	assert.Equal(c.t, "3501756485200000", id)
+
+	return "视频和动图<br />完整版", nil
+}
+
+func (c *fakeClient) Download(link string, w io.Writer) error {
+	return errors.New("not implemented")
+}
+
+func TestMIDFromText(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("3501756485200075", MIDFromText("https://weibo.com/1234567890/z0JH2lOMb"))
+	assert.Equal("3501756485200075", MIDFromText("https://m.weibo.cn/detail/3501756485200075"))
+	assert.Equal("3501756485200075", MIDFromText("https://m.weibo.cn/status/z0JH2lOMb"))
+	assert.Equal("3501756485200075", MIDFromText("https://m.weibo.cn/1234567890/3501756485200075"))
+	assert.Empty(MIDFromText("https://weibo.com/u/1234567890"))
+	assert.Empty(MIDFromText("https://weibo.com/1234567890"))
+}
+
+func TestMatch(t *testing.T) {
+	assert := assert.New(t)
+
+	provider := &Provider{}
+
+	assert.Equal("3501756485200075", provider.Match("https://weibo.com/1234567890/z0JH2lOMb?type=comment#_rnd1700000000000"))
+	assert.Equal("3501756485200075", provider.Match("https://WWW.Weibo.com/1234567890/z0JH2lOMb"))
+	assert.Equal("3501756485200075", provider.Match("https://m.weibo.cn/detail/3501756485200075?sudaref=login.sina.com.cn"))
+	assert.Empty(provider.Match("https://weibo.com/u/1234567890?refer_flag=1001030103_"))
+	assert.Empty(provider.Match("https://m.weibo.cn/detail/?id=3501756485200075"))
+}
+
+func TestFetchPostAndListMedia(t *testing.T) {
+	provider := &Provider{Logger: lib.NewLogger()(), Weibo: &fakeClient{t: t}}
+
+	postID := provider.Match("https://weibo.com/1111111111/z0JH2lOMb?type=comment")
+	require.Equal(t, "3501756485200075", postID)
+
+	post, err := provider.FetchPost(postID)
+	require.NoError(t, err)
+	require.NotNil(t, post)
+	assert.Equal(t, "3501756485200075", post.ID)
+	assert.Equal(t, "https://weibo.com/1234567890/z0JH2lOKa", post.URL)
+	assert.Equal(t, "画师<3", post.Author.Name)
+
+	medias, err := provider.ListMedia(post)
+	require.NoError(t, err)
+	require.Len(t, medias, 2)
+
+	assert.Equal(t, publishing.MediaTypeAnimation, medias[0].Type)
+	assert.Equal(t, "https://wx1.sinaimg.cn/large/006gif.gif", medias[0].OriginalURL)
+	assert.Equal(t, "weibo-by-1234567890-3501756485200075-0.gif", medias[0].OriginalFileName)
+
+	assert.Equal(t, publishing.MediaTypeVideo, medias[1].Type)
+	assert.Equal(t, []string{"https://f.video.weibocdn.com/720.mp4", "https://f.video.weibocdn.com/ld.mp4"}, medias[1].PreviewURLs)
+	assert.Equal(t, "https://wx1.sinaimg.cn/orj480/006poster.jpg", medias[1].PosterURL)
+	assert.Equal(t, 12, medias[1].Duration)
+
+	assert.Equal(t, ""+
+		`<a href="https://weibo.com/u/1234567890">画师&lt;3</a>：`+"\n\n"+
+		"视频和动图\n完整版\n\n"+
+		`来自 <a href="https://weibo.com/1234567890/z0JH2lOKa">微博</a>`,
+		provider.RenderCaption(post, nil))
+
+	post, err = provider.FetchPost("3501756485200001")
+	require.NoError(t, err)
+	assert.Nil(t, post)
+}
@@ -0,0 +1,42 @@
+package publishing
+
+import (
+	"html"
+
+	"github.com/nekomeowww/perobot/internal/configs"
+)
+
+// Metadata 来源所提供的与频道发布政策相关的作品信息，各个来源尽可能填写
+type Metadata struct {
+	// AIGenerated 来源标记或作者声明作品为 AI 生成
+	AIGenerated bool
+}
+
+// Labels 按照频道配置处理作品信息的结果
+type Labels struct {
+	// RefusedReason 不为空时表示作品不允许发布，内容为展示给用户的原因
+	RefusedReason string
+	// Caption 需要附加到说明文字中的标签，已经转义为 HTML
+	Caption []string
+}
+
+// ApplyLabels 按照频道配置决定作品是否允许发布，以及说明文字中需要附加的标签
+func ApplyLabels(channelConfig configs.ChannelConfig, metadata Metadata) Labels {
+	labels := Labels{
+		Caption: make([]string, 0),
+	}
+
+	if metadata.AIGenerated {
+		switch channelConfig.AIGenerated.Action {
+		case configs.AIGeneratedActionRefuse:
+			labels.RefusedReason = "这个作品被标记为 AI 生成，频道设置不允许发布"
+			return labels
+		case configs.AIGeneratedActionLabel:
+			if channelConfig.AIGenerated.LabelText() != "" {
+				labels.Caption = append(labels.Caption, html.EscapeString(channelConfig.AIGenerated.LabelText()))
+			}
+		}
+	}
+
+	return labels
+}
@@ -0,0 +1,45 @@
+package publishing
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nekomeowww/perobot/internal/configs"
+)
+
+func TestApplyLabels(t *testing.T) {
+	channelConfig := configs.ChannelConfig{
+		AIGenerated: configs.AIGeneratedConfig{
+			Action: configs.AIGeneratedActionLabel,
+			Label:  lo.ToPtr("#AI生成"),
+		},
+	}
+
+	labels := ApplyLabels(channelConfig, Metadata{})
+	assert.Empty(t, labels.RefusedReason)
+	assert.Empty(t, labels.Caption)
+
+	labels = ApplyLabels(channelConfig, Metadata{AIGenerated: true})
+	assert.Empty(t, labels.RefusedReason)
+	assert.Equal(t, []string{"#AI生成"}, labels.Caption)
+
+	channelConfig.AIGenerated.Label = lo.ToPtr("")
+	labels = ApplyLabels(channelConfig, Metadata{AIGenerated: true})
+	assert.Empty(t, labels.Caption)
+
+	// 没有经过 withDefaults 的配置中 Label 可能为 nil
+	channelConfig.AIGenerated.Label = nil
+	labels = ApplyLabels(channelConfig, Metadata{AIGenerated: true})
+	assert.Empty(t, labels.Caption)
+
+	channelConfig.AIGenerated.Action = configs.AIGeneratedActionNone
+	labels = ApplyLabels(channelConfig, Metadata{AIGenerated: true})
+	assert.Empty(t, labels.RefusedReason)
+	assert.Empty(t, labels.Caption)
+
+	channelConfig.AIGenerated.Action = configs.AIGeneratedActionRefuse
+	labels = ApplyLabels(channelConfig, Metadata{AIGenerated: true})
+	assert.NotEmpty(t, labels.RefusedReason)
+}
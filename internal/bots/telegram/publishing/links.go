@@ -0,0 +1,31 @@
+package publishing
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// LinkWithoutQuery 返回只包含协议、域名和路径的链接，域名转换为小写，去掉用户信息、查询参数和锚点，
+// 供各个来源的 Match 与作品链接的正则表达式比较，不是绝对链接时返回空字符串
+func LinkWithoutQuery(link string) string {
+	parsedURL, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return ""
+	}
+	if parsedURL.Scheme == "" || parsedURL.Host == "" {
+		return ""
+	}
+
+	return parsedURL.Scheme + "://" + strings.ToLower(parsedURL.Host) + parsedURL.Path
+}
+
+// URLBase 返回链接路径的最后一段，不包含查询参数和锚点，常用于从媒体链接中取得文件名
+func URLBase(link string) string {
+	parsedURL, err := url.Parse(link)
+	if err != nil {
+		return path.Base(link)
+	}
+
+	return path.Base(parsedURL.Path)
+}
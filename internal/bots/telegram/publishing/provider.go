@@ -0,0 +1,163 @@
+package publishing
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/nekomeowww/perobot/internal/configs"
+	"github.com/nekomeowww/perobot/internal/models/published"
+	"github.com/nekomeowww/perobot/pkg/spool"
+)
+
+// MediaType 媒体的类型，决定了媒体在频道中的发送方式
+type MediaType string
+
+const (
+	MediaTypePhoto MediaType = "photo"
+	MediaTypeVideo MediaType = "video"
+	// MediaTypeAnimation 动画无法与照片和视频放在同一个相册中，会在相册之后单独发送
+	MediaTypeAnimation MediaType = "animation"
+)
+
+// Author 作品的作者
+type Author struct {
+	// Name 作者的显示名称
+	Name string
+	// Handle 作者的用户名，没有时为空
+	Handle string
+	// URL 作者主页的链接
+	URL string
+}
+
+// Post 来源中的一个作品
+type Post struct {
+	// ID 作品在来源中的 ID，用于记录已经发布过的作品
+	ID string
+	// URL 作品的链接，附在说明文字的末尾
+	URL string
+	// Author 作品的作者
+	Author Author
+	// Metadata 与频道发布政策相关的作品信息
+	Metadata Metadata
+	// Sensitive 来源将作品标记为敏感内容
+	Sensitive bool
+	// SensitiveLabel 附加在内容警告之后的来源的分级，例如 R-18，没有时为空
+	SensitiveLabel string
+	// Raw 来源的原始数据，由 Provider 自行使用
+	Raw any
+}
+
+// Media 作品中需要发布的一个媒体
+type Media struct {
+	Type MediaType
+	// CacheKey 缓存已经上传到 Telegram 的 file_id 所使用的键，同一个媒体的预览和原图使用相同的键
+	CacheKey string
+	// PreviewURLs 频道中预览所使用的候选链接，按照优先顺序排列，使用第一个满足 Telegram 限制的链接
+	PreviewURLs []string
+	// OriginalURL 讨论群组中以文件的形式发送的原图、原视频，与某个预览的链接相同时只下载一次，为空时不发送
+	OriginalURL string
+	// FileName 预览上传时的文件名
+	FileName string
+	// OriginalFileName 原图、原视频上传时的文件名
+	OriginalFileName string
+	// PosterURL 视频和动画的封面图，用于生成缩略图，没有时为空
+	PosterURL string
+	Width     int
+	Height    int
+	// Duration 视频和动画的时长，单位为秒，未知时为 0
+	Duration int
+	// Description 可选，媒体的替代文字，作为讨论群组中原图、原视频的说明文字
+	Description string
+	// ConvertPreview 可选，将下载的预览转换为发送到频道的文件，并返回用于计算感知哈希的图片，例如将动图的帧压缩包转换为 GIF
+	// 并返回第一帧，没有可用的图片时返回 nil
+	ConvertPreview func(preview *spool.File) (*spool.File, image.Image, error)
+	// Download 可选，下载该媒体的预览、原图和封面图所使用的函数，为空时使用 Provider.Download，
+	// 用于同一个来源中不同类型的作品需要使用不同的客户端下载的情况
+	Download func(link string, w io.Writer) error
+	// Fallback 可选，媒体无法下载或转换时改为发布的媒体
+	Fallback []*Media
+}
+
+// Provider 一个来源网站，实现后即可通过统一的流程将该来源的作品发布到频道
+type Provider interface {
+	// Source 来源的名称，用于记录已经发布过的作品
+	Source() published.Source
+	// Match 返回 url 所指向的作品的 ID，不是该来源的作品链接时返回空字符串
+	Match(url string) string
+	// FetchPost 获取作品的信息
+	FetchPost(id string) (*Post, error)
+	// ListMedia 列出作品中需要发布的媒体，只有通过频道的过滤规则和发布政策的作品才会调用
+	ListMedia(post *Post) ([]*Media, error)
+	// RenderCaption 生成作品在频道中的说明文字，labels 为频道的发布政策附加的已经转义为 HTML 的标签
+	RenderCaption(post *Post, labels []string) string
+	// Download 将来源中的媒体 link 下载到 w
+	Download(link string, w io.Writer) error
+}
+
+// PostFilter 可选，在下载媒体之前按照频道的过滤规则检查作品的 Provider
+type PostFilter interface {
+	// FilterPost 作品被拒绝时返回可以直接展示给用户的原因，允许发布时返回空字符串
+	FilterPost(channelConfig configs.ChannelConfig, post *Post) string
+}
+
+// ChannelMatcher 可选，只在频道的配置允许时才匹配链接的 Provider，例如需要频道主动启用的兜底来源
+type ChannelMatcher interface {
+	// MatchChannel 判断频道是否允许由该 Provider 发布 url 所指向的作品
+	MatchChannel(channelConfig configs.ChannelConfig, url string) bool
+}
+
+// Providers 所有的 Provider
+type Providers []Provider
+
+// Match 返回可以在频道中发布 url 所指向作品的 Provider 和作品的 ID，不存在时返回 nil
+func (p Providers) Match(channelConfig configs.ChannelConfig, url string) (Provider, string) {
+	for _, provider := range p {
+		if channelMatcher, ok := provider.(ChannelMatcher); ok && !channelMatcher.MatchChannel(channelConfig, url) {
+			continue
+		}
+
+		id := provider.Match(url)
+		if id != "" {
+			return provider, id
+		}
+	}
+
+	return nil, ""
+}
+
+// FormatCaption 按照频道统一的格式生成说明文字，author 和 content 需要已经转义为 HTML，tags 需要包含 #
+func FormatCaption(author string, content string, tags []string, sourceName string, sourceURL string) string {
+	caption := author
+	if content != "" {
+		caption += "：\n\n" + content
+	}
+	if len(tags) > 0 {
+		caption += "\n\n" + strings.Join(tags, " ")
+	}
+
+	return caption + fmt.Sprintf("\n\n"+`来自 <a href="%s">%s</a>`, sourceURL, sourceName)
+}
+
+// Hashtag 将标签转换为 Telegram 的话题标签，字母和数字以外的字符替换为 _，连续的 _ 合并为一个
+func Hashtag(tag string) string {
+	var builder strings.Builder
+
+	lastUnderscore := true
+	for _, r := range tag {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			builder.WriteRune(r)
+			lastUnderscore = false
+
+			continue
+		}
+		if !lastUnderscore {
+			builder.WriteRune('_')
+			lastUnderscore = true
+		}
+	}
+
+	return strings.TrimSuffix(builder.String(), "_")
+}
@@ -0,0 +1,25 @@
+package publishing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatCaption(t *testing.T) {
+	caption := FormatCaption("作者", "内容", []string{"#标签", "#AI生成"}, "Pixiv", "https://www.pixiv.net/artworks/1234")
+	assert.Equal(t, "作者：\n\n内容\n\n#标签 #AI生成\n\n"+`来自 <a href="https://www.pixiv.net/artworks/1234">Pixiv</a>`, caption)
+
+	caption = FormatCaption("作者", "", nil, "Twitter", "https://twitter.com/i/web/status/1234")
+	assert.Equal(t, "作者\n\n"+`来自 <a href="https://twitter.com/i/web/status/1234">Twitter</a>`, caption)
+}
+
+func TestHashtag(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("hatsune_miku", Hashtag("hatsune_miku"))
+	assert.Equal("saber_fate", Hashtag("saber_(fate)"))
+	assert.Equal("fate_grand_order", Hashtag("fate/grand_order"))
+	assert.Equal("初音ミク", Hashtag("初音ミク"))
+	assert.Empty(Hashtag(":)"))
+}
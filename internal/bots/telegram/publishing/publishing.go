@@ -0,0 +1,33 @@
+package publishing
+
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Request 将来源作品发布到频道的请求
+type Request struct {
+	// Chat 发布的目标频道
+	Chat *tgbotapi.Chat
+	// URL 来源作品的链接
+	URL string
+	// Force 是否跳过重复作品的检测强制发布
+	Force bool
+	// NoticeChatID 提示消息（例如作品已经发布过）所发送到的会话
+	NoticeChatID int64
+	// NoticeReplyToMessageID 提示消息所回复的消息，为 0 时不回复
+	NoticeReplyToMessageID int
+	// CommandMessageID 频道中发布命令的消息，发布成功后会被删除，为 0 时不删除
+	CommandMessageID int
+}
+
+// NewChannelPostRequest 创建由频道中的发布命令触发的请求，提示消息会回复发布命令
+func NewChannelPostRequest(channelPost *tgbotapi.Message, url string, force bool) *Request {
+	return &Request{
+		Chat:                   channelPost.Chat,
+		URL:                    url,
+		Force:                  force,
+		NoticeChatID:           channelPost.Chat.ID,
+		NoticeReplyToMessageID: channelPost.MessageID,
+		CommandMessageID:       channelPost.MessageID,
+	}
+}
@@ -0,0 +1,180 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+
+	"github.com/nekomeowww/perobot/internal/bots/telegram/handlers/pipeline"
+	"github.com/nekomeowww/perobot/internal/bots/telegram/publishing"
+	"github.com/nekomeowww/perobot/internal/configs"
+	"github.com/nekomeowww/perobot/internal/models/postqueue"
+	"github.com/nekomeowww/perobot/pkg/logger"
+)
+
+const (
+	// tickInterval 检查发布队列的间隔
+	tickInterval = 30 * time.Second
+)
+
+func NewModules() fx.Option {
+	return fx.Options(
+		fx.Provide(NewScheduler()),
+	)
+}
+
+type NewSchedulerParam struct {
+	fx.In
+
+	Logger         *logger.Logger
+	ChannelsConfig *configs.ChannelsConfig
+	PostQueue      *postqueue.Model
+	Pipeline       *pipeline.Handler
+}
+
+// Scheduler 按照频道的配置定时发布发布队列中的作品
+type Scheduler struct {
+	Logger         *logger.Logger
+	ChannelsConfig *configs.ChannelsConfig
+	PostQueue      *postqueue.Model
+	Pipeline       *pipeline.Handler
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewScheduler() func(param NewSchedulerParam) *Scheduler {
+	return func(param NewSchedulerParam) *Scheduler {
+		return &Scheduler{
+			Logger:         param.Logger,
+			ChannelsConfig: param.ChannelsConfig,
+			PostQueue:      param.PostQueue,
+			Pipeline:       param.Pipeline,
+		}
+	}
+}
+
+// Start 在后台开始定时检查发布队列
+func (s *Scheduler) Start(bot *tgbotapi.BotAPI) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go s.run(ctx, bot)
+}
+
+// Stop 停止检查发布队列，并等待正在进行的发布完成
+func (s *Scheduler) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+
+	s.cancel()
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, bot *tgbotapi.BotAPI) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	s.Logger.Info("post queue scheduler started")
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, channelID := range s.PostQueue.Channels() {
+				if ctx.Err() != nil {
+					break
+				}
+
+				s.publishDue(bot, channelID)
+			}
+		case <-ctx.Done():
+			s.Logger.Info("post queue scheduler stopped")
+			return
+		}
+	}
+}
+
+// publishDue 发布频道 channelID 的发布队列中应当发布的作品
+func (s *Scheduler) publishDue(bot *tgbotapi.BotAPI, channelID int64) {
+	logEntry := s.Logger.WithField("channel_id", channelID)
+
+	items, err := s.PostQueue.List(channelID)
+	if err != nil {
+		logEntry.Errorf("failed to list post queue, err: %v", err)
+		return
+	}
+
+	now := time.Now()
+	queueConfig := s.ChannelsConfig.Channel(channelID).Queue
+
+	item := postqueue.Due(items, now, s.PostQueue.LastPublishedAt(channelID), queueConfig)
+	if item == nil {
+		return
+	}
+
+	logEntry = logEntry.WithFields(logrus.Fields{
+		"queue_item_id": item.ID,
+		"url":           item.URL,
+	})
+
+	// 无论发布是否成功都移出队列，避免同一个作品反复发布失败而阻塞队列
+	_, err = s.PostQueue.Remove(channelID, item.ID)
+	if err != nil {
+		logEntry.Errorf("failed to remove item from post queue, err: %v", err)
+		return
+	}
+
+	s.PostQueue.SetLastPublishedAt(channelID, now)
+
+	if !s.Pipeline.Match(channelID, item.URL) {
+		logEntry.Warn("no provider matches the queued url, dropped")
+		s.notice(bot, item, fmt.Sprintf("#%d 的链接不受支持，已移出队列", item.ID), logEntry)
+
+		return
+	}
+
+	chat, err := bot.GetChat(tgbotapi.ChatInfoConfig{
+		ChatConfig: tgbotapi.ChatConfig{
+			ChatID: channelID,
+		},
+	})
+	if err != nil {
+		logEntry.Errorf("failed to get channel, err: %v", err)
+		s.notice(bot, item, fmt.Sprintf("#%d 发布失败，无法获取频道信息", item.ID), logEntry)
+
+		return
+	}
+
+	logEntry.Info("publishing queued item...")
+	s.Pipeline.Publish(bot, &publishing.Request{
+		Chat:                   &chat,
+		URL:                    item.URL,
+		Force:                  item.Force,
+		NoticeChatID:           item.StagingChatID,
+		NoticeReplyToMessageID: item.StagingMessageID,
+	})
+}
+
+func (s *Scheduler) notice(bot *tgbotapi.BotAPI, item *postqueue.Item, text string, logEntry *logrus.Entry) {
+	message := tgbotapi.NewMessage(item.StagingChatID, text)
+	message.ReplyToMessageID = item.StagingMessageID
+
+	_, err := bot.Send(message)
+	if err != nil {
+		logEntry.Errorf("failed to send notice to staging chat, err: %v", err)
+	}
+}
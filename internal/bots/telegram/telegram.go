@@ -11,7 +11,10 @@ import (
 
 	"github.com/nekomeowww/perobot/internal/bots/telegram/dispatcher"
 	"github.com/nekomeowww/perobot/internal/bots/telegram/handlers"
+	"github.com/nekomeowww/perobot/internal/bots/telegram/providers"
+	"github.com/nekomeowww/perobot/internal/bots/telegram/scheduler"
 	"github.com/nekomeowww/perobot/internal/configs"
+	bots_telegram "github.com/nekomeowww/perobot/pkg/bots/telegram"
 	"github.com/nekomeowww/perobot/pkg/handler"
 	"github.com/nekomeowww/perobot/pkg/logger"
 	"github.com/nekomeowww/perobot/pkg/utils"
@@ -21,7 +24,9 @@ func NewModules() fx.Option {
 	return fx.Options(
 		fx.Provide(NewBot()),
 		fx.Options(dispatcher.NewModules()),
+		fx.Options(providers.NewModules()),
 		fx.Options(handlers.NewModules()),
+		fx.Options(scheduler.NewModules()),
 	)
 }
 
@@ -34,6 +39,7 @@ type NewBotParam struct {
 	Logger     *logger.Logger
 	Dispatcher *dispatcher.Dispatcher
 	Handlers   *handlers.Handlers
+	Scheduler  *scheduler.Scheduler
 }
 
 type Bot struct {
@@ -53,10 +59,16 @@ func NewBot() func(param NewBotParam) (*Bot, error) {
 			param.Logger.Fatal("must supply a valid telegram bot token in configs or environment variable")
 		}
 
-		b, err := tgbotapi.NewBotAPI(param.Config.TelegramBotToken)
+		b, err := tgbotapi.NewBotAPIWithAPIEndpoint(
+			param.Config.TelegramBotToken,
+			bots_telegram.BotAPIEndpoint(param.Config.TelegramBotAPIEndpoint),
+		)
 		if err != nil {
 			return nil, err
 		}
+		if param.Config.TelegramBotAPIEndpoint != "" {
+			param.Logger.Infof("using self-hosted telegram bot api server: %s", param.Config.TelegramBotAPIEndpoint)
+		}
 
 		bot := &Bot{
 			BotAPI:     b,
@@ -71,6 +83,15 @@ func NewBot() func(param NewBotParam) (*Bot, error) {
 				return nil
 			},
 		})
+		param.Lifecycle.Append(fx.Hook{
+			OnStart: func(ctx context.Context) error {
+				param.Scheduler.Start(bot.BotAPI)
+				return nil
+			},
+			OnStop: func(ctx context.Context) error {
+				return param.Scheduler.Stop(ctx)
+			},
+		})
 
 		param.Logger.Infof("Authorized as bot @%s", bot.Self.UserName)
 		param.Handlers.RegisterHandlers()
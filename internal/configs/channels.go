@@ -0,0 +1,288 @@
+package configs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+const (
+	DefaultChannelsConfigFileName = "channels.json"
+)
+
+// DuplicateAction 发现与近期发布的作品视觉上相同时的处理方式
+type DuplicateAction string
+
+const (
+	// DuplicateActionOff 不检测
+	DuplicateActionOff DuplicateAction = "off"
+	// DuplicateActionWarn 照常发布，并在频道中回复提示相似的已有消息
+	DuplicateActionWarn DuplicateAction = "warn"
+	// DuplicateActionSkip 不发布，并回复相似的已有消息，/t! 命令可以强制发布
+	DuplicateActionSkip DuplicateAction = "skip"
+)
+
+// DuplicatesConfig 基于感知哈希的重复作品检测配置
+type DuplicatesConfig struct {
+	Action DuplicateAction `json:"action"`
+	// Threshold 两张图片的 dHash 汉明距离不超过该值时视为相同，取值范围为 0 到 64
+	Threshold *int `json:"threshold"`
+	// Window 与最近发布的多少张图片进行比较
+	Window int `json:"window"`
+}
+
+// SensitiveAction 发布来源标记为敏感内容的作品时的处理方式
+type SensitiveAction string
+
+const (
+	// SensitiveActionNone 照常发布
+	SensitiveActionNone SensitiveAction = "none"
+	// SensitiveActionSpoiler 为照片和视频加上剧透遮罩
+	SensitiveActionSpoiler SensitiveAction = "spoiler"
+	// SensitiveActionRefuse 拒绝发布
+	SensitiveActionRefuse SensitiveAction = "refuse"
+)
+
+// SensitiveConfig 敏感内容的处理配置，作品是否敏感由 Pixiv 的 xRestrict、sl 以及 Twitter 的 possibly_sensitive 决定
+type SensitiveConfig struct {
+	Action SensitiveAction `json:"action"`
+	// Warning 发布敏感作品时在说明文字开头附加的内容警告，为空字符串时不附加
+	Warning *string `json:"warning"`
+}
+
+// WarningText 返回内容警告，未填写时返回空字符串
+func (c SensitiveConfig) WarningText() string {
+	if c.Warning == nil {
+		return ""
+	}
+
+	return *c.Warning
+}
+
+// AIGeneratedAction 发布 AI 生成的作品时的处理方式
+type AIGeneratedAction string
+
+const (
+	// AIGeneratedActionNone 照常发布
+	AIGeneratedActionNone AIGeneratedAction = "none"
+	// AIGeneratedActionLabel 在说明文字中附加标签
+	AIGeneratedActionLabel AIGeneratedAction = "label"
+	// AIGeneratedActionRefuse 拒绝发布
+	AIGeneratedActionRefuse AIGeneratedAction = "refuse"
+)
+
+// AIGeneratedConfig AI 生成作品的处理配置，作品是否为 AI 生成由来源提供的信息决定，例如 Pixiv 的 aiType
+type AIGeneratedConfig struct {
+	Action AIGeneratedAction `json:"action"`
+	// Label 附加到说明文字中的标签，例如 #AI生成
+	Label *string `json:"label"`
+}
+
+// LabelText 返回附加到说明文字中的标签，未填写时返回空字符串
+func (c AIGeneratedConfig) LabelText() string {
+	if c.Label == nil {
+		return ""
+	}
+
+	return *c.Label
+}
+
+// ChannelConfig 单个频道的配置
+type ChannelConfig struct {
+	Duplicates  DuplicatesConfig  `json:"duplicates"`
+	Queue       QueueConfig       `json:"queue"`
+	Sensitive   SensitiveConfig   `json:"sensitive"`
+	Filters     FiltersConfig     `json:"filters"`
+	AIGenerated AIGeneratedConfig `json:"ai_generated"`
+	OpenGraph   OpenGraphConfig   `json:"opengraph"`
+}
+
+// ChannelsConfig 各个频道的配置，未单独配置的频道以及未填写的字段使用 Default 中的值
+//
+//	{
+//	  "default": { "duplicates": { "action": "warn", "threshold": 6 } },
+//	  "channels": {
+//	    "-1001234567890": { "duplicates": { "action": "skip" } }
+//	  }
+//	}
+type ChannelsConfig struct {
+	Default  ChannelConfig           `json:"default"`
+	Channels map[int64]ChannelConfig `json:"channels"`
+}
+
+func defaultChannelConfig() ChannelConfig {
+	threshold := 6
+	warning := "⚠️ 内容警告"
+	maxXRestrict := 2
+	aiGeneratedLabel := "#AI生成"
+	openGraphEnabled := false
+
+	return ChannelConfig{
+		Duplicates: DuplicatesConfig{
+			Action:    DuplicateActionWarn,
+			Threshold: &threshold,
+			Window:    500,
+		},
+		Queue: QueueConfig{
+			Interval: Duration(30 * time.Minute),
+			Timezone: "UTC",
+		},
+		Sensitive: SensitiveConfig{
+			Action:  SensitiveActionSpoiler,
+			Warning: &warning,
+		},
+		Filters: FiltersConfig{
+			Pixiv: PixivFiltersConfig{
+				MaxXRestrict: &maxXRestrict,
+			},
+		},
+		AIGenerated: AIGeneratedConfig{
+			Action: AIGeneratedActionLabel,
+			Label:  &aiGeneratedLabel,
+		},
+		OpenGraph: OpenGraphConfig{
+			Enabled: &openGraphEnabled,
+		},
+	}
+}
+
+// withDefaults 使用 defaults 中的值填充未填写的字段
+func (c ChannelConfig) withDefaults(defaults ChannelConfig) ChannelConfig {
+	if c.Duplicates.Action == "" {
+		c.Duplicates.Action = defaults.Duplicates.Action
+	}
+	if c.Duplicates.Threshold == nil {
+		c.Duplicates.Threshold = defaults.Duplicates.Threshold
+	}
+	if c.Duplicates.Window <= 0 {
+		c.Duplicates.Window = defaults.Duplicates.Window
+	}
+	if c.Queue.Interval <= 0 {
+		c.Queue.Interval = defaults.Queue.Interval
+	}
+	if c.Queue.QuietHours == nil {
+		c.Queue.QuietHours = defaults.Queue.QuietHours
+	}
+	if c.Queue.Timezone == "" {
+		c.Queue.Timezone = defaults.Queue.Timezone
+	}
+	if c.Sensitive.Action == "" {
+		c.Sensitive.Action = defaults.Sensitive.Action
+	}
+	if c.Sensitive.Warning == nil {
+		c.Sensitive.Warning = defaults.Sensitive.Warning
+	}
+
+	c.Filters.Pixiv = c.Filters.Pixiv.withDefaults(defaults.Filters.Pixiv)
+	if c.AIGenerated.Action == "" {
+		c.AIGenerated.Action = defaults.AIGenerated.Action
+	}
+	if c.AIGenerated.Label == nil {
+		c.AIGenerated.Label = defaults.AIGenerated.Label
+	}
+
+	c.OpenGraph = c.OpenGraph.withDefaults(defaults.OpenGraph)
+
+	return c
+}
+
+// migrated 将已废弃的 filters.pixiv.block_ai_generated 转换为 ai_generated.action，明确设置了 action 时以 action 为准
+func (c ChannelConfig) migrated() ChannelConfig {
+	if c.Filters.Pixiv.BlockAIGenerated != nil && *c.Filters.Pixiv.BlockAIGenerated && c.AIGenerated.Action == "" {
+		c.AIGenerated.Action = AIGeneratedActionRefuse
+	}
+
+	c.Filters.Pixiv.BlockAIGenerated = nil
+
+	return c
+}
+
+// Channel 返回频道 chatID 的配置
+func (c *ChannelsConfig) Channel(chatID int64) ChannelConfig {
+	channelConfig, ok := c.Channels[chatID]
+	if !ok {
+		return c.Default
+	}
+
+	return channelConfig.withDefaults(c.Default)
+}
+
+// StagingChannel 返回以 chatID 作为暂存会话的频道，不存在时返回 false
+func (c *ChannelsConfig) StagingChannel(chatID int64) (int64, bool) {
+	channelIDs := lo.Keys(c.Channels)
+	sort.Slice(channelIDs, func(i, j int) bool { return channelIDs[i] < channelIDs[j] })
+
+	for _, channelID := range channelIDs {
+		if c.Channels[channelID].Queue.StagingChatID == chatID {
+			return channelID, true
+		}
+	}
+
+	return 0, false
+}
+
+// LoadChannelsConfig 从 path 读取各个频道的配置，文件不存在时所有频道都使用默认配置
+func LoadChannelsConfig(path string) (*ChannelsConfig, error) {
+	channelsConfig := &ChannelsConfig{
+		Channels: make(map[int64]ChannelConfig),
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if len(content) > 0 {
+		err = json.Unmarshal(content, channelsConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse channels config file %s: %w", path, err)
+		}
+	}
+
+	channelsConfig.Default = channelsConfig.Default.migrated()
+	for chatID, channelConfig := range channelsConfig.Channels {
+		channelsConfig.Channels[chatID] = channelConfig.migrated()
+	}
+
+	channelsConfig.Default = channelsConfig.Default.withDefaults(defaultChannelConfig())
+
+	err = channelsConfig.Default.Queue.validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid default queue config: %w", err)
+	}
+
+	err = channelsConfig.Default.Filters.Pixiv.validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid default pixiv filters config: %w", err)
+	}
+
+	for chatID := range channelsConfig.Channels {
+		err = channelsConfig.Channel(chatID).Queue.validate()
+		if err != nil {
+			return nil, fmt.Errorf("invalid queue config of channel %d: %w", chatID, err)
+		}
+
+		err = channelsConfig.Channel(chatID).Filters.Pixiv.validate()
+		if err != nil {
+			return nil, fmt.Errorf("invalid pixiv filters config of channel %d: %w", chatID, err)
+		}
+	}
+
+	return channelsConfig, nil
+}
+
+func NewChannelsConfig() func(config *Config) (*ChannelsConfig, error) {
+	return func(config *Config) (*ChannelsConfig, error) {
+		path := config.ChannelsConfigPath
+		if path == "" {
+			path = filepath.Join(config.DataDir, DefaultChannelsConfigFileName)
+		}
+
+		return LoadChannelsConfig(path)
+	}
+}
@@ -0,0 +1,170 @@
+package configs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadChannelsConfig(t *testing.T) {
+	t.Run("NotExist", func(t *testing.T) {
+		channelsConfig, err := LoadChannelsConfig(filepath.Join(t.TempDir(), "channels.json"))
+		require.NoError(t, err)
+
+		channelConfig := channelsConfig.Channel(-1001234567890)
+		assert.Equal(t, DuplicateActionWarn, channelConfig.Duplicates.Action)
+		assert.Equal(t, 6, *channelConfig.Duplicates.Threshold)
+		assert.Equal(t, 500, channelConfig.Duplicates.Window)
+		assert.Equal(t, SensitiveActionSpoiler, channelConfig.Sensitive.Action)
+		assert.NotEmpty(t, channelConfig.Sensitive.WarningText())
+		assert.Equal(t, AIGeneratedActionLabel, channelConfig.AIGenerated.Action)
+		assert.Equal(t, "#AI生成", channelConfig.AIGenerated.LabelText())
+	})
+
+	t.Run("Override", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "channels.json")
+		err := os.WriteFile(path, []byte(`{
+			"default": { "duplicates": { "threshold": 4 } },
+			"channels": {
+				"-1001234567890": {
+					"duplicates": { "action": "skip", "threshold": 0 },
+					"sensitive": { "action": "refuse", "warning": "" }
+				}
+			}
+		}`), 0644)
+		require.NoError(t, err)
+
+		channelsConfig, err := LoadChannelsConfig(path)
+		require.NoError(t, err)
+
+		channelConfig := channelsConfig.Channel(-1001234567890)
+		assert.Equal(t, DuplicateActionSkip, channelConfig.Duplicates.Action)
+		assert.Equal(t, 0, *channelConfig.Duplicates.Threshold)
+		assert.Equal(t, 500, channelConfig.Duplicates.Window)
+		assert.Equal(t, SensitiveActionRefuse, channelConfig.Sensitive.Action)
+		assert.Empty(t, *channelConfig.Sensitive.Warning)
+
+		channelConfig = channelsConfig.Channel(-1009876543210)
+		assert.Equal(t, DuplicateActionWarn, channelConfig.Duplicates.Action)
+		assert.Equal(t, 4, *channelConfig.Duplicates.Threshold)
+	})
+
+	t.Run("StagingChannel", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "channels.json")
+		err := os.WriteFile(path, []byte(`{
+			"channels": {
+				"-1001234567890": { "queue": { "staging_chat_id": 1234, "interval": "1h", "timezone": "Asia/Shanghai" } }
+			}
+		}`), 0644)
+		require.NoError(t, err)
+
+		channelsConfig, err := LoadChannelsConfig(path)
+		require.NoError(t, err)
+
+		channelID, ok := channelsConfig.StagingChannel(1234)
+		assert.True(t, ok)
+		assert.Equal(t, int64(-1001234567890), channelID)
+
+		_, ok = channelsConfig.StagingChannel(5678)
+		assert.False(t, ok)
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "channels.json")
+		err := os.WriteFile(path, []byte(`{
+			"default": { "filters": { "pixiv": { "blocked_tags": ["R-18G"] } } },
+			"channels": {
+				"-1001234567890": { "filters": { "pixiv": { "block_ai_generated": true, "max_x_restrict": 0 } } }
+			}
+		}`), 0644)
+		require.NoError(t, err)
+
+		channelsConfig, err := LoadChannelsConfig(path)
+		require.NoError(t, err)
+
+		channelConfig := channelsConfig.Channel(-1001234567890)
+		assert.Equal(t, AIGeneratedActionRefuse, channelConfig.AIGenerated.Action)
+		assert.Equal(t, 0, *channelConfig.Filters.Pixiv.MaxXRestrict)
+		assert.Equal(t, []string{"R-18G"}, channelConfig.Filters.Pixiv.BlockedTags)
+
+		channelConfig = channelsConfig.Channel(-1009876543210)
+		assert.Equal(t, AIGeneratedActionLabel, channelConfig.AIGenerated.Action)
+		assert.Equal(t, 2, *channelConfig.Filters.Pixiv.MaxXRestrict)
+	})
+
+	t.Run("BlockAIGeneratedWithAction", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "channels.json")
+		err := os.WriteFile(path, []byte(`{
+			"channels": {
+				"-1001234567890": { "filters": { "pixiv": { "block_ai_generated": true } }, "ai_generated": { "action": "none" } }
+			}
+		}`), 0644)
+		require.NoError(t, err)
+
+		channelsConfig, err := LoadChannelsConfig(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, AIGeneratedActionNone, channelsConfig.Channel(-1001234567890).AIGenerated.Action)
+	})
+
+	t.Run("OpenGraph", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "channels.json")
+		err := os.WriteFile(path, []byte(`{
+			"default": { "opengraph": { "allowed_domains": ["example.com"] } },
+			"channels": {
+				"-1001234567890": { "opengraph": { "enabled": true } }
+			}
+		}`), 0644)
+		require.NoError(t, err)
+
+		channelsConfig, err := LoadChannelsConfig(path)
+		require.NoError(t, err)
+
+		openGraph := channelsConfig.Channel(-1001234567890).OpenGraph
+		assert.True(t, openGraph.AllowsHost("example.com"))
+		assert.True(t, openGraph.AllowsHost("Blog.Example.com"))
+		assert.False(t, openGraph.AllowsHost("notexample.com"))
+		assert.False(t, openGraph.AllowsHost("example.org"))
+
+		openGraph = channelsConfig.Channel(-1009876543210).OpenGraph
+		assert.False(t, openGraph.AllowsHost("example.com"))
+	})
+
+	t.Run("InvalidIllustType", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "channels.json")
+		err := os.WriteFile(path, []byte(`{ "channels": { "-1001234567890": { "filters": { "pixiv": { "blocked_illust_types": ["novel"] } } } } }`), 0644)
+		require.NoError(t, err)
+
+		_, err = LoadChannelsConfig(path)
+		assert.Error(t, err)
+	})
+
+	t.Run("InvalidTimezone", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "channels.json")
+		err := os.WriteFile(path, []byte(`{ "default": { "queue": { "timezone": "Mars/Olympus" } } }`), 0644)
+		require.NoError(t, err)
+
+		_, err = LoadChannelsConfig(path)
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "channels.json")
+		err := os.WriteFile(path, []byte(`{`), 0644)
+		require.NoError(t, err)
+
+		_, err = LoadChannelsConfig(path)
+		assert.Error(t, err)
+	})
+}
+
+func TestSensitiveConfigWarningText(t *testing.T) {
+	warning := "⚠️ 敏感内容"
+
+	assert.Equal(t, warning, SensitiveConfig{Warning: &warning}.WarningText())
+	// 没有经过 withDefaults 的配置中 Warning 可能为 nil
+	assert.Empty(t, SensitiveConfig{}.WarningText())
+}
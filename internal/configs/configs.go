@@ -1,22 +1,94 @@
 package configs
 
-import "os"
+import (
+	"os"
+	"strconv"
+)
 
 const (
-	EnvTelegramBotToken = "TELEGRAM_BOT_TOKEN"
-	EnvPixivPHPSESSID   = "PIXIV_PHPSESSID"
+	EnvTelegramBotToken                  = "TELEGRAM_BOT_TOKEN"
+	EnvTelegramBotAPIEndpoint            = "TELEGRAM_BOT_API_ENDPOINT"
+	EnvTelegramBotAPILocalFilesDir       = "TELEGRAM_BOT_API_LOCAL_FILES_DIR"
+	EnvTelegramBotAPILocalFilesServerDir = "TELEGRAM_BOT_API_LOCAL_FILES_SERVER_DIR"
+	EnvPixivPHPSESSID                    = "PIXIV_PHPSESSID"
+	EnvDataDir                           = "DATA_DIR"
+	EnvChannelsConfig                    = "CHANNELS_CONFIG"
+	EnvSpoolDir                          = "SPOOL_DIR"
+	EnvDownloadConcurrency               = "DOWNLOAD_CONCURRENCY"
+	EnvDownloadBudgetMB                  = "DOWNLOAD_BUDGET_MB"
+	EnvGelbooruAPIKey                    = "GELBOORU_API_KEY"
+	EnvGelbooruUserID                    = "GELBOORU_USER_ID"
+	EnvFileCacheTTLDays                  = "FILE_CACHE_TTL_DAYS"
+	EnvPublishedTTLDays                  = "PUBLISHED_TTL_DAYS"
+)
+
+const (
+	DefaultDataDir = "data"
 )
 
 type Config struct {
 	TelegramBotToken string
-	PixivPHPSESSID   string
+	// TelegramBotAPIEndpoint 自建 Telegram Bot API 服务器的地址，例如 http://telegram-bot-api:8081，
+	// 为空时使用官方的 api.telegram.org
+	TelegramBotAPIEndpoint string
+	// TelegramBotAPILocalFilesDir 与自建 Telegram Bot API 服务器共享的目录，
+	// 较大的文件会被写入该目录后以 file:// 的形式上传
+	TelegramBotAPILocalFilesDir string
+	// TelegramBotAPILocalFilesServerDir 共享目录在 Telegram Bot API 服务器中的路径，
+	// 为空时与 TelegramBotAPILocalFilesDir 相同
+	TelegramBotAPILocalFilesServerDir string
+	PixivPHPSESSID                    string
+	// DataDir 持久化数据所在的目录
+	DataDir string
+	// ChannelsConfigPath 各个频道配置文件的路径，为空时使用 DataDir 下的 channels.json
+	ChannelsConfigPath string
+	// SpoolDir 暂存较大的下载内容所使用的临时目录，为空时使用系统的临时目录
+	SpoolDir string
+	// DownloadConcurrency 所有频道同时进行的下载数量，为 0 时使用默认值
+	DownloadConcurrency int
+	// DownloadBudgetMB 所有下载已写入、发送完毕之前尚未释放的数据上限，单位为 MiB，为 0 时使用默认值
+	DownloadBudgetMB int
+	// GelbooruAPIKey 和 GelbooruUserID 访问 Gelbooru API 所使用的凭据，为空时以匿名身份访问
+	GelbooruAPIKey string
+	GelbooruUserID string
+	// FileCacheTTLDays 已上传媒体的 file_id 保留的天数，为 0 时使用默认值
+	FileCacheTTLDays int
+	// PublishedTTLDays 已发布作品的记录保留的天数，超过后同一个作品可以不带 ! 再次发布，为 0 时使用默认值
+	PublishedTTLDays int
 }
 
 func NewConfig() func() *Config {
 	return func() *Config {
-		return &Config{
-			TelegramBotToken: os.Getenv(EnvTelegramBotToken),
-			PixivPHPSESSID:   os.Getenv(EnvPixivPHPSESSID),
+		config := &Config{
+			TelegramBotToken:                  os.Getenv(EnvTelegramBotToken),
+			TelegramBotAPIEndpoint:            os.Getenv(EnvTelegramBotAPIEndpoint),
+			TelegramBotAPILocalFilesDir:       os.Getenv(EnvTelegramBotAPILocalFilesDir),
+			TelegramBotAPILocalFilesServerDir: os.Getenv(EnvTelegramBotAPILocalFilesServerDir),
+			PixivPHPSESSID:                    os.Getenv(EnvPixivPHPSESSID),
+			DataDir:                           os.Getenv(EnvDataDir),
+			ChannelsConfigPath:                os.Getenv(EnvChannelsConfig),
+			SpoolDir:                          os.Getenv(EnvSpoolDir),
+			DownloadConcurrency:               envInt(EnvDownloadConcurrency),
+			DownloadBudgetMB:                  envInt(EnvDownloadBudgetMB),
+			GelbooruAPIKey:                    os.Getenv(EnvGelbooruAPIKey),
+			GelbooruUserID:                    os.Getenv(EnvGelbooruUserID),
+			FileCacheTTLDays:                  envInt(EnvFileCacheTTLDays),
+			PublishedTTLDays:                  envInt(EnvPublishedTTLDays),
+		}
+		if config.DataDir == "" {
+			config.DataDir = DefaultDataDir
 		}
+
+		return config
 	}
 }
+
+// envInt 读取整数类型的环境变量，未设置或无法解析时返回 0
+func envInt(key string) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return 0
+	}
+
+	return value
+}
@@ -0,0 +1,72 @@
+package configs
+
+import (
+	"fmt"
+
+	"github.com/samber/lo"
+)
+
+var (
+	pixivIllustTypeNames = []string{"illust", "manga", "ugoira"}
+)
+
+// PixivFiltersConfig Pixiv 作品的过滤规则，在下载图片之前根据作品信息判断是否允许发布
+type PixivFiltersConfig struct {
+	// BlockAIGenerated 已废弃，由 ai_generated.action 为 refuse 代替，读取配置时会转换为对应的设置
+	BlockAIGenerated *bool `json:"block_ai_generated"`
+	// BlockedTags 包含其中任意一个标签的作品会被拒绝，与标签或其英文翻译比较，不区分大小写
+	BlockedTags []string `json:"blocked_tags"`
+	// AllowedTags 不为空时，只允许包含其中至少一个标签的作品
+	AllowedTags []string `json:"allowed_tags"`
+	// MaxXRestrict 允许的最高年龄限制，0 为只允许全年龄，1 为允许 R-18，2 为允许 R-18G
+	MaxXRestrict *int `json:"max_x_restrict"`
+	// BlockedIllustTypes 拒绝的作品类型，可选 illust、manga 和 ugoira
+	BlockedIllustTypes []string `json:"blocked_illust_types"`
+	// BlockedAuthors 拒绝的作者 ID
+	BlockedAuthors []string `json:"blocked_authors"`
+	// AllowedAuthors 不为空时，只允许其中的作者的作品
+	AllowedAuthors []string `json:"allowed_authors"`
+}
+
+// FiltersConfig 发布前的过滤规则
+type FiltersConfig struct {
+	Pixiv PixivFiltersConfig `json:"pixiv"`
+}
+
+// withDefaults 使用 defaults 中的值填充未填写的字段
+func (c PixivFiltersConfig) withDefaults(defaults PixivFiltersConfig) PixivFiltersConfig {
+	if c.BlockedTags == nil {
+		c.BlockedTags = defaults.BlockedTags
+	}
+	if c.AllowedTags == nil {
+		c.AllowedTags = defaults.AllowedTags
+	}
+	if c.MaxXRestrict == nil {
+		c.MaxXRestrict = defaults.MaxXRestrict
+	}
+	if c.BlockedIllustTypes == nil {
+		c.BlockedIllustTypes = defaults.BlockedIllustTypes
+	}
+	if c.BlockedAuthors == nil {
+		c.BlockedAuthors = defaults.BlockedAuthors
+	}
+	if c.AllowedAuthors == nil {
+		c.AllowedAuthors = defaults.AllowedAuthors
+	}
+
+	return c
+}
+
+func (c PixivFiltersConfig) validate() error {
+	if c.MaxXRestrict != nil && (*c.MaxXRestrict < 0 || *c.MaxXRestrict > 2) {
+		return fmt.Errorf("invalid max_x_restrict %d, must be between 0 and 2", *c.MaxXRestrict)
+	}
+
+	for _, illustType := range c.BlockedIllustTypes {
+		if !lo.Contains(pixivIllustTypeNames, illustType) {
+			return fmt.Errorf("invalid illust type %s, must be one of %v", illustType, pixivIllustTypeNames)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package configs
+
+import (
+	"strings"
+)
+
+// OpenGraphConfig 不被任何来源支持的链接的兜底发布配置，读取网页的 og:image、og:title 等元数据发布其中的图片
+type OpenGraphConfig struct {
+	// Enabled 频道是否启用兜底发布，默认不启用
+	Enabled *bool `json:"enabled"`
+	// AllowedDomains 允许兜底发布的域名，同时匹配其子域名，为空时不允许任何链接
+	AllowedDomains []string `json:"allowed_domains"`
+}
+
+// withDefaults 使用 defaults 中的值填充未填写的字段
+func (c OpenGraphConfig) withDefaults(defaults OpenGraphConfig) OpenGraphConfig {
+	if c.Enabled == nil {
+		c.Enabled = defaults.Enabled
+	}
+	if c.AllowedDomains == nil {
+		c.AllowedDomains = defaults.AllowedDomains
+	}
+
+	return c
+}
+
+// AllowsHost 判断频道是否启用了兜底发布，并且 host 为允许的域名或其子域名
+func (c OpenGraphConfig) AllowsHost(host string) bool {
+	if c.Enabled == nil || !*c.Enabled {
+		return false
+	}
+
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, domain := range c.AllowedDomains {
+		domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), ".")
+		if domain == "" {
+			continue
+		}
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,139 @@
+package configs
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	// 容器镜像中可能没有时区数据
+	_ "time/tzdata"
+)
+
+const (
+	clockTimeLayout = "15:04"
+)
+
+// Duration 在 JSON 中以 time.ParseDuration 所支持的字符串表示的时长，例如 30m、1h30m
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var str string
+
+	err := json.Unmarshal(data, &str)
+	if err != nil {
+		return fmt.Errorf("duration must be a string like 30m: %w", err)
+	}
+
+	duration, err := time.ParseDuration(str)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(duration)
+	return nil
+}
+
+// QuietHours 每天不发布队列中作品的时段，以 15:04 的格式表示，结束时间早于开始时间时表示跨越午夜
+type QuietHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+func (q QuietHours) validate() error {
+	_, err := time.Parse(clockTimeLayout, q.Start)
+	if err != nil {
+		return fmt.Errorf("invalid quiet hours start %s: %w", q.Start, err)
+	}
+
+	_, err = time.Parse(clockTimeLayout, q.End)
+	if err != nil {
+		return fmt.Errorf("invalid quiet hours end %s: %w", q.End, err)
+	}
+
+	return nil
+}
+
+func minutesOfDay(clockTime string) int {
+	parsed, _ := time.Parse(clockTimeLayout, clockTime)
+	return parsed.Hour()*60 + parsed.Minute()
+}
+
+// QueueConfig 发布队列的配置
+type QueueConfig struct {
+	// StagingChatID 暂存会话的 ID，在该会话中发送的发布命令会加入频道的发布队列
+	StagingChatID int64 `json:"staging_chat_id"`
+	// Interval 队列中的作品两次发布之间的间隔
+	Interval Duration `json:"interval"`
+	// QuietHours 静默时段，为空时全天都可以发布
+	QuietHours *QuietHours `json:"quiet_hours"`
+	// Timezone 静默时段和 /schedule 命令所使用的时区，例如 Asia/Shanghai
+	Timezone string `json:"timezone"`
+}
+
+func (q QueueConfig) validate() error {
+	if q.QuietHours != nil {
+		err := q.QuietHours.validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %s: %w", q.Timezone, err)
+	}
+
+	return nil
+}
+
+// Location 返回 Timezone 所对应的时区
+func (q QueueConfig) Location() *time.Location {
+	location, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return location
+}
+
+// InQuietHours 判断 t 是否处于静默时段
+func (q QueueConfig) InQuietHours(t time.Time) bool {
+	if q.QuietHours == nil {
+		return false
+	}
+
+	t = t.In(q.Location())
+	minutes := t.Hour()*60 + t.Minute()
+	start := minutesOfDay(q.QuietHours.Start)
+	end := minutesOfDay(q.QuietHours.End)
+
+	switch {
+	case start < end:
+		return minutes >= start && minutes < end
+	case start > end:
+		return minutes >= start || minutes < end
+	default:
+		return false
+	}
+}
+
+// NextSlot 返回不早于 t 且不处于静默时段的最早时间
+func (q QueueConfig) NextSlot(t time.Time) time.Time {
+	if !q.InQuietHours(t) {
+		return t
+	}
+
+	local := t.In(q.Location())
+	end := minutesOfDay(q.QuietHours.End)
+
+	next := time.Date(local.Year(), local.Month(), local.Day(), end/60, end%60, 0, 0, local.Location())
+	if !next.After(t) {
+		next = next.AddDate(0, 0, 1)
+	}
+
+	return next
+}
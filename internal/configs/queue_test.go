@@ -0,0 +1,62 @@
+package configs
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuration(t *testing.T) {
+	var duration Duration
+
+	err := json.Unmarshal([]byte(`"1h30m"`), &duration)
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, time.Duration(duration))
+
+	err = json.Unmarshal([]byte(`90`), &duration)
+	assert.Error(t, err)
+}
+
+func TestQueueConfigQuietHours(t *testing.T) {
+	t.Run("SameDay", func(t *testing.T) {
+		queueConfig := QueueConfig{QuietHours: &QuietHours{Start: "12:00", End: "14:00"}, Timezone: "UTC"}
+
+		assert.False(t, queueConfig.InQuietHours(time.Date(2023, 1, 1, 11, 59, 0, 0, time.UTC)))
+		assert.True(t, queueConfig.InQuietHours(time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)))
+		assert.False(t, queueConfig.InQuietHours(time.Date(2023, 1, 1, 14, 0, 0, 0, time.UTC)))
+
+		assert.Equal(t,
+			time.Date(2023, 1, 1, 14, 0, 0, 0, time.UTC),
+			queueConfig.NextSlot(time.Date(2023, 1, 1, 13, 0, 0, 0, time.UTC)).UTC(),
+		)
+	})
+
+	t.Run("AcrossMidnight", func(t *testing.T) {
+		queueConfig := QueueConfig{QuietHours: &QuietHours{Start: "23:00", End: "08:00"}, Timezone: "Asia/Shanghai"}
+		location := queueConfig.Location()
+
+		assert.True(t, queueConfig.InQuietHours(time.Date(2023, 1, 1, 23, 30, 0, 0, location)))
+		assert.True(t, queueConfig.InQuietHours(time.Date(2023, 1, 2, 7, 59, 0, 0, location)))
+		assert.False(t, queueConfig.InQuietHours(time.Date(2023, 1, 2, 8, 0, 0, 0, location)))
+
+		assert.Equal(t,
+			time.Date(2023, 1, 2, 8, 0, 0, 0, location),
+			queueConfig.NextSlot(time.Date(2023, 1, 1, 23, 30, 0, 0, location)),
+		)
+		assert.Equal(t,
+			time.Date(2023, 1, 2, 8, 0, 0, 0, location),
+			queueConfig.NextSlot(time.Date(2023, 1, 2, 3, 0, 0, 0, location)),
+		)
+	})
+
+	t.Run("None", func(t *testing.T) {
+		queueConfig := QueueConfig{Timezone: "UTC"}
+		now := time.Date(2023, 1, 1, 3, 0, 0, 0, time.UTC)
+
+		assert.False(t, queueConfig.InQuietHours(now))
+		assert.Equal(t, now, queueConfig.NextSlot(now))
+	})
+}
@@ -0,0 +1,21 @@
+package lib
+
+import (
+	"github.com/nekomeowww/perobot/internal/configs"
+	"github.com/nekomeowww/perobot/pkg/downloads"
+	"github.com/nekomeowww/perobot/pkg/options"
+)
+
+func NewDownloads() func(config *configs.Config) *downloads.Manager {
+	return func(config *configs.Config) *downloads.Manager {
+		callOpts := make([]options.CallOptions[downloads.ManagerOptions], 0)
+		if config.DownloadConcurrency > 0 {
+			callOpts = append(callOpts, downloads.WithConcurrency(config.DownloadConcurrency))
+		}
+		if config.DownloadBudgetMB > 0 {
+			callOpts = append(callOpts, downloads.WithBudget(int64(config.DownloadBudgetMB)*1024*1024))
+		}
+
+		return downloads.New(callOpts...)
+	}
+}
@@ -5,5 +5,8 @@ import "go.uber.org/fx"
 func NewModules() fx.Option {
 	return fx.Options(
 		fx.Provide(NewLogger()),
+		fx.Provide(NewStore()),
+		fx.Provide(NewSpool()),
+		fx.Provide(NewDownloads()),
 	)
 }
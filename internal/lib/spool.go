@@ -0,0 +1,18 @@
+package lib
+
+import (
+	"github.com/nekomeowww/perobot/internal/configs"
+	"github.com/nekomeowww/perobot/pkg/options"
+	"github.com/nekomeowww/perobot/pkg/spool"
+)
+
+func NewSpool() func(config *configs.Config) (*spool.Store, error) {
+	return func(config *configs.Config) (*spool.Store, error) {
+		callOpts := make([]options.CallOptions[spool.StoreOptions], 0)
+		if config.SpoolDir != "" {
+			callOpts = append(callOpts, spool.WithDir(config.SpoolDir))
+		}
+
+		return spool.Open(callOpts...)
+	}
+}
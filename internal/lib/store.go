@@ -0,0 +1,43 @@
+package lib
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+
+	"github.com/nekomeowww/perobot/internal/configs"
+	"github.com/nekomeowww/perobot/pkg/logger"
+	"github.com/nekomeowww/perobot/pkg/store"
+)
+
+type NewStoreParam struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+
+	Config *configs.Config
+	Logger *logger.Logger
+}
+
+func NewStore() func(param NewStoreParam) (*store.Store, error) {
+	return func(param NewStoreParam) (*store.Store, error) {
+		s, err := store.Open(
+			filepath.Join(param.Config.DataDir, "perobot.json"),
+			store.WithLogger(logrus.NewEntry(param.Logger.Logger)),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		// 退出前写入尚未写入文件的修改
+		param.Lifecycle.Append(fx.Hook{
+			OnStop: func(ctx context.Context) error {
+				return s.Close()
+			},
+		})
+
+		return s, nil
+	}
+}
@@ -0,0 +1,143 @@
+package filecache
+
+import (
+	"fmt"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.uber.org/fx"
+
+	"github.com/nekomeowww/perobot/internal/configs"
+	"github.com/nekomeowww/perobot/pkg/bots/telegram"
+	"github.com/nekomeowww/perobot/pkg/logger"
+	"github.com/nekomeowww/perobot/pkg/store"
+)
+
+const (
+	bucket = "file_ids"
+
+	// DefaultTTL 未设置 FILE_CACHE_TTL_DAYS 时 file_id 保留的时间，过期后会重新下载和上传
+	DefaultTTL = 90 * 24 * time.Hour
+)
+
+// Variant 同一个来源媒体上传到 Telegram 的不同版本
+type Variant string
+
+const (
+	// VariantPreview 频道相册中的预览
+	VariantPreview Variant = "preview"
+	// VariantOriginal 讨论群组中以文件形式发送的原图、原视频
+	VariantOriginal Variant = "original"
+)
+
+// Entry 已上传到 Telegram 的文件
+type Entry struct {
+	// Type 文件上传时的媒体类型，同一个 file_id 只能以相同的媒体类型重新发送
+	Type         string    `json:"type"`
+	FileID       string    `json:"file_id"`
+	FileUniqueID string    `json:"file_unique_id"`
+	CachedAt     time.Time `json:"cached_at"`
+	// Hash 预览的感知哈希，只有 HasHash 为 true 时有效，使用缓存的 file_id 时不会重新下载，
+	// 需要与 file_id 一起记录才能在其他频道中查找重复图片
+	Hash    uint64 `json:"hash,omitempty"`
+	HasHash bool   `json:"has_hash,omitempty"`
+}
+
+type NewModelParam struct {
+	fx.In
+
+	Logger *logger.Logger
+	Config *configs.Config
+	Store  *store.Store
+}
+
+// Model 来源媒体 URL 与 Telegram file_id 的映射，用于避免重复下载和上传相同的媒体
+type Model struct {
+	Logger *logger.Logger
+	store  *store.Store
+	expiry *store.Expiry
+}
+
+func NewModel() func(param NewModelParam) *Model {
+	return func(param NewModelParam) *Model {
+		ttl := DefaultTTL
+		if param.Config.FileCacheTTLDays > 0 {
+			ttl = time.Duration(param.Config.FileCacheTTLDays) * 24 * time.Hour
+		}
+
+		return &Model{
+			Logger: param.Logger,
+			store:  param.Store,
+			expiry: store.NewExpiry(param.Store, bucket, ttl, func(entry Entry) time.Time { return entry.CachedAt }),
+		}
+	}
+}
+
+func key(sourceURL string, variant Variant) string {
+	return fmt.Sprintf("%s/%s", variant, sourceURL)
+}
+
+// Get 返回来源媒体 sourceURL 的 variant 版本所对应的文件，不存在时返回 nil
+func (m *Model) Get(sourceURL string, variant Variant) *Entry {
+	if sourceURL == "" {
+		return nil
+	}
+
+	var entry Entry
+
+	ok, err := m.store.Get(bucket, key(sourceURL, variant), &entry)
+	if err != nil {
+		m.Logger.WithField("source_url", sourceURL).Errorf("failed to get cached file id, err: %v", err)
+		return nil
+	}
+	if !ok || entry.FileID == "" {
+		return nil
+	}
+
+	return &entry
+}
+
+// SetFromMessage 从发送成功的消息中记录来源媒体 sourceURL 的 variant 版本所对应的 file_id
+func (m *Model) SetFromMessage(sourceURL string, variant Variant, message tgbotapi.Message) {
+	m.set(sourceURL, variant, message, 0, false)
+}
+
+// SetPreviewFromMessage 从发送成功的消息中记录来源媒体 sourceURL 的预览所对应的 file_id 和预览的感知哈希
+func (m *Model) SetPreviewFromMessage(sourceURL string, message tgbotapi.Message, hash uint64, hasHash bool) {
+	m.set(sourceURL, VariantPreview, message, hash, hasHash)
+}
+
+func (m *Model) set(sourceURL string, variant Variant, message tgbotapi.Message, hash uint64, hasHash bool) {
+	media, ok := telegram.MessageMediaOf(message)
+	if !ok || sourceURL == "" {
+		return
+	}
+
+	m.expiry.PruneIfDue()
+
+	err := m.store.Set(bucket, key(sourceURL, variant), Entry{
+		Type:         media.Type,
+		FileID:       media.FileID,
+		FileUniqueID: media.FileUniqueID,
+		CachedAt:     time.Now(),
+		Hash:         hash,
+		HasHash:      hasHash,
+	})
+	if err != nil {
+		m.Logger.WithField("source_url", sourceURL).Errorf("failed to cache file id, err: %v", err)
+	}
+}
+
+// Invalidate 删除来源媒体 sourceURL 的 variant 版本所对应的 file_id，
+// 用于 file_id 失效时重新下载和上传
+func (m *Model) Invalidate(sourceURL string, variant Variant) {
+	err := m.store.Delete(bucket, key(sourceURL, variant))
+	if err != nil {
+		m.Logger.WithField("source_url", sourceURL).Errorf("failed to invalidate cached file id, err: %v", err)
+	}
+}
+
+// Prune 删除 before 之前缓存的 file_id，返回删除的数量
+func (m *Model) Prune(before time.Time) int {
+	return m.expiry.Prune(before)
+}
@@ -0,0 +1,50 @@
+package filecache
+
+import (
+	"path/filepath"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nekomeowww/perobot/internal/configs"
+	"github.com/nekomeowww/perobot/internal/lib"
+	"github.com/nekomeowww/perobot/pkg/bots/telegram"
+	"github.com/nekomeowww/perobot/pkg/store"
+)
+
+func TestSetPreviewFromMessage(t *testing.T) {
+	s, err := store.Open(filepath.Join(t.TempDir(), "perobot.json"))
+	require.NoError(t, err)
+
+	defer s.Close()
+
+	model := NewModel()(NewModelParam{
+		Logger: lib.NewLogger()(),
+		Config: &configs.Config{},
+		Store:  s,
+	})
+
+	sourceURL := "https://example.com/a.png"
+	message := tgbotapi.Message{Photo: []tgbotapi.PhotoSize{{FileID: "small"}, {FileID: "large", FileUniqueID: "unique"}}}
+
+	// 使用缓存的 file_id 发送到其他频道时仍然需要预览的感知哈希
+	model.SetPreviewFromMessage(sourceURL, message, 0xdeadbeef, true)
+	model.SetFromMessage(sourceURL, VariantOriginal, tgbotapi.Message{Document: &tgbotapi.Document{FileID: "document"}})
+
+	preview := model.Get(sourceURL, VariantPreview)
+	require.NotNil(t, preview)
+	assert.Equal(t, telegram.MediaTypePhoto, preview.Type)
+	assert.Equal(t, "large", preview.FileID)
+	assert.Equal(t, uint64(0xdeadbeef), preview.Hash)
+	assert.True(t, preview.HasHash)
+
+	original := model.Get(sourceURL, VariantOriginal)
+	require.NotNil(t, original)
+	assert.Equal(t, "document", original.FileID)
+	assert.False(t, original.HasHash)
+
+	model.Invalidate(sourceURL, VariantPreview)
+	assert.Nil(t, model.Get(sourceURL, VariantPreview))
+}
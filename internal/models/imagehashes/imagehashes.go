@@ -0,0 +1,109 @@
+package imagehashes
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+
+	"github.com/nekomeowww/perobot/internal/models/published"
+	"github.com/nekomeowww/perobot/pkg/imagehash"
+	"github.com/nekomeowww/perobot/pkg/logger"
+	"github.com/nekomeowww/perobot/pkg/store"
+)
+
+const (
+	bucket = "image_hashes"
+)
+
+// Record 频道中已发布图片的感知哈希
+type Record struct {
+	Hash     uint64           `json:"hash"`
+	Source   published.Source `json:"source"`
+	SourceID string           `json:"source_id"`
+	// MessageID 频道中相册第一条消息的 ID
+	MessageID   int       `json:"message_id"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+type NewModelParam struct {
+	fx.In
+
+	Logger *logger.Logger
+	Store  *store.Store
+}
+
+// Model 每个频道最近发布的图片的感知哈希，用于发现跨来源视觉上相同的作品
+type Model struct {
+	Logger *logger.Logger
+	store  *store.Store
+
+	mutex sync.Mutex
+}
+
+func NewModel() func(param NewModelParam) *Model {
+	return func(param NewModelParam) *Model {
+		return &Model{
+			Logger: param.Logger,
+			store:  param.Store,
+		}
+	}
+}
+
+func (m *Model) records(chatID int64) []Record {
+	records := make([]Record, 0)
+
+	_, err := m.store.Get(bucket, strconv.FormatInt(chatID, 10), &records)
+	if err != nil {
+		m.Logger.WithField("chat_id", chatID).Errorf("failed to get image hashes, err: %v", err)
+		return make([]Record, 0)
+	}
+
+	return records
+}
+
+// FindSimilar 在频道 chatID 最近发布的 window 张图片中，查找与 hashes 中任意一张的汉明距离
+// 不超过 threshold 的图片，返回距离最小的记录，不存在时返回 nil
+func (m *Model) FindSimilar(chatID int64, hashes []uint64, threshold int, window int) *Record {
+	records := m.records(chatID)
+	if len(records) > window {
+		records = records[len(records)-window:]
+	}
+
+	var similar *Record
+	minDistance := imagehash.MaxDistance + 1
+
+	for i := range records {
+		for _, hash := range hashes {
+			distance := imagehash.Distance(records[i].Hash, hash)
+			if distance <= threshold && distance < minDistance {
+				similar = &records[i]
+				minDistance = distance
+			}
+		}
+	}
+
+	return similar
+}
+
+// Add 记录频道 chatID 中新发布的图片，只保留最近的 window 张
+func (m *Model) Add(chatID int64, newRecords []Record, window int) {
+	if len(newRecords) == 0 {
+		return
+	}
+
+	// 同时发布的作品读取、追加和写回同一个频道的记录时不能互相覆盖
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	records := append(m.records(chatID), newRecords...)
+	if len(records) > window {
+		records = records[len(records)-window:]
+	}
+
+	err := m.store.Set(bucket, strconv.FormatInt(chatID, 10), records)
+	if err != nil {
+		m.Logger.WithField("chat_id", chatID).Errorf("failed to set image hashes, err: %v", err)
+	}
+}
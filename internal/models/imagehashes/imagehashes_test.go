@@ -0,0 +1,39 @@
+package imagehashes
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nekomeowww/perobot/internal/lib"
+	"github.com/nekomeowww/perobot/pkg/store"
+)
+
+func TestAddConcurrently(t *testing.T) {
+	s, err := store.Open(filepath.Join(t.TempDir(), "perobot.json"))
+	require.NoError(t, err)
+
+	defer s.Close()
+
+	model := NewModel()(NewModelParam{
+		Logger: lib.NewLogger()(),
+		Store:  s,
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			model.Add(-1001234567890, []Record{{Hash: uint64(i), SourceID: "post"}}, 500)
+		}(i)
+	}
+
+	wg.Wait()
+
+	assert.Len(t, model.records(-1001234567890), 20)
+}
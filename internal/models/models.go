@@ -1,6 +1,10 @@
 package models
 
 import (
+	"github.com/nekomeowww/perobot/internal/models/filecache"
+	"github.com/nekomeowww/perobot/internal/models/imagehashes"
+	"github.com/nekomeowww/perobot/internal/models/postqueue"
+	"github.com/nekomeowww/perobot/internal/models/published"
 	"github.com/nekomeowww/perobot/internal/models/twitter"
 	"go.uber.org/fx"
 )
@@ -8,5 +12,9 @@ import (
 func NewModules() fx.Option {
 	return fx.Options(
 		fx.Provide(twitter.NewModel()),
+		fx.Provide(filecache.NewModel()),
+		fx.Provide(published.NewModel()),
+		fx.Provide(imagehashes.NewModel()),
+		fx.Provide(postqueue.NewModel()),
 	)
 }
@@ -0,0 +1,222 @@
+package postqueue
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+	"go.uber.org/fx"
+
+	"github.com/nekomeowww/perobot/internal/configs"
+	"github.com/nekomeowww/perobot/pkg/logger"
+	"github.com/nekomeowww/perobot/pkg/store"
+)
+
+const (
+	bucket      = "post_queue"
+	stateBucket = "post_queue_state"
+
+	nextIDKey = "next_id"
+)
+
+// Item 发布队列中等待发布的作品
+type Item struct {
+	ID    int64  `json:"id"`
+	URL   string `json:"url"`
+	Force bool   `json:"force"`
+	// ScheduledAt 指定的发布时间，为 nil 时按照队列的间隔依次发布
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+	// StagingChatID 加入队列时所在的暂存会话，发布时的提示消息会发送到这里
+	StagingChatID int64 `json:"staging_chat_id"`
+	// StagingMessageID 暂存会话中加入队列的命令消息
+	StagingMessageID int       `json:"staging_message_id"`
+	EnqueuedAt       time.Time `json:"enqueued_at"`
+}
+
+type NewModelParam struct {
+	fx.In
+
+	Logger *logger.Logger
+	Store  *store.Store
+}
+
+// Model 每个频道的发布队列
+type Model struct {
+	Logger *logger.Logger
+	store  *store.Store
+
+	mutex sync.Mutex
+}
+
+func NewModel() func(param NewModelParam) *Model {
+	return func(param NewModelParam) *Model {
+		return &Model{
+			Logger: param.Logger,
+			store:  param.Store,
+		}
+	}
+}
+
+func channelKey(channelID int64) string {
+	return strconv.FormatInt(channelID, 10)
+}
+
+func lastPublishedAtKey(channelID int64) string {
+	return fmt.Sprintf("last_published_at/%d", channelID)
+}
+
+func (m *Model) items(channelID int64) ([]Item, error) {
+	items := make([]Item, 0)
+
+	_, err := m.store.Get(bucket, channelKey(channelID), &items)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Enqueue 将作品加入频道 channelID 的发布队列，返回分配了 ID 的作品
+func (m *Model) Enqueue(channelID int64, item Item) (Item, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	items, err := m.items(channelID)
+	if err != nil {
+		return Item{}, err
+	}
+
+	var nextID int64
+
+	_, err = m.store.Get(stateBucket, nextIDKey, &nextID)
+	if err != nil {
+		return Item{}, err
+	}
+
+	item.ID = nextID + 1
+	item.EnqueuedAt = time.Now()
+
+	err = m.store.Set(stateBucket, nextIDKey, item.ID)
+	if err != nil {
+		return Item{}, err
+	}
+
+	err = m.store.Set(bucket, channelKey(channelID), append(items, item))
+	if err != nil {
+		return Item{}, err
+	}
+
+	return item, nil
+}
+
+// List 返回频道 channelID 发布队列中的所有作品，按照加入队列的顺序排列
+func (m *Model) List(channelID int64) ([]Item, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.items(channelID)
+}
+
+// Remove 从频道 channelID 的发布队列中移除作品 id，返回作品是否存在
+func (m *Model) Remove(channelID int64, id int64) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	items, err := m.items(channelID)
+	if err != nil {
+		return false, err
+	}
+
+	remaining := lo.Reject(items, func(item Item, _ int) bool { return item.ID == id })
+	if len(remaining) == len(items) {
+		return false, nil
+	}
+
+	return true, m.store.Set(bucket, channelKey(channelID), remaining)
+}
+
+// Channels 返回所有存在发布队列的频道
+func (m *Model) Channels() []int64 {
+	channelIDs := make([]int64, 0)
+	for _, key := range m.store.Keys(bucket) {
+		channelID, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		channelIDs = append(channelIDs, channelID)
+	}
+
+	return channelIDs
+}
+
+// LastPublishedAt 返回频道 channelID 最近一次发布队列中作品的时间
+func (m *Model) LastPublishedAt(channelID int64) time.Time {
+	var lastPublishedAt time.Time
+
+	_, err := m.store.Get(stateBucket, lastPublishedAtKey(channelID), &lastPublishedAt)
+	if err != nil {
+		m.Logger.WithField("channel_id", channelID).Errorf("failed to get last published time, err: %v", err)
+	}
+
+	return lastPublishedAt
+}
+
+// SetLastPublishedAt 记录频道 channelID 最近一次发布队列中作品的时间
+func (m *Model) SetLastPublishedAt(channelID int64, lastPublishedAt time.Time) {
+	err := m.store.Set(stateBucket, lastPublishedAtKey(channelID), lastPublishedAt)
+	if err != nil {
+		m.Logger.WithField("channel_id", channelID).Errorf("failed to set last published time, err: %v", err)
+	}
+}
+
+// Due 返回 now 时应当发布的作品，不存在时返回 nil
+//
+// 1. 已经到达指定发布时间的作品优先发布，不受间隔和静默时段的限制；
+// 2. 距离上一次发布已经超过间隔且不处于静默时段时，发布最早加入队列且没有指定发布时间的作品。
+func Due(items []Item, now time.Time, lastPublishedAt time.Time, queueConfig configs.QueueConfig) *Item {
+	scheduledItems := lo.Filter(items, func(item Item, _ int) bool {
+		return item.ScheduledAt != nil && !item.ScheduledAt.After(now)
+	})
+	if len(scheduledItems) > 0 {
+		sort.SliceStable(scheduledItems, func(i, j int) bool { return scheduledItems[i].ScheduledAt.Before(*scheduledItems[j].ScheduledAt) })
+		return &scheduledItems[0]
+	}
+
+	if queueConfig.InQuietHours(now) || now.Before(lastPublishedAt.Add(time.Duration(queueConfig.Interval))) {
+		return nil
+	}
+
+	item, ok := lo.Find(items, func(item Item) bool { return item.ScheduledAt == nil })
+	if !ok {
+		return nil
+	}
+
+	return &item
+}
+
+// Plan 估算队列中每个作品的发布时间
+func Plan(items []Item, now time.Time, lastPublishedAt time.Time, queueConfig configs.QueueConfig) []time.Time {
+	interval := time.Duration(queueConfig.Interval)
+
+	slot := lastPublishedAt.Add(interval)
+	if slot.Before(now) {
+		slot = now
+	}
+
+	planned := make([]time.Time, len(items))
+	for i, item := range items {
+		if item.ScheduledAt != nil {
+			planned[i] = *item.ScheduledAt
+			continue
+		}
+
+		planned[i] = queueConfig.NextSlot(slot)
+		slot = planned[i].Add(interval)
+	}
+
+	return planned
+}
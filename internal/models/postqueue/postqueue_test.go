@@ -0,0 +1,74 @@
+package postqueue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nekomeowww/perobot/internal/configs"
+)
+
+func TestDue(t *testing.T) {
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	queueConfig := configs.QueueConfig{Interval: configs.Duration(30 * time.Minute), Timezone: "UTC"}
+	items := []Item{
+		{ID: 1, URL: "https://twitter.com/a/status/1"},
+		{ID: 2, URL: "https://twitter.com/a/status/2", ScheduledAt: &future},
+		{ID: 3, URL: "https://twitter.com/a/status/3", ScheduledAt: &past},
+	}
+
+	t.Run("Scheduled", func(t *testing.T) {
+		item := Due(items, now, now, queueConfig)
+		require.NotNil(t, item)
+		assert.Equal(t, int64(3), item.ID)
+	})
+
+	t.Run("Interval", func(t *testing.T) {
+		assert.Nil(t, Due(items[:2], now, now.Add(-10*time.Minute), queueConfig))
+
+		item := Due(items[:2], now, now.Add(-30*time.Minute), queueConfig)
+		require.NotNil(t, item)
+		assert.Equal(t, int64(1), item.ID)
+	})
+
+	t.Run("QuietHours", func(t *testing.T) {
+		quietQueueConfig := queueConfig
+		quietQueueConfig.QuietHours = &configs.QuietHours{Start: "11:00", End: "13:00"}
+
+		assert.Nil(t, Due(items[:2], now, time.Time{}, quietQueueConfig))
+
+		// 指定了发布时间的作品不受静默时段的限制
+		item := Due(items, now, time.Time{}, quietQueueConfig)
+		require.NotNil(t, item)
+		assert.Equal(t, int64(3), item.ID)
+	})
+}
+
+func TestPlan(t *testing.T) {
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	scheduledAt := now.Add(5 * time.Hour)
+
+	queueConfig := configs.QueueConfig{
+		Interval:   configs.Duration(30 * time.Minute),
+		QuietHours: &configs.QuietHours{Start: "12:45", End: "14:00"},
+		Timezone:   "UTC",
+	}
+	items := []Item{
+		{ID: 1},
+		{ID: 2, ScheduledAt: &scheduledAt},
+		{ID: 3},
+		{ID: 4},
+	}
+
+	planned := Plan(items, now, now.Add(-10*time.Minute), queueConfig)
+	require.Len(t, planned, 4)
+	assert.Equal(t, now.Add(20*time.Minute), planned[0])
+	assert.Equal(t, scheduledAt, planned[1])
+	assert.Equal(t, time.Date(2023, 1, 1, 14, 0, 0, 0, time.UTC), planned[2])
+	assert.Equal(t, time.Date(2023, 1, 1, 14, 30, 0, 0, time.UTC), planned[3])
+}
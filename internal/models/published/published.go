@@ -0,0 +1,119 @@
+package published
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/fx"
+
+	"github.com/nekomeowww/perobot/internal/configs"
+	"github.com/nekomeowww/perobot/pkg/logger"
+	"github.com/nekomeowww/perobot/pkg/store"
+)
+
+const (
+	bucket = "published"
+
+	// DefaultTTL 未设置 PUBLISHED_TTL_DAYS 时发布记录保留的时间
+	DefaultTTL = 365 * 24 * time.Hour
+)
+
+// Source 作品的来源
+type Source string
+
+const (
+	SourceTwitter Source = "twitter"
+	SourcePixiv   Source = "pixiv"
+	SourceBluesky Source = "bluesky"
+	// SourceFediverse Mastodon、Misskey 等任意实例上的作品，ID 中包含实例的域名
+	SourceFediverse Source = "fediverse"
+	// SourceBooru Danbooru、Gelbooru、Moebooru 等图站上的作品，ID 中包含站点的域名
+	SourceBooru Source = "booru"
+	// SourceWeibo 微博，ID 为微博的数字 ID
+	SourceWeibo Source = "weibo"
+	// SourceBilibili B 站动态，ID 为动态的 ID
+	SourceBilibili Source = "bilibili"
+	// SourceArtStation ArtStation 作品，ID 为作品链接中的 hash
+	SourceArtStation Source = "artstation"
+	// SourceDeviantArt DeviantArt 作品，ID 为作品链接末尾的数字 ID
+	SourceDeviantArt Source = "deviantart"
+	// SourceReddit Reddit 帖子，ID 为帖子的 base36 ID
+	SourceReddit Source = "reddit"
+	// SourceOpenGraph 没有专门支持的网页，通过 OpenGraph 元数据发布，ID 为去掉锚点的网页链接
+	SourceOpenGraph Source = "opengraph"
+)
+
+// Entry 已经发布到频道的作品
+type Entry struct {
+	// MessageID 频道中相册第一条消息的 ID
+	MessageID   int       `json:"message_id"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+type NewModelParam struct {
+	fx.In
+
+	Logger *logger.Logger
+	Config *configs.Config
+	Store  *store.Store
+}
+
+// Model 每个频道已发布作品的索引，用于避免重复发布相同的作品
+type Model struct {
+	Logger *logger.Logger
+	store  *store.Store
+	expiry *store.Expiry
+}
+
+func NewModel() func(param NewModelParam) *Model {
+	return func(param NewModelParam) *Model {
+		ttl := DefaultTTL
+		if param.Config.PublishedTTLDays > 0 {
+			ttl = time.Duration(param.Config.PublishedTTLDays) * 24 * time.Hour
+		}
+
+		return &Model{
+			Logger: param.Logger,
+			store:  param.Store,
+			expiry: store.NewExpiry(param.Store, bucket, ttl, func(entry Entry) time.Time { return entry.PublishedAt }),
+		}
+	}
+}
+
+func key(chatID int64, source Source, sourceID string) string {
+	return fmt.Sprintf("%d/%s/%s", chatID, source, sourceID)
+}
+
+// Get 返回频道 chatID 中来自 source 的作品 sourceID 的发布记录，未发布过时返回 nil
+func (m *Model) Get(chatID int64, source Source, sourceID string) *Entry {
+	var entry Entry
+
+	ok, err := m.store.Get(bucket, key(chatID, source, sourceID), &entry)
+	if err != nil {
+		m.Logger.WithField("source_id", sourceID).Errorf("failed to get published entry, err: %v", err)
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+
+	return &entry
+}
+
+// Set 记录频道 chatID 中来自 source 的作品 sourceID 已发布为消息 messageID
+func (m *Model) Set(chatID int64, source Source, sourceID string, messageID int) {
+	m.expiry.PruneIfDue()
+
+	err := m.store.Set(bucket, key(chatID, source, sourceID), Entry{
+		MessageID:   messageID,
+		PublishedAt: time.Now(),
+	})
+	if err != nil {
+		m.Logger.WithField("source_id", sourceID).Errorf("failed to set published entry, err: %v", err)
+	}
+}
+
+// Prune 删除 before 之前发布的作品的记录，返回删除的数量
+func (m *Model) Prune(before time.Time) int {
+	return m.expiry.Prune(before)
+}
@@ -0,0 +1,43 @@
+package published
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nekomeowww/perobot/internal/configs"
+	"github.com/nekomeowww/perobot/internal/lib"
+	"github.com/nekomeowww/perobot/pkg/store"
+)
+
+func TestPrune(t *testing.T) {
+	s, err := store.Open(filepath.Join(t.TempDir(), "perobot.json"))
+	require.NoError(t, err)
+
+	defer s.Close()
+
+	model := NewModel()(NewModelParam{
+		Logger: lib.NewLogger()(),
+		Config: &configs.Config{PublishedTTLDays: 30},
+		Store:  s,
+	})
+	assert.Equal(t, 30*24*time.Hour, model.expiry.TTL())
+
+	err = s.Set(bucket, key(-1001234567890, SourcePixiv, "1"), Entry{MessageID: 1, PublishedAt: time.Now().Add(-31 * 24 * time.Hour)})
+	require.NoError(t, err)
+	err = s.Set(bucket, key(-1001234567890, SourcePixiv, "2"), "invalid")
+	require.NoError(t, err)
+
+	// 第一次写入时清理过期和无法解析的记录
+	model.Set(-1001234567890, SourcePixiv, "3", 3)
+
+	assert.Nil(t, model.Get(-1001234567890, SourcePixiv, "1"))
+	assert.Nil(t, model.Get(-1001234567890, SourcePixiv, "2"))
+	require.NotNil(t, model.Get(-1001234567890, SourcePixiv, "3"))
+	assert.Equal(t, 3, model.Get(-1001234567890, SourcePixiv, "3").MessageID)
+
+	assert.Equal(t, 1, model.Prune(time.Now().Add(time.Minute)))
+}
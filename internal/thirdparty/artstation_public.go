@@ -0,0 +1,33 @@
+package thirdparty
+
+import (
+	artstation_public "github.com/nekomeowww/perobot/pkg/artstation/public"
+	"github.com/nekomeowww/perobot/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+type NewArtStationPublicParam struct {
+	fx.In
+
+	Logger *logger.Logger
+}
+
+type ArtStationPublic struct {
+	*artstation_public.Client
+}
+
+func NewArtStationPublic() func(param NewArtStationPublicParam) (*ArtStationPublic, error) {
+	return func(param NewArtStationPublicParam) (*ArtStationPublic, error) {
+		client, err := artstation_public.NewClient(
+			artstation_public.WithLogger(logrus.NewEntry(param.Logger.Logger)),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ArtStationPublic{
+			Client: client,
+		}, nil
+	}
+}
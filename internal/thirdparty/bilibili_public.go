@@ -0,0 +1,33 @@
+package thirdparty
+
+import (
+	bilibili_public "github.com/nekomeowww/perobot/pkg/bilibili/public"
+	"github.com/nekomeowww/perobot/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+type NewBilibiliPublicParam struct {
+	fx.In
+
+	Logger *logger.Logger
+}
+
+type BilibiliPublic struct {
+	*bilibili_public.Client
+}
+
+func NewBilibiliPublic() func(param NewBilibiliPublicParam) (*BilibiliPublic, error) {
+	return func(param NewBilibiliPublicParam) (*BilibiliPublic, error) {
+		client, err := bilibili_public.NewClient(
+			bilibili_public.WithLogger(logrus.NewEntry(param.Logger.Logger)),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return &BilibiliPublic{
+			Client: client,
+		}, nil
+	}
+}
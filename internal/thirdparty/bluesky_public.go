@@ -0,0 +1,33 @@
+package thirdparty
+
+import (
+	bluesky_public "github.com/nekomeowww/perobot/pkg/bluesky/public"
+	"github.com/nekomeowww/perobot/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+type NewBlueskyPublicParam struct {
+	fx.In
+
+	Logger *logger.Logger
+}
+
+type BlueskyPublic struct {
+	*bluesky_public.Client
+}
+
+func NewBlueskyPublic() func(param NewBlueskyPublicParam) (*BlueskyPublic, error) {
+	return func(param NewBlueskyPublicParam) (*BlueskyPublic, error) {
+		client, err := bluesky_public.NewClient(
+			bluesky_public.WithLogger(logrus.NewEntry(param.Logger.Logger)),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return &BlueskyPublic{
+			Client: client,
+		}, nil
+	}
+}
@@ -0,0 +1,36 @@
+package thirdparty
+
+import (
+	"github.com/nekomeowww/perobot/internal/configs"
+	"github.com/nekomeowww/perobot/pkg/booru"
+	"github.com/nekomeowww/perobot/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+type NewBooruParam struct {
+	fx.In
+
+	Logger *logger.Logger
+	Config *configs.Config
+}
+
+type Booru struct {
+	*booru.Client
+}
+
+func NewBooru() func(param NewBooruParam) (*Booru, error) {
+	return func(param NewBooruParam) (*Booru, error) {
+		client, err := booru.NewClient(
+			booru.WithLogger(logrus.NewEntry(param.Logger.Logger)),
+			booru.WithGelbooruCredential(param.Config.GelbooruAPIKey, param.Config.GelbooruUserID),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Booru{
+			Client: client,
+		}, nil
+	}
+}
@@ -0,0 +1,33 @@
+package thirdparty
+
+import (
+	deviantart_public "github.com/nekomeowww/perobot/pkg/deviantart/public"
+	"github.com/nekomeowww/perobot/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+type NewDeviantArtPublicParam struct {
+	fx.In
+
+	Logger *logger.Logger
+}
+
+type DeviantArtPublic struct {
+	*deviantart_public.Client
+}
+
+func NewDeviantArtPublic() func(param NewDeviantArtPublicParam) (*DeviantArtPublic, error) {
+	return func(param NewDeviantArtPublicParam) (*DeviantArtPublic, error) {
+		client, err := deviantart_public.NewClient(
+			deviantart_public.WithLogger(logrus.NewEntry(param.Logger.Logger)),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return &DeviantArtPublic{
+			Client: client,
+		}, nil
+	}
+}
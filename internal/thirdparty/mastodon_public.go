@@ -0,0 +1,33 @@
+package thirdparty
+
+import (
+	"github.com/nekomeowww/perobot/pkg/logger"
+	mastodon_public "github.com/nekomeowww/perobot/pkg/mastodon/public"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+type NewMastodonPublicParam struct {
+	fx.In
+
+	Logger *logger.Logger
+}
+
+type MastodonPublic struct {
+	*mastodon_public.Client
+}
+
+func NewMastodonPublic() func(param NewMastodonPublicParam) (*MastodonPublic, error) {
+	return func(param NewMastodonPublicParam) (*MastodonPublic, error) {
+		client, err := mastodon_public.NewClient(
+			mastodon_public.WithLogger(logrus.NewEntry(param.Logger.Logger)),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return &MastodonPublic{
+			Client: client,
+		}, nil
+	}
+}
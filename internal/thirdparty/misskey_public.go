@@ -0,0 +1,33 @@
+package thirdparty
+
+import (
+	"github.com/nekomeowww/perobot/pkg/logger"
+	misskey_public "github.com/nekomeowww/perobot/pkg/misskey/public"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+type NewMisskeyPublicParam struct {
+	fx.In
+
+	Logger *logger.Logger
+}
+
+type MisskeyPublic struct {
+	*misskey_public.Client
+}
+
+func NewMisskeyPublic() func(param NewMisskeyPublicParam) (*MisskeyPublic, error) {
+	return func(param NewMisskeyPublicParam) (*MisskeyPublic, error) {
+		client, err := misskey_public.NewClient(
+			misskey_public.WithLogger(logrus.NewEntry(param.Logger.Logger)),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return &MisskeyPublic{
+			Client: client,
+		}, nil
+	}
+}
@@ -0,0 +1,33 @@
+package thirdparty
+
+import (
+	"github.com/nekomeowww/perobot/pkg/logger"
+	opengraph_public "github.com/nekomeowww/perobot/pkg/opengraph/public"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+type NewOpenGraphPublicParam struct {
+	fx.In
+
+	Logger *logger.Logger
+}
+
+type OpenGraphPublic struct {
+	*opengraph_public.Client
+}
+
+func NewOpenGraphPublic() func(param NewOpenGraphPublicParam) (*OpenGraphPublic, error) {
+	return func(param NewOpenGraphPublicParam) (*OpenGraphPublic, error) {
+		client, err := opengraph_public.NewClient(
+			opengraph_public.WithLogger(logrus.NewEntry(param.Logger.Logger)),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return &OpenGraphPublic{
+			Client: client,
+		}, nil
+	}
+}
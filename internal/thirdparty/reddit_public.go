@@ -0,0 +1,33 @@
+package thirdparty
+
+import (
+	"github.com/nekomeowww/perobot/pkg/logger"
+	reddit_public "github.com/nekomeowww/perobot/pkg/reddit/public"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+type NewRedditPublicParam struct {
+	fx.In
+
+	Logger *logger.Logger
+}
+
+type RedditPublic struct {
+	*reddit_public.Client
+}
+
+func NewRedditPublic() func(param NewRedditPublicParam) (*RedditPublic, error) {
+	return func(param NewRedditPublicParam) (*RedditPublic, error) {
+		client, err := reddit_public.NewClient(
+			reddit_public.WithLogger(logrus.NewEntry(param.Logger.Logger)),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return &RedditPublic{
+			Client: client,
+		}, nil
+	}
+}
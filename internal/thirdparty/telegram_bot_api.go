@@ -0,0 +1,50 @@
+package thirdparty
+
+import (
+	"os"
+
+	"go.uber.org/fx"
+
+	"github.com/nekomeowww/perobot/internal/configs"
+	"github.com/nekomeowww/perobot/pkg/bots/telegram"
+	"github.com/nekomeowww/perobot/pkg/logger"
+	"github.com/nekomeowww/perobot/pkg/options"
+)
+
+type NewTelegramFileUploaderParam struct {
+	fx.In
+
+	Logger *logger.Logger
+	Config *configs.Config
+}
+
+type TelegramFileUploader struct {
+	*telegram.FileUploader
+}
+
+func NewTelegramFileUploader() func(param NewTelegramFileUploaderParam) (*TelegramFileUploader, error) {
+	return func(param NewTelegramFileUploaderParam) (*TelegramFileUploader, error) {
+		callOpts := make([]options.CallOptions[telegram.FileUploaderOptions], 0)
+		if param.Config.TelegramBotAPIEndpoint != "" {
+			callOpts = append(callOpts, telegram.WithSizePolicy(telegram.LocalBotAPISizePolicy))
+		}
+		if param.Config.TelegramBotAPILocalFilesDir != "" {
+			err := os.MkdirAll(param.Config.TelegramBotAPILocalFilesDir, 0755)
+			if err != nil {
+				return nil, err
+			}
+
+			callOpts = append(callOpts, telegram.WithLocalFiles(
+				param.Config.TelegramBotAPILocalFilesDir,
+				param.Config.TelegramBotAPILocalFilesServerDir,
+			))
+		}
+
+		uploader := telegram.NewFileUploader(callOpts...)
+		param.Logger.Infof("telegram upload size limit: %d MB", uploader.SizePolicy().MaxUploadSize/telegram.MB)
+
+		return &TelegramFileUploader{
+			FileUploader: uploader,
+		}, nil
+	}
+}
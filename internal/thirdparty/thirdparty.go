@@ -6,5 +6,16 @@ func NewModules() fx.Option {
 	return fx.Options(
 		fx.Provide(NewTwitterPublic()),
 		fx.Provide(NewPixivPublic()),
+		fx.Provide(NewBlueskyPublic()),
+		fx.Provide(NewMastodonPublic()),
+		fx.Provide(NewMisskeyPublic()),
+		fx.Provide(NewBooru()),
+		fx.Provide(NewWeiboPublic()),
+		fx.Provide(NewBilibiliPublic()),
+		fx.Provide(NewArtStationPublic()),
+		fx.Provide(NewDeviantArtPublic()),
+		fx.Provide(NewRedditPublic()),
+		fx.Provide(NewOpenGraphPublic()),
+		fx.Provide(NewTelegramFileUploader()),
 	)
 }
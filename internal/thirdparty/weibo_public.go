@@ -0,0 +1,33 @@
+package thirdparty
+
+import (
+	"github.com/nekomeowww/perobot/pkg/logger"
+	weibo_public "github.com/nekomeowww/perobot/pkg/weibo/public"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+type NewWeiboPublicParam struct {
+	fx.In
+
+	Logger *logger.Logger
+}
+
+type WeiboPublic struct {
+	*weibo_public.Client
+}
+
+func NewWeiboPublic() func(param NewWeiboPublicParam) (*WeiboPublic, error) {
+	return func(param NewWeiboPublicParam) (*WeiboPublic, error) {
+		client, err := weibo_public.NewClient(
+			weibo_public.WithLogger(logrus.NewEntry(param.Logger.Logger)),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return &WeiboPublic{
+			Client: client,
+		}, nil
+	}
+}
@@ -0,0 +1,102 @@
+package artstation_public
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/imroc/req/v3"
+	"github.com/sirupsen/logrus"
+
+	artstation_public_types "github.com/nekomeowww/perobot/pkg/artstation/public/types"
+	"github.com/nekomeowww/perobot/pkg/options"
+)
+
+const (
+	// DefaultBaseURL ArtStation 的网站地址
+	DefaultBaseURL = "https://www.artstation.com"
+)
+
+type ClientOptions struct {
+	Logger  *logrus.Entry
+	BaseURL string
+}
+
+func WithLogger(logger *logrus.Entry) options.CallOptions[ClientOptions] {
+	return options.NewCallOptions(func(o *ClientOptions) {
+		o.Logger = logger
+	})
+}
+
+// WithBaseURL 设定 ArtStation 的网站地址
+func WithBaseURL(baseURL string) options.CallOptions[ClientOptions] {
+	return options.NewCallOptions(func(o *ClientOptions) {
+		o.BaseURL = baseURL
+	})
+}
+
+// Client 访问 ArtStation 网页所使用的公开 JSON 接口
+type Client struct {
+	reqClient *req.Client
+	logger    *logrus.Entry
+}
+
+func NewClient(callOpts ...options.CallOptions[ClientOptions]) (*Client, error) {
+	opts := options.ApplyCallOptions(callOpts, ClientOptions{
+		Logger:  logrus.NewEntry(logrus.New()),
+		BaseURL: DefaultBaseURL,
+	})
+
+	c := req.
+		C().
+		SetBaseURL(strings.TrimSuffix(opts.BaseURL, "/")).
+		SetCommonHeader("Accept", "application/json").
+		SetUserAgent("Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/109.0.0.0 Safari/537.36 Edg/109.0.1518.52")
+
+	client := &Client{
+		reqClient: c,
+		logger:    opts.Logger,
+	}
+
+	return client, nil
+}
+
+// GetProject 返回作品详情，hashID 为作品链接 /artwork/<hash> 中的 hash，作品不存在时返回 nil
+func (c *Client) GetProject(hashID string) (*artstation_public_types.Project, error) {
+	var project artstation_public_types.Project
+
+	resp, err := c.reqClient.R().
+		SetSuccessResult(&project).
+		Get(fmt.Sprintf("/projects/%s.json", hashID))
+	if err != nil {
+		c.logger.Errorf("failed to get artstation project, err: %v", err)
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to get artstation project, status code: %d", resp.StatusCode)
+		return nil, fmt.Errorf("request to %s failed: status code: %d", resp.Request.URL, resp.StatusCode)
+	}
+
+	return &project, nil
+}
+
+// Download 下载 ArtStation CDN 上的图片，并以流的形式写入 w
+func (c *Client) Download(link string, w io.Writer) error {
+	resp, err := c.reqClient.R().
+		SetOutput(w).
+		Get(link)
+	if err != nil {
+		c.logger.Errorf("failed to fetch artstation image, err: %v", err)
+		return err
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to fetch artstation image, status code: %d", resp.StatusCode)
+		return fmt.Errorf("failed to fetch artstation image, status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
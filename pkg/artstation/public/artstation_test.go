@@ -0,0 +1,58 @@
+package artstation_public
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetProject(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		switch r.URL.Path {
+		case "/projects/AbCdE1.json":
+			_, _ = w.Write([]byte(`{
+				"id": 1,
+				"hash_id": "AbCdE1",
+				"title": "Castle",
+				"tags": ["environment", "concept art"],
+				"permalink": "https://www.artstation.com/artwork/AbCdE1",
+				"user": { "username": "alice", "full_name": "Alice", "permalink": "https://www.artstation.com/alice" },
+				"assets": [
+					{ "id": 10, "asset_type": "image", "has_image": true, "image_url": "https://cdna.artstation.com/p/assets/images/images/000/000/010/large/alice-castle.jpg?1700000000", "width": 3840, "height": 2160, "position": 0 },
+					{ "id": 11, "asset_type": "video_clip", "has_image": true, "image_url": "https://cdna.artstation.com/p/assets/video_clips/images/000/000/011/large/thumb.jpg", "player_embedded": "<iframe src='https://www.artstation.com/api/v2/animation/video_clips/11/player' width='100%'></iframe>", "position": 1 }
+				]
+			}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient(WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	project, err := client.GetProject("AbCdE1")
+	require.NoError(t, err)
+	require.NotNil(t, project)
+	assert.Equal(t, "Alice", project.User.Name())
+	require.Len(t, project.Assets, 2)
+
+	assert.True(t, project.Assets[0].IsImage())
+	assert.Equal(t, "https://cdna.artstation.com/p/assets/images/images/000/000/010/4k/alice-castle.jpg?1700000000", project.Assets[0].ImageURL4K())
+
+	assert.False(t, project.Assets[1].IsImage())
+	assert.True(t, project.Assets[1].IsVideo())
+	assert.Equal(t, "https://www.artstation.com/api/v2/animation/video_clips/11/player", project.Assets[1].PlayerURL())
+
+	project, err = client.GetProject("missing")
+	require.NoError(t, err)
+	assert.Nil(t, project)
+}
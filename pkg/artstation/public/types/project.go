@@ -0,0 +1,89 @@
+package artstation_public_types
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	AssetTypeImage = "image"
+	// AssetTypeCover 作品的封面，是其他资源的缩略图
+	AssetTypeCover = "cover"
+	// AssetTypeVideo 嵌入的 YouTube、Vimeo 等外部视频
+	AssetTypeVideo = "video"
+	// AssetTypeVideoClip 上传到 ArtStation 的视频，只能通过内嵌的播放器观看
+	AssetTypeVideoClip = "video_clip"
+)
+
+// Project ArtStation 的作品
+type Project struct {
+	ID     int    `json:"id"`
+	HashID string `json:"hash_id"`
+	Title  string `json:"title"`
+	// Description 纯文本的作品描述
+	Description  string   `json:"description"`
+	Tags         []string `json:"tags"`
+	Permalink    string   `json:"permalink"`
+	AdultContent bool     `json:"adult_content"`
+	User         *User    `json:"user"`
+	Assets       []*Asset `json:"assets"`
+}
+
+type User struct {
+	Username  string `json:"username"`
+	FullName  string `json:"full_name"`
+	Permalink string `json:"permalink"`
+}
+
+// Name 返回用户的名字，没有设置名字时返回用户名
+func (u *User) Name() string {
+	if u.FullName != "" {
+		return u.FullName
+	}
+
+	return u.Username
+}
+
+// Asset 作品中的图片、视频等资源，按照 Position 排列
+type Asset struct {
+	ID        int    `json:"id"`
+	AssetType string `json:"asset_type"`
+	HasImage  bool   `json:"has_image"`
+	// ImageURL large 尺寸的图片，视频为封面
+	ImageURL string `json:"image_url"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	Position int    `json:"position"`
+	// PlayerEmbedded 视频播放器的 iframe HTML
+	PlayerEmbedded string `json:"player_embedded"`
+}
+
+// IsImage 资源是否为图片
+func (a *Asset) IsImage() bool {
+	return a.AssetType == AssetTypeImage && a.HasImage && a.ImageURL != ""
+}
+
+// IsVideo 资源是否为视频
+func (a *Asset) IsVideo() bool {
+	return a.AssetType == AssetTypeVideo || a.AssetType == AssetTypeVideoClip
+}
+
+// ImageURL4K 返回 4k 尺寸的图片链接，原图较小时站点不会生成 4k 尺寸，链接会无法访问
+func (a *Asset) ImageURL4K() string {
+	return strings.Replace(a.ImageURL, "/large/", "/4k/", 1)
+}
+
+var iframeSrcRegexp = regexp.MustCompile(`<iframe[^>]*\ssrc=['"]([^'"]+)['"]`)
+
+// PlayerURL 返回视频播放器的链接，无法解析时返回空字符串
+func (a *Asset) PlayerURL() string {
+	matches := iframeSrcRegexp.FindStringSubmatch(a.PlayerEmbedded)
+	if len(matches) != 2 {
+		return ""
+	}
+	if strings.HasPrefix(matches[1], "//") {
+		return "https:" + matches[1]
+	}
+
+	return matches[1]
+}
@@ -0,0 +1,116 @@
+package bilibili_public
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/imroc/req/v3"
+	"github.com/sirupsen/logrus"
+
+	bilibili_public_types "github.com/nekomeowww/perobot/pkg/bilibili/public/types"
+	"github.com/nekomeowww/perobot/pkg/options"
+)
+
+const (
+	// DefaultAPIURL B 站 API 的地址
+	DefaultAPIURL = "https://api.bilibili.com"
+)
+
+type ClientOptions struct {
+	Logger *logrus.Entry
+	APIURL string
+}
+
+func WithLogger(logger *logrus.Entry) options.CallOptions[ClientOptions] {
+	return options.NewCallOptions(func(o *ClientOptions) {
+		o.Logger = logger
+	})
+}
+
+// WithAPIURL 设定 B 站 API 的地址
+func WithAPIURL(apiURL string) options.CallOptions[ClientOptions] {
+	return options.NewCallOptions(func(o *ClientOptions) {
+		o.APIURL = apiURL
+	})
+}
+
+// Client 访问 B 站无需登录的公开接口
+type Client struct {
+	reqClient *req.Client
+	logger    *logrus.Entry
+}
+
+func NewClient(callOpts ...options.CallOptions[ClientOptions]) (*Client, error) {
+	opts := options.ApplyCallOptions(callOpts, ClientOptions{
+		Logger: logrus.NewEntry(logrus.New()),
+		APIURL: DefaultAPIURL,
+	})
+
+	c := req.
+		C().
+		SetBaseURL(strings.TrimSuffix(opts.APIURL, "/")).
+		SetCommonHeader("Referer", "https://t.bilibili.com/").
+		SetCommonHeader("Origin", "https://t.bilibili.com").
+		SetUserAgent("Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/109.0.0.0 Safari/537.36 Edg/109.0.1518.52")
+
+	client := &Client{
+		reqClient: c,
+		logger:    opts.Logger,
+	}
+
+	return client, nil
+}
+
+// GetDynamicDetail 返回动态详情，动态不存在、被删除或不可见时返回 nil
+//
+// https://github.com/SocialSisterYi/bilibili-API-collect/blob/master/docs/dynamic/detail.md
+func (c *Client) GetDynamicDetail(id string) (*bilibili_public_types.DynamicItem, error) {
+	var detailResp bilibili_public_types.DynamicDetailResp
+
+	resp, err := c.reqClient.R().
+		SetQueryParam("id", id).
+		// 不带 features 时新版图文动态会以旧版的格式返回，缺少标题
+		SetQueryParam("features", "itemOpusStyle").
+		SetSuccessResult(&detailResp).
+		Get("/x/polymer/web-dynamic/v1/detail")
+	if err != nil {
+		c.logger.Errorf("failed to get bilibili dynamic detail, err: %v", err)
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to get bilibili dynamic detail, status code: %d", resp.StatusCode)
+		return nil, fmt.Errorf("request to %s failed: status code: %d", resp.Request.URL, resp.StatusCode)
+	}
+
+	switch detailResp.Code {
+	case 0:
+	case bilibili_public_types.CodeDynamicNotFound, bilibili_public_types.CodeDynamicUnavailable:
+		return nil, nil
+	default:
+		c.logger.Errorf("failed to get bilibili dynamic detail, code: %d, message: %s", detailResp.Code, detailResp.Message)
+		return nil, fmt.Errorf("request to %s failed: code: %d, message: %s", resp.Request.URL, detailResp.Code, detailResp.Message)
+	}
+	if detailResp.Data == nil || detailResp.Data.Item == nil {
+		return nil, nil
+	}
+
+	return detailResp.Data.Item, nil
+}
+
+// Download 下载 B 站图床上的图片，并以流的形式写入 w
+func (c *Client) Download(link string, w io.Writer) error {
+	resp, err := c.reqClient.R().
+		SetOutput(w).
+		Get(link)
+	if err != nil {
+		c.logger.Errorf("failed to fetch bilibili picture, err: %v", err)
+		return err
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to fetch bilibili picture, status code: %d", resp.StatusCode)
+		return fmt.Errorf("failed to fetch bilibili picture, status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package bilibili_public
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDynamicDetail(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/x/polymer/web-dynamic/v1/detail", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "itemOpusStyle", r.URL.Query().Get("features"))
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		switch r.URL.Query().Get("id") {
+		case "900000000000000001":
+			_, _ = w.Write([]byte(`{
+				"code": 0,
+				"message": "0",
+				"data": {
+					"item": {
+						"id_str": "900000000000000001",
+						"type": "DYNAMIC_TYPE_DRAW",
+						"visible": true,
+						"modules": {
+							"module_author": { "mid": 12345, "name": "画师" },
+							"module_dynamic": {
+								"desc": null,
+								"major": {
+									"type": "MAJOR_TYPE_OPUS",
+									"opus": {
+										"title": "",
+										"summary": { "text": "新图", "rich_text_nodes": [] },
+										"pics": [{ "url": "https://i0.hdslb.com/bfs/new_dyn/abc.png", "width": 2000, "height": 3000, "size": 1024.5 }]
+									}
+								}
+							}
+						}
+					}
+				}
+			}`))
+		case "900000000000000002":
+			_, _ = w.Write([]byte(`{"code":-352,"message":"风控校验失败","data":null}`))
+		default:
+			_, _ = w.Write([]byte(`{"code":4101131,"message":"动态不存在","data":null}`))
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient(WithAPIURL(server.URL))
+	require.NoError(t, err)
+
+	item, err := client.GetDynamicDetail("900000000000000001")
+	require.NoError(t, err)
+	require.NotNil(t, item)
+	assert.Equal(t, "画师", item.Author().Name)
+	assert.Equal(t, "新图", item.TextInHTML())
+	require.Len(t, item.Pictures(), 1)
+	assert.Equal(t, "https://i0.hdslb.com/bfs/new_dyn/abc.png", item.Pictures()[0].URL)
+
+	item, err = client.GetDynamicDetail("900000000000000000")
+	require.NoError(t, err)
+	assert.Nil(t, item)
+
+	_, err = client.GetDynamicDetail("900000000000000002")
+	assert.Error(t, err)
+}
@@ -0,0 +1,19 @@
+package bilibili_public_types
+
+const (
+	// CodeDynamicNotFound 动态不存在或已被删除
+	CodeDynamicNotFound = 4101131
+	// CodeDynamicUnavailable 动态不可见，例如仅粉丝可见或审核中
+	CodeDynamicUnavailable = 4101128
+)
+
+// DynamicDetailResp 动态详情接口的返回值，请求失败时 HTTP 状态码仍然可能为 200，以 Code 区分
+type DynamicDetailResp struct {
+	Code    int                `json:"code"`
+	Message string             `json:"message"`
+	Data    *DynamicDetailData `json:"data"`
+}
+
+type DynamicDetailData struct {
+	Item *DynamicItem `json:"item"`
+}
@@ -0,0 +1,194 @@
+package bilibili_public_types
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+const (
+	DynamicTypeForward = "DYNAMIC_TYPE_FORWARD"
+	DynamicTypeDraw    = "DYNAMIC_TYPE_DRAW"
+	DynamicTypeWord    = "DYNAMIC_TYPE_WORD"
+
+	MajorTypeDraw = "MAJOR_TYPE_DRAW"
+	MajorTypeOpus = "MAJOR_TYPE_OPUS"
+
+	RichTextNodeTypeText  = "RICH_TEXT_NODE_TYPE_TEXT"
+	RichTextNodeTypeWeb   = "RICH_TEXT_NODE_TYPE_WEB"
+	RichTextNodeTypeTopic = "RICH_TEXT_NODE_TYPE_TOPIC"
+	RichTextNodeTypeAt    = "RICH_TEXT_NODE_TYPE_AT"
+	RichTextNodeTypeEmoji = "RICH_TEXT_NODE_TYPE_EMOJI"
+)
+
+// DynamicItem 动态
+//
+// https://github.com/SocialSisterYi/bilibili-API-collect/blob/master/docs/dynamic/detail.md
+type DynamicItem struct {
+	IDStr   string          `json:"id_str"`
+	Type    string          `json:"type"`
+	Visible bool            `json:"visible"`
+	Modules *DynamicModules `json:"modules"`
+	// Orig 转发动态的原动态，不是转发时为 nil
+	Orig *DynamicItem `json:"orig,omitempty"`
+}
+
+type DynamicModules struct {
+	ModuleAuthor  *ModuleAuthor  `json:"module_author,omitempty"`
+	ModuleDynamic *ModuleDynamic `json:"module_dynamic,omitempty"`
+}
+
+type ModuleAuthor struct {
+	Mid  int64  `json:"mid"`
+	Name string `json:"name"`
+}
+
+// SpaceURL 返回作者的个人空间
+func (a *ModuleAuthor) SpaceURL() string {
+	return fmt.Sprintf("https://space.bilibili.com/%d", a.Mid)
+}
+
+type ModuleDynamic struct {
+	Desc  *RichText `json:"desc,omitempty"`
+	Major *Major    `json:"major,omitempty"`
+}
+
+type Major struct {
+	Type string     `json:"type"`
+	Draw *MajorDraw `json:"draw,omitempty"`
+	Opus *MajorOpus `json:"opus,omitempty"`
+}
+
+type MajorDraw struct {
+	Items []*DrawItem `json:"items"`
+}
+
+// DrawItem 图文动态中的图片，Src 为原图
+type DrawItem struct {
+	Src    string  `json:"src"`
+	Width  int     `json:"width"`
+	Height int     `json:"height"`
+	Size   float64 `json:"size"`
+}
+
+// MajorOpus 新版图文（opus）动态，正文在 Summary 中
+type MajorOpus struct {
+	Title   string     `json:"title"`
+	Summary *RichText  `json:"summary,omitempty"`
+	Pics    []*OpusPic `json:"pics"`
+}
+
+// OpusPic 新版图文动态中的图片，URL 为原图
+type OpusPic struct {
+	URL    string  `json:"url"`
+	Width  int     `json:"width"`
+	Height int     `json:"height"`
+	Size   float64 `json:"size"`
+}
+
+type RichText struct {
+	Text          string          `json:"text"`
+	RichTextNodes []*RichTextNode `json:"rich_text_nodes"`
+}
+
+type RichTextNode struct {
+	Type     string `json:"type"`
+	Text     string `json:"text"`
+	OrigText string `json:"orig_text"`
+	JumpURL  string `json:"jump_url"`
+}
+
+// Picture 动态中的一张原图
+type Picture struct {
+	URL    string
+	Width  int
+	Height int
+}
+
+// Author 返回动态的作者，没有作者信息时返回 nil
+func (d *DynamicItem) Author() *ModuleAuthor {
+	if d.Modules == nil {
+		return nil
+	}
+
+	return d.Modules.ModuleAuthor
+}
+
+// Pictures 返回动态中的原图，同时支持旧版的图文动态和新版的 opus 动态
+func (d *DynamicItem) Pictures() []Picture {
+	if d.Modules == nil || d.Modules.ModuleDynamic == nil || d.Modules.ModuleDynamic.Major == nil {
+		return []Picture{}
+	}
+
+	major := d.Modules.ModuleDynamic.Major
+	pictures := make([]Picture, 0)
+
+	switch {
+	case major.Draw != nil:
+		for _, item := range major.Draw.Items {
+			pictures = append(pictures, Picture{URL: item.Src, Width: item.Width, Height: item.Height})
+		}
+	case major.Opus != nil:
+		for _, pic := range major.Opus.Pics {
+			pictures = append(pictures, Picture{URL: pic.URL, Width: pic.Width, Height: pic.Height})
+		}
+	}
+
+	return pictures
+}
+
+// TextInHTML 将动态的正文转换为 Telegram 支持的 HTML，网页链接保留为链接，话题、提及的用户和表情保留原文，
+// opus 动态的标题放在正文之前
+func (d *DynamicItem) TextInHTML() string {
+	if d.Modules == nil || d.Modules.ModuleDynamic == nil {
+		return ""
+	}
+
+	moduleDynamic := d.Modules.ModuleDynamic
+
+	var title string
+	richText := moduleDynamic.Desc
+	if moduleDynamic.Major != nil && moduleDynamic.Major.Opus != nil {
+		title = moduleDynamic.Major.Opus.Title
+		if richText == nil {
+			richText = moduleDynamic.Major.Opus.Summary
+		}
+	}
+
+	text := richText.InHTML()
+	if title == "" {
+		return text
+	}
+	if text == "" {
+		return "<b>" + html.EscapeString(title) + "</b>"
+	}
+
+	return "<b>" + html.EscapeString(title) + "</b>\n" + text
+}
+
+// InHTML 将富文本转换为 Telegram 支持的 HTML
+func (r *RichText) InHTML() string {
+	if r == nil {
+		return ""
+	}
+	if len(r.RichTextNodes) == 0 {
+		return strings.TrimSpace(html.EscapeString(r.Text))
+	}
+
+	var sb strings.Builder
+	for _, node := range r.RichTextNodes {
+		switch node.Type {
+		case RichTextNodeTypeWeb:
+			jumpURL := node.JumpURL
+			if strings.HasPrefix(jumpURL, "//") {
+				jumpURL = "https:" + jumpURL
+			}
+
+			sb.WriteString(fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(jumpURL), html.EscapeString(node.Text)))
+		default:
+			sb.WriteString(html.EscapeString(node.Text))
+		}
+	}
+
+	return strings.TrimSpace(sb.String())
+}
@@ -0,0 +1,60 @@
+package bilibili_public_types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextInHTML(t *testing.T) {
+	item := &DynamicItem{
+		Modules: &DynamicModules{
+			ModuleDynamic: &ModuleDynamic{
+				Major: &Major{
+					Type: MajorTypeOpus,
+					Opus: &MajorOpus{
+						Title: "约稿 <完成>",
+						Summary: &RichText{
+							RichTextNodes: []*RichTextNode{
+								{Type: RichTextNodeTypeTopic, Text: "#原创#", JumpURL: "//search.bilibili.com/all?keyword=原创"},
+								{Type: RichTextNodeTypeText, Text: " 感谢 "},
+								{Type: RichTextNodeTypeAt, Text: "@某人"},
+								{Type: RichTextNodeTypeEmoji, Text: "[doge]"},
+								{Type: RichTextNodeTypeText, Text: "\n大图 "},
+								{Type: RichTextNodeTypeWeb, Text: "网页链接", JumpURL: "//www.pixiv.net/artworks/1"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, ""+
+		"<b>约稿 &lt;完成&gt;</b>\n"+
+		"#原创# 感谢 @某人[doge]\n"+
+		`大图 <a href="https://www.pixiv.net/artworks/1">网页链接</a>`,
+		item.TextInHTML())
+
+	item.Modules.ModuleDynamic = &ModuleDynamic{
+		Desc: &RichText{Text: "a < b"},
+	}
+
+	assert.Equal(t, "a &lt; b", item.TextInHTML())
+}
+
+func TestPictures(t *testing.T) {
+	item := &DynamicItem{
+		Modules: &DynamicModules{
+			ModuleDynamic: &ModuleDynamic{
+				Major: &Major{
+					Type: MajorTypeDraw,
+					Draw: &MajorDraw{Items: []*DrawItem{{Src: "https://i0.hdslb.com/bfs/album/a.gif", Width: 320, Height: 240}}},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []Picture{{URL: "https://i0.hdslb.com/bfs/album/a.gif", Width: 320, Height: 240}}, item.Pictures())
+	assert.Empty(t, (&DynamicItem{}).Pictures())
+}
@@ -0,0 +1,193 @@
+package bluesky_public
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/imroc/req/v3"
+	"github.com/sirupsen/logrus"
+
+	bluesky_public_types "github.com/nekomeowww/perobot/pkg/bluesky/public/types"
+	"github.com/nekomeowww/perobot/pkg/options"
+)
+
+const (
+	// DefaultAppViewURL 无需登录即可访问的公开 AppView
+	DefaultAppViewURL = "https://public.api.bsky.app"
+	// DefaultPLCDirectoryURL 解析 did:plc 的目录服务
+	DefaultPLCDirectoryURL = "https://plc.directory"
+)
+
+type ClientOptions struct {
+	Logger          *logrus.Entry
+	AppViewURL      string
+	PLCDirectoryURL string
+}
+
+func WithLogger(logger *logrus.Entry) options.CallOptions[ClientOptions] {
+	return options.NewCallOptions(func(o *ClientOptions) {
+		o.Logger = logger
+	})
+}
+
+// WithAppViewURL 设定 XRPC 请求发往的 AppView 地址
+func WithAppViewURL(appViewURL string) options.CallOptions[ClientOptions] {
+	return options.NewCallOptions(func(o *ClientOptions) {
+		o.AppViewURL = appViewURL
+	})
+}
+
+// WithPLCDirectoryURL 设定解析 did:plc 的目录服务地址
+func WithPLCDirectoryURL(plcDirectoryURL string) options.CallOptions[ClientOptions] {
+	return options.NewCallOptions(func(o *ClientOptions) {
+		o.PLCDirectoryURL = plcDirectoryURL
+	})
+}
+
+type Client struct {
+	reqClient *req.Client
+
+	logger          *logrus.Entry
+	plcDirectoryURL string
+}
+
+func NewClient(callOpts ...options.CallOptions[ClientOptions]) (*Client, error) {
+	opts := options.ApplyCallOptions(callOpts, ClientOptions{
+		Logger:          logrus.NewEntry(logrus.New()),
+		AppViewURL:      DefaultAppViewURL,
+		PLCDirectoryURL: DefaultPLCDirectoryURL,
+	})
+
+	c := req.
+		C().
+		SetBaseURL(strings.TrimSuffix(opts.AppViewURL, "/")).
+		SetUserAgent("Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/109.0.0.0 Safari/537.36 Edg/109.0.1518.52")
+
+	client := &Client{
+		reqClient:       c,
+		logger:          opts.Logger,
+		plcDirectoryURL: strings.TrimSuffix(opts.PLCDirectoryURL, "/"),
+	}
+
+	return client, nil
+}
+
+// PostURI 返回帖子的 AT URI，actor 可以是 handle 或者 DID
+func PostURI(actor string, rkey string) string {
+	return fmt.Sprintf("at://%s/app.bsky.feed.post/%s", actor, rkey)
+}
+
+// BlobURL 返回 PDS 上原始文件的下载链接，CDN 上的图片会被压缩，原图需要从 PDS 下载
+//
+// https://docs.bsky.app/docs/api/com-atproto-sync-get-blob
+func BlobURL(pdsEndpoint string, did string, cid string) string {
+	query := url.Values{}
+	query.Set("did", did)
+	query.Set("cid", cid)
+
+	return fmt.Sprintf("%s/xrpc/com.atproto.sync.getBlob?%s", strings.TrimSuffix(pdsEndpoint, "/"), query.Encode())
+}
+
+// GetPostThread 返回帖子详情，不获取回复和上文，帖子不存在时返回 NotFound 的讨论串
+//
+// https://docs.bsky.app/docs/api/app-bsky-feed-get-post-thread
+func (c *Client) GetPostThread(uri string) (*bluesky_public_types.GetPostThreadResp, error) {
+	var getPostThreadResp bluesky_public_types.GetPostThreadResp
+	var errorResp bluesky_public_types.ErrorResp
+
+	resp, err := c.reqClient.R().
+		SetQueryParam("uri", uri).
+		SetQueryParam("depth", "0").
+		SetQueryParam("parentHeight", "0").
+		SetSuccessResult(&getPostThreadResp).
+		SetErrorResult(&errorResp).
+		Get("/xrpc/app.bsky.feed.getPostThread")
+	if err != nil {
+		c.logger.Errorf("failed to get bluesky post thread, err: %v", err)
+		return nil, err
+	}
+	if errorResp.Error == "NotFound" {
+		return &bluesky_public_types.GetPostThreadResp{
+			Thread: &bluesky_public_types.ThreadViewPost{
+				Type:     bluesky_public_types.ThreadTypeNotFoundPost,
+				URI:      uri,
+				NotFound: true,
+			},
+		}, nil
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to get bluesky post thread, status code: %d, error: %s, message: %s", resp.StatusCode, errorResp.Error, errorResp.Message)
+		return nil, fmt.Errorf("request to %s failed: status code: %d, error: %s", resp.Request.URL, resp.StatusCode, errorResp.Error)
+	}
+
+	return &getPostThreadResp, nil
+}
+
+// ResolveDID 获取用户的 DID 文档，支持 did:plc 和 did:web
+//
+// https://atproto.com/specs/did
+func (c *Client) ResolveDID(did string) (*bluesky_public_types.DIDDocument, error) {
+	var documentURL string
+
+	switch {
+	case strings.HasPrefix(did, "did:plc:"):
+		documentURL = fmt.Sprintf("%s/%s", c.plcDirectoryURL, did)
+	case strings.HasPrefix(did, "did:web:"):
+		host, err := url.PathUnescape(strings.TrimPrefix(did, "did:web:"))
+		if err != nil {
+			return nil, err
+		}
+
+		documentURL = fmt.Sprintf("https://%s/.well-known/did.json", host)
+	default:
+		return nil, fmt.Errorf("unsupported did method: %s", did)
+	}
+
+	var didDocument bluesky_public_types.DIDDocument
+
+	resp, err := c.reqClient.R().
+		SetSuccessResult(&didDocument).
+		Get(documentURL)
+	if err != nil {
+		c.logger.Errorf("failed to resolve did %s, err: %v", did, err)
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to resolve did %s, status code: %d", did, resp.StatusCode)
+		return nil, fmt.Errorf("request to %s failed: status code: %d", resp.Request.URL, resp.StatusCode)
+	}
+
+	return &didDocument, nil
+}
+
+// VideoPlaylist 获取视频的 HLS 主播放列表，返回按照码率从高到低排列的清晰度
+func (c *Client) VideoPlaylist(playlistURL string) ([]bluesky_public_types.PlaylistVariant, error) {
+	buffer := new(bytes.Buffer)
+
+	err := c.Download(playlistURL, buffer)
+	if err != nil {
+		return nil, err
+	}
+
+	return bluesky_public_types.ParseMasterPlaylist(playlistURL, buffer.String())
+}
+
+// Download 下载 CDN 上的图片、PDS 上的原始文件或者播放列表，并以流的形式写入 w
+func (c *Client) Download(link string, w io.Writer) error {
+	resp, err := c.reqClient.R().
+		SetOutput(w).
+		Get(link)
+	if err != nil {
+		c.logger.Errorf("failed to fetch bluesky media, err: %v", err)
+		return err
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to fetch bluesky media, status code: %d", resp.StatusCode)
+		return fmt.Errorf("failed to fetch bluesky media, status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package bluesky_public
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	bluesky_public_types "github.com/nekomeowww/perobot/pkg/bluesky/public/types"
+)
+
+const testPostThread = `{
+	"thread": {
+		"$type": "app.bsky.feed.defs#threadViewPost",
+		"post": {
+			"uri": "at://did:plc:alice/app.bsky.feed.post/3kabcde",
+			"cid": "bafyreipost",
+			"author": {
+				"did": "did:plc:alice",
+				"handle": "alice.bsky.social",
+				"displayName": "Alice",
+				"labels": []
+			},
+			"record": {
+				"$type": "app.bsky.feed.post",
+				"text": "new drawing",
+				"createdAt": "2024-10-01T00:00:00.000Z",
+				"embed": {
+					"$type": "app.bsky.embed.recordWithMedia",
+					"record": { "$type": "app.bsky.embed.record" },
+					"media": {
+						"$type": "app.bsky.embed.images",
+						"images": [
+							{
+								"alt": "",
+								"image": {
+									"$type": "blob",
+									"ref": { "$link": "bafkreiimage" },
+									"mimeType": "image/png",
+									"size": 123456
+								},
+								"aspectRatio": { "width": 1200, "height": 1600 }
+							}
+						]
+					}
+				}
+			},
+			"embed": {
+				"$type": "app.bsky.embed.recordWithMedia#view",
+				"record": { "$type": "app.bsky.embed.record#view" },
+				"media": {
+					"$type": "app.bsky.embed.images#view",
+					"images": [
+						{
+							"thumb": "https://cdn.bsky.app/img/feed_thumbnail/plain/did:plc:alice/bafkreiimage@jpeg",
+							"fullsize": "https://cdn.bsky.app/img/feed_fullsize/plain/did:plc:alice/bafkreiimage@jpeg",
+							"alt": "",
+							"aspectRatio": { "width": 1200, "height": 1600 }
+						}
+					]
+				}
+			},
+			"labels": [{ "src": "did:plc:alice", "uri": "at://did:plc:alice/app.bsky.feed.post/3kabcde", "val": "sexual" }]
+		}
+	}
+}`
+
+func newTestServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/xrpc/app.bsky.feed.getPostThread", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "0", r.URL.Query().Get("depth"))
+		assert.Equal(t, "0", r.URL.Query().Get("parentHeight"))
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Query().Get("uri") {
+		case "at://alice.bsky.social/app.bsky.feed.post/3kabcde":
+			_, _ = w.Write([]byte(testPostThread))
+		case "at://alice.bsky.social/app.bsky.feed.post/unavailable":
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"error":"InternalServerError","message":"Internal Server Error"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"NotFound","message":"Post not found"}`))
+		}
+	})
+	mux.HandleFunc("/did:plc:alice", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "did:plc:alice",
+			"alsoKnownAs": ["at://alice.bsky.social"],
+			"service": [{ "id": "#atproto_pds", "type": "AtprotoPersonalDataServer", "serviceEndpoint": "https://pds.example.com/" }]
+		}`))
+	})
+	mux.HandleFunc("/watch/playlist.m3u8", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("#EXTM3U\n#EXT-X-STREAM-INF:BANDWIDTH=2850000,RESOLUTION=1280x720\n720p/video.m3u8\n"))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestGetPostThread(t *testing.T) {
+	server := newTestServer(t)
+
+	client, err := NewClient(WithAppViewURL(server.URL), WithPLCDirectoryURL(server.URL))
+	require.NoError(t, err)
+
+	resp, err := client.GetPostThread(PostURI("alice.bsky.social", "3kabcde"))
+	require.NoError(t, err)
+	require.NotNil(t, resp.Thread)
+	require.NotNil(t, resp.Thread.Post)
+
+	post := resp.Thread.Post
+	assert.Equal(t, "Alice", post.Author.Name())
+	assert.Equal(t, "new drawing", post.Record.TextInHTML())
+	assert.Equal(t, []string{bluesky_public_types.LabelSexual}, post.SensitiveLabelValues())
+
+	mediaView := post.Embed.MediaView()
+	require.NotNil(t, mediaView)
+	require.Len(t, mediaView.Images, 1)
+	assert.Equal(t, "https://cdn.bsky.app/img/feed_fullsize/plain/did:plc:alice/bafkreiimage@jpeg", mediaView.Images[0].Fullsize)
+
+	mediaEmbed := post.Record.Embed.MediaEmbed()
+	require.NotNil(t, mediaEmbed)
+	require.Len(t, mediaEmbed.Images, 1)
+	assert.Equal(t, "bafkreiimage", mediaEmbed.Images[0].Image.CID())
+	assert.Equal(t, "image/png", mediaEmbed.Images[0].Image.MimeType)
+
+	resp, err = client.GetPostThread(PostURI("alice.bsky.social", "deleted"))
+	require.NoError(t, err)
+	assert.True(t, resp.Thread.NotFound)
+	assert.Nil(t, resp.Thread.Post)
+
+	_, err = client.GetPostThread(PostURI("alice.bsky.social", "unavailable"))
+	assert.Error(t, err)
+}
+
+func TestResolveDID(t *testing.T) {
+	server := newTestServer(t)
+
+	client, err := NewClient(WithAppViewURL(server.URL), WithPLCDirectoryURL(server.URL))
+	require.NoError(t, err)
+
+	didDocument, err := client.ResolveDID("did:plc:alice")
+	require.NoError(t, err)
+	assert.Equal(t, "https://pds.example.com", didDocument.PDSEndpoint())
+
+	_, err = client.ResolveDID("did:plc:bob")
+	assert.Error(t, err)
+
+	_, err = client.ResolveDID("did:key:alice")
+	assert.Error(t, err)
+}
+
+func TestVideoPlaylistAndDownload(t *testing.T) {
+	server := newTestServer(t)
+
+	client, err := NewClient(WithAppViewURL(server.URL), WithPLCDirectoryURL(server.URL))
+	require.NoError(t, err)
+
+	variants, err := client.VideoPlaylist(server.URL + "/watch/playlist.m3u8")
+	require.NoError(t, err)
+	require.Len(t, variants, 1)
+	assert.Equal(t, 1280, variants[0].Width)
+	assert.Equal(t, 720, variants[0].Height)
+	assert.Equal(t, server.URL+"/watch/720p/video.m3u8", variants[0].URL)
+
+	buffer := new(bytes.Buffer)
+	err = client.Download(server.URL+"/missing.jpg", buffer)
+	assert.Error(t, err)
+}
+
+func TestBlobURL(t *testing.T) {
+	assert.Equal(t, "https://pds.example.com/xrpc/com.atproto.sync.getBlob?cid=bafkreiimage&did=did%3Aplc%3Aalice", BlobURL("https://pds.example.com/", "did:plc:alice", "bafkreiimage"))
+}
@@ -0,0 +1,18 @@
+package bluesky_public_types
+
+type ProfileViewBasic struct {
+	DID         string   `json:"did"`
+	Handle      string   `json:"handle"`
+	DisplayName string   `json:"displayName"`
+	Avatar      string   `json:"avatar"`
+	Labels      []*Label `json:"labels"`
+}
+
+// Name 返回用户的显示名称，没有设置时使用 handle
+func (p *ProfileViewBasic) Name() string {
+	if p.DisplayName != "" {
+		return p.DisplayName
+	}
+
+	return p.Handle
+}
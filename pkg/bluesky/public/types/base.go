@@ -0,0 +1,31 @@
+package bluesky_public_types
+
+// ErrorResp XRPC 接口请求失败时返回的错误
+type ErrorResp struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+// Blob 记录中引用的二进制文件，例如图片和视频
+type Blob struct {
+	Type string `json:"$type"`
+	Ref  struct {
+		Link string `json:"$link"`
+	} `json:"ref"`
+	MimeType string `json:"mimeType"`
+	Size     int64  `json:"size"`
+}
+
+// CID 返回文件的内容标识符
+func (b *Blob) CID() string {
+	if b == nil {
+		return ""
+	}
+
+	return b.Ref.Link
+}
+
+type AspectRatio struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
@@ -0,0 +1,35 @@
+package bluesky_public_types
+
+import "strings"
+
+const (
+	// ServiceIDAtprotoPDS DID 文档中用户数据所在的 PDS 服务
+	ServiceIDAtprotoPDS = "#atproto_pds"
+)
+
+// DIDDocument 用户的 DID 文档
+//
+// https://atproto.com/specs/did
+type DIDDocument struct {
+	ID          string        `json:"id"`
+	AlsoKnownAs []string      `json:"alsoKnownAs"`
+	Service     []*DIDService `json:"service"`
+}
+
+type DIDService struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	ServiceEndpoint string `json:"serviceEndpoint"`
+}
+
+// PDSEndpoint 返回用户数据所在的 PDS 地址，没有时返回空字符串
+func (d *DIDDocument) PDSEndpoint() string {
+	for _, service := range d.Service {
+		// 服务的 ID 可能是完整的 did:plc:xxx#atproto_pds
+		if strings.HasSuffix(service.ID, ServiceIDAtprotoPDS) {
+			return strings.TrimSuffix(service.ServiceEndpoint, "/")
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,99 @@
+package bluesky_public_types
+
+import "encoding/json"
+
+const (
+	EmbedTypeImagesView          = "app.bsky.embed.images#view"
+	EmbedTypeVideoView           = "app.bsky.embed.video#view"
+	EmbedTypeExternalView        = "app.bsky.embed.external#view"
+	EmbedTypeRecordView          = "app.bsky.embed.record#view"
+	EmbedTypeRecordWithMediaView = "app.bsky.embed.recordWithMedia#view"
+
+	EmbedTypeImages          = "app.bsky.embed.images"
+	EmbedTypeVideo           = "app.bsky.embed.video"
+	EmbedTypeRecordWithMedia = "app.bsky.embed.recordWithMedia"
+)
+
+// EmbedView AppView 返回的帖子附件，根据 $type 只有对应的字段有值
+type EmbedView struct {
+	Type string `json:"$type"`
+
+	// Images 图片附件 app.bsky.embed.images#view
+	Images []*EmbedImagesViewImage `json:"images,omitempty"`
+
+	// 视频附件 app.bsky.embed.video#view
+	CID string `json:"cid,omitempty"`
+	// Playlist 视频的 HLS 播放列表
+	Playlist    string       `json:"playlist,omitempty"`
+	Thumbnail   string       `json:"thumbnail,omitempty"`
+	Alt         string       `json:"alt,omitempty"`
+	AspectRatio *AspectRatio `json:"aspectRatio,omitempty"`
+
+	// Media 引用帖子时附带的图片或视频 app.bsky.embed.recordWithMedia#view
+	Media *EmbedView `json:"media,omitempty"`
+	// Record 引用的帖子，不会被展开
+	Record json.RawMessage `json:"record,omitempty"`
+}
+
+type EmbedImagesViewImage struct {
+	// Thumb 缩略图的 CDN 链接
+	Thumb string `json:"thumb"`
+	// Fullsize 大图的 CDN 链接，CDN 会压缩和转换为 JPEG
+	Fullsize    string       `json:"fullsize"`
+	Alt         string       `json:"alt"`
+	AspectRatio *AspectRatio `json:"aspectRatio,omitempty"`
+}
+
+// MediaView 返回帖子中的图片或视频附件，引用帖子时返回附带的媒体，没有时返回 nil
+func (e *EmbedView) MediaView() *EmbedView {
+	if e == nil {
+		return nil
+	}
+
+	switch e.Type {
+	case EmbedTypeImagesView, EmbedTypeVideoView:
+		return e
+	case EmbedTypeRecordWithMediaView:
+		return e.Media.MediaView()
+	default:
+		return nil
+	}
+}
+
+// Embed 帖子记录中的附件，根据 $type 只有对应的字段有值
+type Embed struct {
+	Type string `json:"$type"`
+
+	// Images 图片附件 app.bsky.embed.images
+	Images []*EmbedImage `json:"images,omitempty"`
+
+	// Video 视频附件 app.bsky.embed.video
+	Video       *Blob        `json:"video,omitempty"`
+	Alt         string       `json:"alt,omitempty"`
+	AspectRatio *AspectRatio `json:"aspectRatio,omitempty"`
+
+	// Media 引用帖子时附带的图片或视频 app.bsky.embed.recordWithMedia
+	Media *Embed `json:"media,omitempty"`
+}
+
+type EmbedImage struct {
+	Image       *Blob        `json:"image"`
+	Alt         string       `json:"alt"`
+	AspectRatio *AspectRatio `json:"aspectRatio,omitempty"`
+}
+
+// MediaEmbed 返回帖子记录中的图片或视频附件，引用帖子时返回附带的媒体，没有时返回 nil
+func (e *Embed) MediaEmbed() *Embed {
+	if e == nil {
+		return nil
+	}
+
+	switch e.Type {
+	case EmbedTypeImages, EmbedTypeVideo:
+		return e
+	case EmbedTypeRecordWithMedia:
+		return e.Media.MediaEmbed()
+	default:
+		return nil
+	}
+}
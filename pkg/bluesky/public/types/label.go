@@ -0,0 +1,48 @@
+package bluesky_public_types
+
+import "github.com/samber/lo"
+
+// Label 作者自己或者审核服务给帖子、用户打上的标签
+//
+// https://docs.bsky.app/docs/advanced-guides/moderation#labels
+type Label struct {
+	Src string `json:"src"`
+	URI string `json:"uri"`
+	CID string `json:"cid"`
+	Val string `json:"val"`
+	// Neg 为 true 时表示撤销之前打上的同名标签
+	Neg bool   `json:"neg"`
+	Cts string `json:"cts"`
+}
+
+const (
+	LabelPorn         = "porn"
+	LabelSexual       = "sexual"
+	LabelNudity       = "nudity"
+	LabelGraphicMedia = "graphic-media"
+	LabelGore         = "gore"
+)
+
+var (
+	// SensitiveLabels 需要按照敏感内容处理的标签
+	SensitiveLabels = []string{
+		LabelPorn,
+		LabelSexual,
+		LabelNudity,
+		LabelGraphicMedia,
+		LabelGore,
+	}
+)
+
+// SensitiveLabelValues 返回标签中的敏感内容标签，已撤销的标签不计入，结果不重复
+func SensitiveLabelValues(labels []*Label) []string {
+	negated := lo.FilterMap(labels, func(label *Label, _ int) (string, bool) {
+		return label.Val, label.Neg
+	})
+
+	values := lo.FilterMap(labels, func(label *Label, _ int) (string, bool) {
+		return label.Val, !label.Neg && lo.Contains(SensitiveLabels, label.Val) && !lo.Contains(negated, label.Val)
+	})
+
+	return lo.Uniq(values)
+}
@@ -0,0 +1,113 @@
+package bluesky_public_types
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PlaylistVariant HLS 主播放列表中的一个清晰度
+type PlaylistVariant struct {
+	Bandwidth int
+	Width     int
+	Height    int
+	// URL 该清晰度的媒体播放列表
+	URL string
+}
+
+// ParseMasterPlaylist 解析 HLS 主播放列表，返回按照码率从高到低排列的清晰度，相对链接基于 playlistURL 解析
+func ParseMasterPlaylist(playlistURL string, content string) ([]PlaylistVariant, error) {
+	baseURL, err := url.Parse(playlistURL)
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != "#EXTM3U" {
+		return nil, fmt.Errorf("invalid playlist: missing #EXTM3U header")
+	}
+
+	variants := make([]PlaylistVariant, 0)
+
+	var pending *PlaylistVariant
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#EXT-X-STREAM-INF:") {
+			pending = parseStreamInf(strings.TrimPrefix(line, "#EXT-X-STREAM-INF:"))
+			continue
+		}
+		if strings.HasPrefix(line, "#") || pending == nil {
+			continue
+		}
+
+		variantURL, err := baseURL.Parse(line)
+		if err != nil {
+			return nil, err
+		}
+
+		pending.URL = variantURL.String()
+		variants = append(variants, *pending)
+		pending = nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(variants, func(i, j int) bool {
+		return variants[i].Bandwidth > variants[j].Bandwidth
+	})
+
+	return variants, nil
+}
+
+// parseStreamInf 解析 #EXT-X-STREAM-INF 的属性，只关心码率和分辨率
+func parseStreamInf(attributes string) *PlaylistVariant {
+	variant := &PlaylistVariant{}
+
+	for _, attribute := range splitAttributes(attributes) {
+		name, value, ok := strings.Cut(attribute, "=")
+		if !ok {
+			continue
+		}
+
+		switch name {
+		case "BANDWIDTH":
+			variant.Bandwidth, _ = strconv.Atoi(value)
+		case "RESOLUTION":
+			width, height, ok := strings.Cut(value, "x")
+			if ok {
+				variant.Width, _ = strconv.Atoi(width)
+				variant.Height, _ = strconv.Atoi(height)
+			}
+		}
+	}
+
+	return variant
+}
+
+// splitAttributes 以逗号分隔属性，引号中的逗号不分隔，例如 CODECS="avc1.64001f,mp4a.40.2"
+func splitAttributes(attributes string) []string {
+	parts := make([]string, 0)
+
+	quoted := false
+	start := 0
+	for i, r := range attributes {
+		switch r {
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				parts = append(parts, attributes[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(parts, attributes[start:])
+}
@@ -0,0 +1,43 @@
+package bluesky_public_types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMasterPlaylist(t *testing.T) {
+	variants, err := ParseMasterPlaylist("https://video.bsky.app/watch/did%3Aplc%3Aalice/bafkrei/playlist.m3u8", ""+
+		"#EXTM3U\n"+
+		"#EXT-X-VERSION:3\n"+
+		"#EXT-X-STREAM-INF:BANDWIDTH=1088000,RESOLUTION=640x360,CODECS=\"avc1.64001e,mp4a.40.2\"\n"+
+		"360p/video.m3u8\n"+
+		"\n"+
+		"#EXT-X-STREAM-INF:BANDWIDTH=2850000,RESOLUTION=1280x720,CODECS=\"avc1.64001f,mp4a.40.2\"\n"+
+		"https://video.cdn.bsky.app/720p/video.m3u8\n")
+	require.NoError(t, err)
+	require.Len(t, variants, 2)
+
+	assert.Equal(t, PlaylistVariant{
+		Bandwidth: 2850000,
+		Width:     1280,
+		Height:    720,
+		URL:       "https://video.cdn.bsky.app/720p/video.m3u8",
+	}, variants[0])
+	assert.Equal(t, PlaylistVariant{
+		Bandwidth: 1088000,
+		Width:     640,
+		Height:    360,
+		URL:       "https://video.bsky.app/watch/did%3Aplc%3Aalice/bafkrei/360p/video.m3u8",
+	}, variants[1])
+
+	variants, err = ParseMasterPlaylist("https://video.bsky.app/playlist.m3u8", "#EXTM3U\n#EXT-X-ENDLIST\n")
+	require.NoError(t, err)
+	assert.Empty(t, variants)
+
+	_, err = ParseMasterPlaylist("https://video.bsky.app/playlist.m3u8", "not a playlist")
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{"BANDWIDTH=1", `CODECS="a,b"`, "RESOLUTION=1x1"}, splitAttributes(`BANDWIDTH=1,CODECS="a,b",RESOLUTION=1x1`))
+}
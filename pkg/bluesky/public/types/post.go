@@ -0,0 +1,130 @@
+package bluesky_public_types
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	FacetFeatureTypeLink    = "app.bsky.richtext.facet#link"
+	FacetFeatureTypeMention = "app.bsky.richtext.facet#mention"
+	FacetFeatureTypeTag     = "app.bsky.richtext.facet#tag"
+)
+
+// PostView AppView 返回的帖子
+type PostView struct {
+	URI         string            `json:"uri"`
+	CID         string            `json:"cid"`
+	Author      *ProfileViewBasic `json:"author"`
+	Record      *PostRecord       `json:"record"`
+	Embed       *EmbedView        `json:"embed,omitempty"`
+	ReplyCount  int               `json:"replyCount"`
+	RepostCount int               `json:"repostCount"`
+	LikeCount   int               `json:"likeCount"`
+	QuoteCount  int               `json:"quoteCount"`
+	IndexedAt   string            `json:"indexedAt"`
+	Labels      []*Label          `json:"labels"`
+}
+
+// SensitiveLabelValues 返回帖子和作者的敏感内容标签
+func (p *PostView) SensitiveLabelValues() []string {
+	labels := append([]*Label{}, p.Labels...)
+	if p.Author != nil {
+		labels = append(labels, p.Author.Labels...)
+	}
+
+	return SensitiveLabelValues(labels)
+}
+
+// PostRecord 帖子的记录 app.bsky.feed.post
+type PostRecord struct {
+	Type      string   `json:"$type"`
+	Text      string   `json:"text"`
+	CreatedAt string   `json:"createdAt"`
+	Langs     []string `json:"langs"`
+	Facets    []*Facet `json:"facets"`
+	Embed     *Embed   `json:"embed,omitempty"`
+}
+
+// Facet 富文本的标注，Index 为 UTF-8 字节的偏移量
+type Facet struct {
+	Index struct {
+		ByteStart int `json:"byteStart"`
+		ByteEnd   int `json:"byteEnd"`
+	} `json:"index"`
+	Features []*FacetFeature `json:"features"`
+}
+
+type FacetFeature struct {
+	Type string `json:"$type"`
+	// URI 链接 app.bsky.richtext.facet#link
+	URI string `json:"uri,omitempty"`
+	// DID 提及的用户 app.bsky.richtext.facet#mention
+	DID string `json:"did,omitempty"`
+	// Tag 话题标签 app.bsky.richtext.facet#tag
+	Tag string `json:"tag,omitempty"`
+}
+
+// TextInHTML 返回转义后的正文，被截断显示的链接和提及的用户替换为 HTML 链接，话题标签保留原文
+func (r *PostRecord) TextInHTML() string {
+	facets := make([]*Facet, 0, len(r.Facets))
+	for _, facet := range r.Facets {
+		if facet.Index.ByteStart < 0 || facet.Index.ByteStart >= facet.Index.ByteEnd || facet.Index.ByteEnd > len(r.Text) {
+			continue
+		}
+		if !utf8.ValidString(r.Text[facet.Index.ByteStart:facet.Index.ByteEnd]) {
+			continue
+		}
+
+		facets = append(facets, facet)
+	}
+
+	sort.SliceStable(facets, func(i, j int) bool {
+		return facets[i].Index.ByteStart < facets[j].Index.ByteStart
+	})
+
+	var sb strings.Builder
+	cursor := 0
+	for _, facet := range facets {
+		// 重叠的标注只处理第一个
+		if facet.Index.ByteStart < cursor {
+			continue
+		}
+
+		sb.WriteString(html.EscapeString(r.Text[cursor:facet.Index.ByteStart]))
+
+		text := html.EscapeString(r.Text[facet.Index.ByteStart:facet.Index.ByteEnd])
+		href := facetHref(facet)
+		if href != "" {
+			sb.WriteString(fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(href), text))
+		} else {
+			sb.WriteString(text)
+		}
+
+		cursor = facet.Index.ByteEnd
+	}
+
+	sb.WriteString(html.EscapeString(r.Text[cursor:]))
+
+	return sb.String()
+}
+
+func facetHref(facet *Facet) string {
+	for _, feature := range facet.Features {
+		switch feature.Type {
+		case FacetFeatureTypeLink:
+			if feature.URI != "" {
+				return feature.URI
+			}
+		case FacetFeatureTypeMention:
+			if feature.DID != "" {
+				return fmt.Sprintf("https://bsky.app/profile/%s", feature.DID)
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,67 @@
+package bluesky_public_types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextInHTML(t *testing.T) {
+	var record PostRecord
+	err := json.Unmarshal([]byte(`{
+		"$type": "app.bsky.feed.post",
+		"text": "新作 <3 example.com/art... @alice.bsky.social #illustration",
+		"facets": [
+			{
+				"index": { "byteStart": 48, "byteEnd": 61 },
+				"features": [{ "$type": "app.bsky.richtext.facet#tag", "tag": "illustration" }]
+			},
+			{
+				"index": { "byteStart": 10, "byteEnd": 28 },
+				"features": [{ "$type": "app.bsky.richtext.facet#link", "uri": "https://example.com/artworks?id=1&page=2" }]
+			},
+			{
+				"index": { "byteStart": 29, "byteEnd": 47 },
+				"features": [{ "$type": "app.bsky.richtext.facet#mention", "did": "did:plc:alice" }]
+			},
+			{
+				"index": { "byteStart": 100, "byteEnd": 120 },
+				"features": [{ "$type": "app.bsky.richtext.facet#link", "uri": "https://example.com" }]
+			}
+		]
+	}`), &record)
+	require.NoError(t, err)
+
+	assert.Equal(t, ""+
+		`新作 &lt;3 <a href="https://example.com/artworks?id=1&amp;page=2">example.com/art...</a> `+
+		`<a href="https://bsky.app/profile/did:plc:alice">@alice.bsky.social</a> #illustration`,
+		record.TextInHTML())
+
+	record.Facets = nil
+	assert.Equal(t, "新作 &lt;3 example.com/art... @alice.bsky.social #illustration", record.TextInHTML())
+}
+
+func TestSensitiveLabelValues(t *testing.T) {
+	post := &PostView{
+		Labels: []*Label{
+			{Val: LabelSexual},
+			{Val: "!no-unauthenticated"},
+			{Val: LabelGore},
+			{Val: LabelGore, Neg: true},
+		},
+		Author: &ProfileViewBasic{
+			Labels: []*Label{
+				{Val: LabelPorn},
+				{Val: LabelSexual},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{LabelSexual, LabelPorn}, post.SensitiveLabelValues())
+
+	post.Labels = nil
+	post.Author = nil
+	assert.Empty(t, post.SensitiveLabelValues())
+}
@@ -0,0 +1,21 @@
+package bluesky_public_types
+
+const (
+	ThreadTypeThreadViewPost = "app.bsky.feed.defs#threadViewPost"
+	ThreadTypeNotFoundPost   = "app.bsky.feed.defs#notFoundPost"
+	ThreadTypeBlockedPost    = "app.bsky.feed.defs#blockedPost"
+)
+
+// GetPostThreadResp app.bsky.feed.getPostThread 的响应
+type GetPostThreadResp struct {
+	Thread *ThreadViewPost `json:"thread"`
+}
+
+// ThreadViewPost 帖子所在的讨论串，帖子不存在或被屏蔽时 Post 为 nil
+type ThreadViewPost struct {
+	Type     string    `json:"$type"`
+	URI      string    `json:"uri"`
+	Post     *PostView `json:"post,omitempty"`
+	NotFound bool      `json:"notFound,omitempty"`
+	Blocked  bool      `json:"blocked,omitempty"`
+}
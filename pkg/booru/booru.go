@@ -0,0 +1,165 @@
+package booru
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/imroc/req/v3"
+	"github.com/sirupsen/logrus"
+
+	"github.com/nekomeowww/perobot/pkg/options"
+)
+
+// Rating 作品的分级，各个站点的分级统一为 Danbooru 的四个等级
+type Rating string
+
+const (
+	RatingGeneral      Rating = "general"
+	RatingSensitive    Rating = "sensitive"
+	RatingQuestionable Rating = "questionable"
+	RatingExplicit     Rating = "explicit"
+)
+
+// IsSensitive 分级为 questionable 或 explicit 时需要按照敏感内容处理
+func (r Rating) IsSensitive() bool {
+	return r == RatingQuestionable || r == RatingExplicit
+}
+
+// TagCategory 标签的分类
+type TagCategory string
+
+const (
+	TagCategoryGeneral   TagCategory = "general"
+	TagCategoryArtist    TagCategory = "artist"
+	TagCategoryCopyright TagCategory = "copyright"
+	TagCategoryCharacter TagCategory = "character"
+	TagCategoryMeta      TagCategory = "meta"
+)
+
+// Post 统一了各个站点格式的作品
+type Post struct {
+	ID     string
+	Rating Rating
+	// FileURL 原始文件的链接，被站点隐藏时为空
+	FileURL string
+	// SampleURLs 按照清晰度从高到低排列的缩小版本或转换为 JPEG 的版本，不包含与 FileURL 相同的链接
+	SampleURLs []string
+	// PreviewURL 缩略图的链接
+	PreviewURL string
+	// FileExt 原始文件的扩展名，不包含 .
+	FileExt string
+	Width   int
+	Height  int
+	MD5     string
+	// Source 作品的出处，通常为链接，也可能是任意文字
+	Source string
+	// Tags 按照分类整理的标签，站点没有提供分类的标签归为 general
+	Tags map[TagCategory][]string
+}
+
+// TagsOf 返回分类 category 中的标签
+func (p *Post) TagsOf(category TagCategory) []string {
+	return p.Tags[category]
+}
+
+// HasAnyTag 任意分类中包含 tags 中的任意一个标签时返回 true，不区分大小写
+func (p *Post) HasAnyTag(tags ...string) bool {
+	for _, categoryTags := range p.Tags {
+		for _, tag := range categoryTags {
+			for _, candidate := range tags {
+				if strings.EqualFold(tag, candidate) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+type ClientOptions struct {
+	Logger         *logrus.Entry
+	GelbooruAPIKey string
+	GelbooruUserID string
+}
+
+func WithLogger(logger *logrus.Entry) options.CallOptions[ClientOptions] {
+	return options.NewCallOptions(func(o *ClientOptions) {
+		o.Logger = logger
+	})
+}
+
+// WithGelbooruCredential 设定访问 Gelbooru API 所使用的 API Key 和用户 ID，可以在 Gelbooru 的账号设置中获取
+func WithGelbooruCredential(apiKey string, userID string) options.CallOptions[ClientOptions] {
+	return options.NewCallOptions(func(o *ClientOptions) {
+		o.GelbooruAPIKey = apiKey
+		o.GelbooruUserID = userID
+	})
+}
+
+// Client 访问 Danbooru、Gelbooru 以及 Moebooru（yande.re、konachan）的公开 API，
+// 所有请求都需要传入站点的地址，例如 https://danbooru.donmai.us
+type Client struct {
+	reqClient *req.Client
+	logger    *logrus.Entry
+
+	gelbooruAPIKey string
+	gelbooruUserID string
+}
+
+func NewClient(callOpts ...options.CallOptions[ClientOptions]) (*Client, error) {
+	opts := options.ApplyCallOptions(callOpts, ClientOptions{
+		Logger: logrus.NewEntry(logrus.New()),
+	})
+
+	c := req.
+		C().
+		SetCommonHeader("Accept", "application/json").
+		SetUserAgent("perobot/1.0 (+https://github.com/nekomeowww/perobot)")
+
+	client := &Client{
+		reqClient:      c,
+		logger:         opts.Logger,
+		gelbooruAPIKey: opts.GelbooruAPIKey,
+		gelbooruUserID: opts.GelbooruUserID,
+	}
+
+	return client, nil
+}
+
+// Download 下载作品的文件，并以流的形式写入 w
+func (c *Client) Download(link string, w io.Writer) error {
+	resp, err := c.reqClient.R().
+		SetOutput(w).
+		Get(link)
+	if err != nil {
+		c.logger.Errorf("failed to fetch booru file, err: %v", err)
+		return err
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to fetch booru file, status code: %d", resp.StatusCode)
+		return fmt.Errorf("failed to fetch booru file, status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// splitTags 拆分以空格分隔的标签
+func splitTags(tags string) []string {
+	return strings.Fields(tags)
+}
+
+// sampleURLs 去除空的和与原始文件相同的链接
+func sampleURLs(fileURL string, urls ...string) []string {
+	samples := make([]string, 0, len(urls))
+	for _, sampleURL := range urls {
+		if sampleURL == "" || sampleURL == fileURL {
+			continue
+		}
+
+		samples = append(samples, sampleURL)
+	}
+
+	return samples
+}
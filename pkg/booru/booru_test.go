@@ -0,0 +1,181 @@
+package booru
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/posts/1234.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": 1234,
+			"rating": "q",
+			"file_url": "https://cdn.donmai.us/original/ab/cd/abcd.png",
+			"large_file_url": "https://cdn.donmai.us/sample/ab/cd/sample-abcd.jpg",
+			"preview_file_url": "https://cdn.donmai.us/180x180/ab/cd/abcd.jpg",
+			"file_ext": "png",
+			"image_width": 2400,
+			"image_height": 3200,
+			"md5": "abcd",
+			"source": "https://www.pixiv.net/artworks/5678",
+			"tag_string_general": "1girl solo",
+			"tag_string_artist": "alice_(artist)",
+			"tag_string_copyright": "fate/grand_order",
+			"tag_string_character": "mash_kyrielight",
+			"tag_string_meta": "highres"
+		}`))
+	})
+	mux.HandleFunc("/posts/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"success":false,"error":"ActiveRecord::RecordNotFound","message":"That record was not found."}`))
+	})
+	mux.HandleFunc("/index.php", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "dapi", query.Get("page"))
+		assert.Equal(t, "1", query.Get("json"))
+		assert.Equal(t, "key", query.Get("api_key"))
+		assert.Equal(t, "42", query.Get("user_id"))
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case query.Get("s") == "post" && query.Get("id") == "1234":
+			_, _ = w.Write([]byte(`{
+				"@attributes": { "limit": 100, "offset": 0, "count": 1 },
+				"post": [{
+					"id": 1234,
+					"rating": "explicit",
+					"file_url": "https://img3.gelbooru.com/images/ab/cd/abcd.jpg",
+					"sample_url": "",
+					"preview_url": "https://img3.gelbooru.com/thumbnails/ab/cd/thumbnail_abcd.jpg",
+					"image": "abcd.jpg",
+					"width": 1000,
+					"height": 1400,
+					"source": "",
+					"tags": "1girl alice_(artist) hatsune_miku vocaloid"
+				}]
+			}`))
+		case query.Get("s") == "post":
+			_, _ = w.Write([]byte(`{"@attributes": { "limit": 100, "offset": 0, "count": 0 }}`))
+		case query.Get("s") == "tag":
+			assert.Equal(t, "1girl alice_(artist) hatsune_miku vocaloid", query.Get("names"))
+			_, _ = w.Write([]byte(`{"tag": [
+				{ "name": "1girl", "type": 0 },
+				{ "name": "alice_(artist)", "type": 1 },
+				{ "name": "hatsune_miku", "type": 4 },
+				{ "name": "vocaloid", "type": 3 }
+			]}`))
+		}
+	})
+	mux.HandleFunc("/post.json", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "2", query.Get("api_version"))
+		assert.Equal(t, "1", query.Get("include_tags"))
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if query.Get("tags") != "id:1234" {
+			_, _ = w.Write([]byte(`{"posts": [], "pools": [], "tags": {}}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{
+			"posts": [{
+				"id": 1234,
+				"rating": "s",
+				"file_url": "https://files.yande.re/image/abcd/yande.re%201234.png",
+				"file_ext": "png",
+				"jpeg_url": "https://files.yande.re/jpeg/abcd/yande.re%201234.jpg",
+				"sample_url": "https://files.yande.re/sample/abcd/yande.re%201234.jpg",
+				"preview_url": "https://assets.yande.re/data/preview/ab/cd/abcd.jpg",
+				"width": 4000,
+				"height": 6000,
+				"md5": "abcd",
+				"source": "https://twitter.com/alice/status/1",
+				"tags": "alice circle_a dress genshin_impact lumine"
+			}],
+			"tags": { "alice": "artist", "circle_a": "circle", "dress": "general", "genshin_impact": "copyright", "lumine": "character" }
+		}`))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestDanbooruPost(t *testing.T) {
+	server := newTestServer(t)
+
+	client, err := NewClient()
+	require.NoError(t, err)
+
+	post, err := client.DanbooruPost(server.URL, "1234")
+	require.NoError(t, err)
+	require.NotNil(t, post)
+	assert.Equal(t, RatingQuestionable, post.Rating)
+	assert.True(t, post.Rating.IsSensitive())
+	assert.Equal(t, []string{"https://cdn.donmai.us/sample/ab/cd/sample-abcd.jpg"}, post.SampleURLs)
+	assert.Equal(t, "png", post.FileExt)
+	assert.Equal(t, []string{"alice_(artist)"}, post.TagsOf(TagCategoryArtist))
+	assert.Equal(t, []string{"fate/grand_order"}, post.TagsOf(TagCategoryCopyright))
+	assert.Equal(t, []string{"mash_kyrielight"}, post.TagsOf(TagCategoryCharacter))
+
+	post, err = client.DanbooruPost(server.URL, "1")
+	require.NoError(t, err)
+	assert.Nil(t, post)
+}
+
+func TestGelbooruPost(t *testing.T) {
+	server := newTestServer(t)
+
+	client, err := NewClient(WithGelbooruCredential("key", "42"))
+	require.NoError(t, err)
+
+	post, err := client.GelbooruPost(server.URL, "1234")
+	require.NoError(t, err)
+	require.NotNil(t, post)
+	assert.Equal(t, RatingExplicit, post.Rating)
+	assert.Empty(t, post.SampleURLs)
+	assert.Equal(t, "jpg", post.FileExt)
+	assert.Equal(t, []string{"1girl"}, post.TagsOf(TagCategoryGeneral))
+	assert.Equal(t, []string{"alice_(artist)"}, post.TagsOf(TagCategoryArtist))
+	assert.Equal(t, []string{"vocaloid"}, post.TagsOf(TagCategoryCopyright))
+	assert.Equal(t, []string{"hatsune_miku"}, post.TagsOf(TagCategoryCharacter))
+
+	post, err = client.GelbooruPost(server.URL, "1")
+	require.NoError(t, err)
+	assert.Nil(t, post)
+}
+
+func TestMoebooruPost(t *testing.T) {
+	server := newTestServer(t)
+
+	client, err := NewClient()
+	require.NoError(t, err)
+
+	post, err := client.MoebooruPost(server.URL, "1234")
+	require.NoError(t, err)
+	require.NotNil(t, post)
+	assert.Equal(t, RatingGeneral, post.Rating)
+	assert.False(t, post.Rating.IsSensitive())
+	assert.Equal(t, []string{
+		"https://files.yande.re/jpeg/abcd/yande.re%201234.jpg",
+		"https://files.yande.re/sample/abcd/yande.re%201234.jpg",
+	}, post.SampleURLs)
+	assert.Equal(t, []string{"alice", "circle_a"}, post.TagsOf(TagCategoryArtist))
+	assert.Equal(t, []string{"genshin_impact"}, post.TagsOf(TagCategoryCopyright))
+	assert.Equal(t, []string{"lumine"}, post.TagsOf(TagCategoryCharacter))
+	assert.Equal(t, []string{"dress"}, post.TagsOf(TagCategoryGeneral))
+
+	post, err = client.MoebooruPost(server.URL, "1")
+	require.NoError(t, err)
+	assert.Nil(t, post)
+}
@@ -0,0 +1,87 @@
+package booru
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DanbooruPost Danbooru 的作品
+//
+// https://danbooru.donmai.us/wiki_pages/api:posts
+type DanbooruPost struct {
+	ID     int    `json:"id"`
+	Rating string `json:"rating"`
+	// FileURL 原始文件，被隐藏的作品（例如需要 Gold 账号）没有该字段
+	FileURL string `json:"file_url"`
+	// LargeFileURL 宽度为 850 的缩小版本，原始文件较小时与 FileURL 相同，动图为转换后的 WebM
+	LargeFileURL       string `json:"large_file_url"`
+	PreviewFileURL     string `json:"preview_file_url"`
+	FileExt            string `json:"file_ext"`
+	ImageWidth         int    `json:"image_width"`
+	ImageHeight        int    `json:"image_height"`
+	MD5                string `json:"md5"`
+	Source             string `json:"source"`
+	TagStringGeneral   string `json:"tag_string_general"`
+	TagStringArtist    string `json:"tag_string_artist"`
+	TagStringCopyright string `json:"tag_string_copyright"`
+	TagStringCharacter string `json:"tag_string_character"`
+	TagStringMeta      string `json:"tag_string_meta"`
+}
+
+// DanbooruErrorResp Danbooru 请求失败时返回的错误
+type DanbooruErrorResp struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+var danbooruRatings = map[string]Rating{
+	"g": RatingGeneral,
+	"s": RatingSensitive,
+	"q": RatingQuestionable,
+	"e": RatingExplicit,
+}
+
+// DanbooruPost 获取 Danbooru 的作品，作品不存在时返回 nil
+func (c *Client) DanbooruPost(siteURL string, postID string) (*Post, error) {
+	var danbooruPost DanbooruPost
+	var errorResp DanbooruErrorResp
+
+	resp, err := c.reqClient.R().
+		SetSuccessResult(&danbooruPost).
+		SetErrorResult(&errorResp).
+		Get(fmt.Sprintf("%s/posts/%s.json", strings.TrimSuffix(siteURL, "/"), postID))
+	if err != nil {
+		c.logger.Errorf("failed to get danbooru post, err: %v", err)
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to get danbooru post, status code: %d, error: %s, message: %s", resp.StatusCode, errorResp.Error, errorResp.Message)
+		return nil, fmt.Errorf("request to %s failed: status code: %d, error: %s", resp.Request.URL, resp.StatusCode, errorResp.Error)
+	}
+
+	return &Post{
+		ID:         strconv.Itoa(danbooruPost.ID),
+		Rating:     danbooruRatings[danbooruPost.Rating],
+		FileURL:    danbooruPost.FileURL,
+		SampleURLs: sampleURLs(danbooruPost.FileURL, danbooruPost.LargeFileURL),
+		PreviewURL: danbooruPost.PreviewFileURL,
+		FileExt:    danbooruPost.FileExt,
+		Width:      danbooruPost.ImageWidth,
+		Height:     danbooruPost.ImageHeight,
+		MD5:        danbooruPost.MD5,
+		Source:     danbooruPost.Source,
+		Tags: map[TagCategory][]string{
+			TagCategoryGeneral:   splitTags(danbooruPost.TagStringGeneral),
+			TagCategoryArtist:    splitTags(danbooruPost.TagStringArtist),
+			TagCategoryCopyright: splitTags(danbooruPost.TagStringCopyright),
+			TagCategoryCharacter: splitTags(danbooruPost.TagStringCharacter),
+			TagCategoryMeta:      splitTags(danbooruPost.TagStringMeta),
+		},
+	}, nil
+}
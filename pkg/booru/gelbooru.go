@@ -0,0 +1,152 @@
+package booru
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/imroc/req/v3"
+)
+
+// GelbooruPost Gelbooru 的作品
+//
+// https://gelbooru.com/index.php?page=wiki&s=view&id=18780
+type GelbooruPost struct {
+	ID         int    `json:"id"`
+	Rating     string `json:"rating"`
+	FileURL    string `json:"file_url"`
+	SampleURL  string `json:"sample_url"`
+	PreviewURL string `json:"preview_url"`
+	Image      string `json:"image"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	MD5        string `json:"md5"`
+	Source     string `json:"source"`
+	// Tags 以空格分隔的标签，不包含分类
+	Tags string `json:"tags"`
+}
+
+type GelbooruPostsResp struct {
+	Post []*GelbooruPost `json:"post"`
+}
+
+// GelbooruTag Gelbooru 的标签
+type GelbooruTag struct {
+	Name string `json:"name"`
+	// Type 标签的分类，0 为一般，1 为作者，3 为版权，4 为角色，5 为元数据
+	Type int `json:"type"`
+}
+
+type GelbooruTagsResp struct {
+	Tag []*GelbooruTag `json:"tag"`
+}
+
+var gelbooruTagCategories = map[int]TagCategory{
+	0: TagCategoryGeneral,
+	1: TagCategoryArtist,
+	3: TagCategoryCopyright,
+	4: TagCategoryCharacter,
+	5: TagCategoryMeta,
+}
+
+// GelbooruPost 获取 Gelbooru 的作品，并通过标签接口获取标签的分类，作品不存在时返回 nil
+func (c *Client) GelbooruPost(siteURL string, postID string) (*Post, error) {
+	var postsResp GelbooruPostsResp
+
+	resp, err := c.gelbooruRequest().
+		SetQueryParam("s", "post").
+		SetQueryParam("id", postID).
+		SetSuccessResult(&postsResp).
+		Get(fmt.Sprintf("%s/index.php", strings.TrimSuffix(siteURL, "/")))
+	if err != nil {
+		c.logger.Errorf("failed to get gelbooru post, err: %v", err)
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to get gelbooru post, status code: %d", resp.StatusCode)
+		return nil, fmt.Errorf("request to %s failed: status code: %d", resp.Request.URL, resp.StatusCode)
+	}
+	if len(postsResp.Post) == 0 {
+		return nil, nil
+	}
+
+	gelbooruPost := postsResp.Post[0]
+	tags := splitTags(gelbooruPost.Tags)
+
+	post := &Post{
+		ID:         strconv.Itoa(gelbooruPost.ID),
+		Rating:     Rating(gelbooruPost.Rating),
+		FileURL:    gelbooruPost.FileURL,
+		SampleURLs: sampleURLs(gelbooruPost.FileURL, gelbooruPost.SampleURL),
+		PreviewURL: gelbooruPost.PreviewURL,
+		FileExt:    strings.TrimPrefix(path.Ext(gelbooruPost.Image), "."),
+		Width:      gelbooruPost.Width,
+		Height:     gelbooruPost.Height,
+		MD5:        gelbooruPost.MD5,
+		Source:     gelbooruPost.Source,
+		Tags: map[TagCategory][]string{
+			TagCategoryGeneral: tags,
+		},
+	}
+
+	// 标签的分类只影响说明文字中的话题标签，获取失败时全部作为一般标签
+	categorizedTags, err := c.gelbooruTagCategories(siteURL, tags)
+	if err != nil {
+		c.logger.Warnf("failed to get gelbooru tag categories, err: %v", err)
+		return post, nil
+	}
+
+	post.Tags = categorizedTags
+
+	return post, nil
+}
+
+// gelbooruTagCategories 获取标签的分类，接口没有返回的标签归为一般标签
+func (c *Client) gelbooruTagCategories(siteURL string, tags []string) (map[TagCategory][]string, error) {
+	var tagsResp GelbooruTagsResp
+
+	resp, err := c.gelbooruRequest().
+		SetQueryParam("s", "tag").
+		SetQueryParam("names", strings.Join(tags, " ")).
+		SetSuccessResult(&tagsResp).
+		Get(fmt.Sprintf("%s/index.php", strings.TrimSuffix(siteURL, "/")))
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		return nil, fmt.Errorf("request to %s failed: status code: %d", resp.Request.URL, resp.StatusCode)
+	}
+
+	tagTypes := make(map[string]int, len(tagsResp.Tag))
+	for _, tag := range tagsResp.Tag {
+		tagTypes[tag.Name] = tag.Type
+	}
+
+	categorizedTags := make(map[TagCategory][]string)
+	for _, tag := range tags {
+		category, ok := gelbooruTagCategories[tagTypes[tag]]
+		if !ok {
+			category = TagCategoryGeneral
+		}
+
+		categorizedTags[category] = append(categorizedTags[category], tag)
+	}
+
+	return categorizedTags, nil
+}
+
+// gelbooruRequest 返回带有 DAPI 公共参数和 API Key 的请求
+func (c *Client) gelbooruRequest() *req.Request {
+	request := c.reqClient.R().
+		SetQueryParam("page", "dapi").
+		SetQueryParam("q", "index").
+		SetQueryParam("json", "1")
+	if c.gelbooruAPIKey != "" && c.gelbooruUserID != "" {
+		request.
+			SetQueryParam("api_key", c.gelbooruAPIKey).
+			SetQueryParam("user_id", c.gelbooruUserID)
+	}
+
+	return request
+}
@@ -0,0 +1,101 @@
+package booru
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MoebooruPost Moebooru（yande.re、konachan）的作品
+//
+// https://yande.re/help/api
+type MoebooruPost struct {
+	ID     int    `json:"id"`
+	Rating string `json:"rating"`
+	// FileURL 原始文件，可能是 PNG
+	FileURL string `json:"file_url"`
+	FileExt string `json:"file_ext"`
+	// JPEGURL 原始尺寸的 JPEG 版本，原始文件为 JPEG 时与 FileURL 相同
+	JPEGURL    string `json:"jpeg_url"`
+	SampleURL  string `json:"sample_url"`
+	PreviewURL string `json:"preview_url"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	MD5        string `json:"md5"`
+	Source     string `json:"source"`
+	// Tags 以空格分隔的标签，分类在 MoebooruPostsResp.Tags 中
+	Tags string `json:"tags"`
+}
+
+// MoebooruPostsResp api_version=2 的作品列表，include_tags=1 时附带标签的分类
+type MoebooruPostsResp struct {
+	Posts []*MoebooruPost `json:"posts"`
+	// Tags 标签到分类名称的映射，例如 artist、copyright、character、general、circle、faults
+	Tags map[string]string `json:"tags"`
+}
+
+var moebooruRatings = map[string]Rating{
+	"s": RatingGeneral,
+	"q": RatingQuestionable,
+	"e": RatingExplicit,
+}
+
+var moebooruTagCategories = map[string]TagCategory{
+	"general":   TagCategoryGeneral,
+	"artist":    TagCategoryArtist,
+	"copyright": TagCategoryCopyright,
+	"character": TagCategoryCharacter,
+	// circle 为社团，在说明文字中与作者同等对待
+	"circle": TagCategoryArtist,
+	"faults": TagCategoryMeta,
+	"style":  TagCategoryMeta,
+}
+
+// MoebooruPost 获取 Moebooru 的作品，作品不存在时返回 nil
+func (c *Client) MoebooruPost(siteURL string, postID string) (*Post, error) {
+	var postsResp MoebooruPostsResp
+
+	resp, err := c.reqClient.R().
+		SetQueryParam("tags", "id:"+postID).
+		SetQueryParam("api_version", "2").
+		SetQueryParam("include_tags", "1").
+		SetSuccessResult(&postsResp).
+		Get(fmt.Sprintf("%s/post.json", strings.TrimSuffix(siteURL, "/")))
+	if err != nil {
+		c.logger.Errorf("failed to get moebooru post, err: %v", err)
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to get moebooru post, status code: %d", resp.StatusCode)
+		return nil, fmt.Errorf("request to %s failed: status code: %d", resp.Request.URL, resp.StatusCode)
+	}
+	if len(postsResp.Posts) == 0 {
+		return nil, nil
+	}
+
+	moebooruPost := postsResp.Posts[0]
+
+	categorizedTags := make(map[TagCategory][]string)
+	for _, tag := range splitTags(moebooruPost.Tags) {
+		category, ok := moebooruTagCategories[postsResp.Tags[tag]]
+		if !ok {
+			category = TagCategoryGeneral
+		}
+
+		categorizedTags[category] = append(categorizedTags[category], tag)
+	}
+
+	return &Post{
+		ID:         strconv.Itoa(moebooruPost.ID),
+		Rating:     moebooruRatings[moebooruPost.Rating],
+		FileURL:    moebooruPost.FileURL,
+		SampleURLs: sampleURLs(moebooruPost.FileURL, moebooruPost.JPEGURL, moebooruPost.SampleURL),
+		PreviewURL: moebooruPost.PreviewURL,
+		FileExt:    moebooruPost.FileExt,
+		Width:      moebooruPost.Width,
+		Height:     moebooruPost.Height,
+		MD5:        moebooruPost.MD5,
+		Source:     moebooruPost.Source,
+		Tags:       categorizedTags,
+	}, nil
+}
@@ -0,0 +1,54 @@
+package telegram
+
+import (
+	"encoding/json"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// SendAnimation 发送动图（GIF 或无声的 MP4）
+//
+// tgbotapi 不支持 has_spoiler，因此自行构造 sendAnimation 请求。
+func SendAnimation(bot *tgbotapi.BotAPI, config tgbotapi.AnimationConfig, spoiler bool) (tgbotapi.Message, error) {
+	params, files, err := prepareAnimationRequest(config, spoiler)
+	if err != nil {
+		return tgbotapi.Message{}, err
+	}
+
+	resp, err := bot.UploadFiles("sendAnimation", params, files)
+	if err != nil {
+		return tgbotapi.Message{}, err
+	}
+
+	var message tgbotapi.Message
+
+	err = json.Unmarshal(resp.Result, &message)
+	if err != nil {
+		return tgbotapi.Message{}, err
+	}
+
+	return message, nil
+}
+
+func prepareAnimationRequest(config tgbotapi.AnimationConfig, spoiler bool) (tgbotapi.Params, []tgbotapi.RequestFile, error) {
+	params := make(tgbotapi.Params)
+
+	err := params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	params.AddNonZero("reply_to_message_id", config.ReplyToMessageID)
+	params.AddBool("disable_notification", config.DisableNotification)
+	params.AddNonZero("duration", config.Duration)
+	params.AddNonEmpty("caption", config.Caption)
+	params.AddNonEmpty("parse_mode", config.ParseMode)
+	params.AddBool("has_spoiler", spoiler)
+
+	files := []tgbotapi.RequestFile{{Name: "animation", Data: config.File}}
+	if config.Thumb != nil {
+		files = append(files, tgbotapi.RequestFile{Name: "thumb", Data: config.Thumb})
+	}
+
+	return params, files, nil
+}
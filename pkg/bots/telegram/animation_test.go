@@ -0,0 +1,29 @@
+package telegram
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrepareAnimationRequest(t *testing.T) {
+	config := tgbotapi.NewAnimation(1234, tgbotapi.FileBytes{Name: "animation.gif", Bytes: []byte("GIF89a")})
+	config.Caption = "caption"
+	config.ParseMode = "HTML"
+
+	params, files, err := prepareAnimationRequest(config, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, "1234", params["chat_id"])
+	assert.Equal(t, "caption", params["caption"])
+	assert.Equal(t, "HTML", params["parse_mode"])
+	assert.Equal(t, "true", params["has_spoiler"])
+	require.Len(t, files, 1)
+	assert.Equal(t, "animation", files[0].Name)
+
+	params, _, err = prepareAnimationRequest(config, false)
+	require.NoError(t, err)
+	assert.NotContains(t, params, "has_spoiler")
+}
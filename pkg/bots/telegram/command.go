@@ -0,0 +1,51 @@
+package telegram
+
+import (
+	"strings"
+)
+
+const (
+	postCommand      = "/t"
+	forcePostCommand = "/t!"
+)
+
+// PostCommand 频道中用于发布来源作品的命令
+//
+// 1. /t <url> 发布作品，已经发布过的作品不会再次发布；
+// 2. /t! <url> 无论是否发布过都强制发布。
+type PostCommand struct {
+	// Argument 命令后的参数，通常是来源作品的链接
+	Argument string
+	// Force 是否强制发布
+	Force bool
+}
+
+// ParseCommand 解析消息中的命令，返回命令和命令后的参数，群组中 /command@bot 形式的命令会去掉 @bot 部分，
+// 不是命令时返回 false
+func ParseCommand(text string) (command string, argument string, ok bool) {
+	if !strings.HasPrefix(text, "/") {
+		return "", "", false
+	}
+
+	command, argument, _ = strings.Cut(strings.TrimSpace(text), " ")
+	command, _, _ = strings.Cut(command, "@")
+
+	return command, strings.TrimSpace(argument), true
+}
+
+// ParsePostCommand 解析消息中的 /t 或 /t! 命令，不是发布命令时返回 false
+func ParsePostCommand(text string) (*PostCommand, bool) {
+	command, argument, ok := ParseCommand(text)
+	if !ok || argument == "" {
+		return nil, false
+	}
+
+	switch command {
+	case postCommand:
+		return &PostCommand{Argument: argument}, true
+	case forcePostCommand:
+		return &PostCommand{Argument: argument, Force: true}, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,51 @@
+package telegram
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommand(t *testing.T) {
+	command, argument, ok := ParseCommand("/queue remove 12")
+	assert.True(t, ok)
+	assert.Equal(t, "/queue", command)
+	assert.Equal(t, "remove 12", argument)
+
+	command, argument, ok = ParseCommand("/queue@perobot")
+	assert.True(t, ok)
+	assert.Equal(t, "/queue", command)
+	assert.Empty(t, argument)
+
+	_, _, ok = ParseCommand("queue")
+	assert.False(t, ok)
+}
+
+func TestParsePostCommand(t *testing.T) {
+	t.Run("Post", func(t *testing.T) {
+		command, ok := ParsePostCommand("/t https://twitter.com/a/status/1")
+		assert.True(t, ok)
+		assert.Equal(t, "https://twitter.com/a/status/1", command.Argument)
+		assert.False(t, command.Force)
+	})
+
+	t.Run("Force", func(t *testing.T) {
+		command, ok := ParsePostCommand("/t! https://www.pixiv.net/artworks/1234")
+		assert.True(t, ok)
+		assert.Equal(t, "https://www.pixiv.net/artworks/1234", command.Argument)
+		assert.True(t, command.Force)
+	})
+
+	t.Run("Mention", func(t *testing.T) {
+		command, ok := ParsePostCommand("/t@perobot https://twitter.com/a/status/1")
+		assert.True(t, ok)
+		assert.Equal(t, "https://twitter.com/a/status/1", command.Argument)
+	})
+
+	t.Run("NotCommand", func(t *testing.T) {
+		for _, text := range []string{"", "/t", "/t ", "/tt https://twitter.com", "https://twitter.com", "!t https://twitter.com"} {
+			_, ok := ParsePostCommand(text)
+			assert.False(t, ok, text)
+		}
+	})
+}
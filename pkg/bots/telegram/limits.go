@@ -0,0 +1,147 @@
+package telegram
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/nekomeowww/imaging"
+)
+
+const (
+	// MB 1 MiB
+	MB int64 = 1024 * 1024
+)
+
+var (
+	ErrMediaTooLarge = errors.New("media is too large to be sent to telegram")
+)
+
+// SizePolicy Telegram 对于上传媒体的大小和尺寸限制
+//
+// https://core.telegram.org/bots/api#sendphoto
+//
+// https://core.telegram.org/bots/api#sending-files
+type SizePolicy struct {
+	// MaxPhotoSize 照片的最大大小
+	MaxPhotoSize int64
+	// MaxPhotoDimensionSum 照片宽高之和的最大值
+	MaxPhotoDimensionSum int
+	// MaxPhotoAspectRatio 照片宽高比（长边比短边）的最大值
+	MaxPhotoAspectRatio float64
+	// MaxUploadSize 视频、文件等其他媒体的最大上传大小
+	MaxUploadSize int64
+}
+
+// DefaultSizePolicy 使用官方 Bot API 服务器时的限制
+var DefaultSizePolicy = SizePolicy{
+	MaxPhotoSize:         10 * MB,
+	MaxPhotoDimensionSum: 10000,
+	MaxPhotoAspectRatio:  20,
+	MaxUploadSize:        50 * MB,
+}
+
+// PreparedPhoto 经过 SizePolicy.PreparePhoto 检查和处理后的照片
+type PreparedPhoto struct {
+	// Bytes 可以直接上传的照片数据
+	Bytes []byte
+	// AsDocument 照片无法满足照片的限制，需要以文件的形式发送
+	AsDocument bool
+	// Resized 照片是否经过了缩小或重新编码
+	Resized bool
+	// Image 解码后的照片，无法解码时为 nil，可用于计算感知哈希等后续处理
+	Image image.Image
+}
+
+// FitsUpload 判断视频、文件等媒体的大小是否可以上传
+func (p SizePolicy) FitsUpload(size int64) bool {
+	return size <= p.MaxUploadSize
+}
+
+// PreparePhoto 检查照片是否满足 Telegram 对照片的限制
+//
+// 1. 满足限制的照片会被原样返回；
+// 2. 尺寸或大小超出限制的照片会被等比缩小并重新编码为 JPEG，直到满足限制，JPEG 不支持透明度，透明的部分会被填充为白色；
+// 3. 宽高比超出限制、无法解码或无法压缩到限制以内的照片，会在不超过 MaxUploadSize 的前提下以文件的形式发送；
+// 4. 连文件都无法发送的照片会返回 ErrMediaTooLarge。
+func (p SizePolicy) PreparePhoto(data []byte) (*PreparedPhoto, error) {
+	size := int64(len(data))
+
+	img, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		return p.photoAsDocument(data, nil)
+	}
+
+	original := img
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	if width == 0 || height == 0 {
+		return p.photoAsDocument(data, nil)
+	}
+	if aspectRatio(width, height) > p.MaxPhotoAspectRatio {
+		return p.photoAsDocument(data, original)
+	}
+	if size <= p.MaxPhotoSize && width+height <= p.MaxPhotoDimensionSum {
+		return &PreparedPhoto{Bytes: data, Image: original}, nil
+	}
+
+	// 先等比缩小到宽高之和满足限制
+	if width+height > p.MaxPhotoDimensionSum {
+		scale := float64(p.MaxPhotoDimensionSum) / float64(width+height)
+		img = imaging.Resize(img, int(float64(width)*scale), 0, imaging.Lanczos)
+	}
+
+	img = flattenOnWhite(img)
+
+	// 再逐步降低 JPEG 质量和尺寸，直到大小满足限制
+	quality := 95
+	for attempt := 0; attempt < 8; attempt++ {
+		buffer := new(bytes.Buffer)
+
+		err = imaging.Encode(buffer, img, imaging.JPEG, imaging.JPEGQuality(quality))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode resized photo: %w", err)
+		}
+		if int64(buffer.Len()) <= p.MaxPhotoSize {
+			return &PreparedPhoto{Bytes: buffer.Bytes(), Resized: true, Image: original}, nil
+		}
+
+		if quality > 75 {
+			quality -= 10
+		} else {
+			img = imaging.Resize(img, img.Bounds().Dx()*4/5, 0, imaging.Lanczos)
+		}
+	}
+
+	return p.photoAsDocument(data, original)
+}
+
+func (p SizePolicy) photoAsDocument(data []byte, img image.Image) (*PreparedPhoto, error) {
+	if !p.FitsUpload(int64(len(data))) {
+		return nil, ErrMediaTooLarge
+	}
+
+	return &PreparedPhoto{Bytes: data, AsDocument: true, Image: img}, nil
+}
+
+// flattenOnWhite 将含有透明部分的图片叠加到白色背景上，否则编码为 JPEG 时透明的部分会变为黑色
+func flattenOnWhite(img image.Image) image.Image {
+	opaque, ok := img.(interface{ Opaque() bool })
+	if ok && opaque.Opaque() {
+		return img
+	}
+
+	bounds := img.Bounds()
+	background := imaging.New(bounds.Dx(), bounds.Dy(), color.White)
+
+	return imaging.Overlay(background, img, image.Point{}, 1)
+}
+
+func aspectRatio(width, height int) float64 {
+	if width > height {
+		return float64(width) / float64(height)
+	}
+
+	return float64(height) / float64(width)
+}
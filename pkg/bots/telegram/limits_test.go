@@ -0,0 +1,85 @@
+package telegram
+
+import (
+	"bytes"
+	"image"
+	"testing"
+
+	"github.com/nekomeowww/imaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPNG(t *testing.T, width, height int) []byte {
+	buffer := new(bytes.Buffer)
+	err := imaging.Encode(buffer, image.NewNRGBA(image.Rect(0, 0, width, height)), imaging.PNG)
+	require.NoError(t, err)
+
+	return buffer.Bytes()
+}
+
+func TestSizePolicyPreparePhoto(t *testing.T) {
+	policy := SizePolicy{
+		MaxPhotoSize:         10 * MB,
+		MaxPhotoDimensionSum: 1000,
+		MaxPhotoAspectRatio:  20,
+		MaxUploadSize:        50 * MB,
+	}
+
+	t.Run("Unchanged", func(t *testing.T) {
+		data := newTestPNG(t, 400, 300)
+
+		prepared, err := policy.PreparePhoto(data)
+		require.NoError(t, err)
+		assert.False(t, prepared.AsDocument)
+		assert.False(t, prepared.Resized)
+		assert.Equal(t, data, prepared.Bytes)
+		require.NotNil(t, prepared.Image)
+		assert.Equal(t, 400, prepared.Image.Bounds().Dx())
+	})
+
+	t.Run("Downscaled", func(t *testing.T) {
+		prepared, err := policy.PreparePhoto(newTestPNG(t, 1600, 1200))
+		require.NoError(t, err)
+		assert.False(t, prepared.AsDocument)
+		assert.True(t, prepared.Resized)
+
+		config, format, err := image.DecodeConfig(bytes.NewReader(prepared.Bytes))
+		require.NoError(t, err)
+		assert.Equal(t, "jpeg", format)
+		assert.LessOrEqual(t, config.Width+config.Height, 1000)
+	})
+
+	t.Run("TransparentFlattenedOnWhite", func(t *testing.T) {
+		// newTestPNG 的图片完全透明
+		prepared, err := policy.PreparePhoto(newTestPNG(t, 1600, 1200))
+		require.NoError(t, err)
+		require.True(t, prepared.Resized)
+
+		img, err := imaging.Decode(bytes.NewReader(prepared.Bytes))
+		require.NoError(t, err)
+
+		r, g, b, _ := img.At(10, 10).RGBA()
+		assert.Greater(t, r>>8, uint32(250))
+		assert.Greater(t, g>>8, uint32(250))
+		assert.Greater(t, b>>8, uint32(250))
+	})
+
+	t.Run("ExtremeAspectRatio", func(t *testing.T) {
+		prepared, err := policy.PreparePhoto(newTestPNG(t, 21, 1))
+		require.NoError(t, err)
+		assert.True(t, prepared.AsDocument)
+	})
+
+	t.Run("Undecodable", func(t *testing.T) {
+		prepared, err := policy.PreparePhoto([]byte("not an image"))
+		require.NoError(t, err)
+		assert.True(t, prepared.AsDocument)
+
+		policy := policy
+		policy.MaxUploadSize = 4
+
+		_, err = policy.PreparePhoto([]byte("not an image"))
+		assert.ErrorIs(t, err, ErrMediaTooLarge)
+	})
+}
@@ -0,0 +1,319 @@
+package telegram
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/samber/lo"
+)
+
+// MaxMediaGroupSize Telegram 单个相册（media group）最多可包含的媒体数量
+//
+// https://core.telegram.org/bots/api#sendmediagroup
+const MaxMediaGroupSize = 10
+
+var (
+	ErrEmptyMediaGroup = errors.New("media group is empty")
+)
+
+// MediaGroupBuilder 相册构建器
+//
+// 将任意数量的媒体切分为多个连续的、每个最多 MaxMediaGroupSize 个媒体的相册，
+// 标题只会设置在第一个相册的第一个媒体上，之后的相册会依次回复上一个相册的第一条消息，
+// 以便在客户端中把这些相册串联起来。
+//
+// Telegram 不允许文件、音频与照片、视频混合在同一个相册中，因此媒体的种类变化时会开始新的相册，
+// 所有媒体都按照追加的顺序发送。
+type MediaGroupBuilder struct {
+	chatID           int64
+	replyToMessageID int
+	caption          string
+	parseMode        string
+	spoiler          bool
+	onSent           func(sent []tgbotapi.Message)
+	media            []interface{}
+}
+
+// NewMediaGroupBuilder 创建发送到 chatID 的相册构建器
+func NewMediaGroupBuilder(chatID int64) *MediaGroupBuilder {
+	return &MediaGroupBuilder{
+		chatID: chatID,
+		media:  make([]interface{}, 0),
+	}
+}
+
+// ReplyTo 设定第一个相册所回复的消息
+func (b *MediaGroupBuilder) ReplyTo(messageID int) *MediaGroupBuilder {
+	b.replyToMessageID = messageID
+	return b
+}
+
+// Caption 设定相册的标题，只会出现在第一个相册的第一个媒体上
+func (b *MediaGroupBuilder) Caption(caption string, parseMode string) *MediaGroupBuilder {
+	b.caption = caption
+	b.parseMode = parseMode
+	return b
+}
+
+// Spoiler 设定是否为相册中的照片和视频加上剧透遮罩（has_spoiler），文件和音频不支持剧透遮罩
+func (b *MediaGroupBuilder) Spoiler(spoiler bool) *MediaGroupBuilder {
+	b.spoiler = spoiler
+	return b
+}
+
+// OnSent 设定每个相册发送成功后调用的函数，sent 为该相册的消息，
+// 可以在之后的相册仍在上传时就使用第一个相册的消息
+func (b *MediaGroupBuilder) OnSent(fn func(sent []tgbotapi.Message)) *MediaGroupBuilder {
+	b.onSent = fn
+	return b
+}
+
+// Add 追加媒体，支持 tgbotapi.InputMediaPhoto、tgbotapi.InputMediaVideo、
+// tgbotapi.InputMediaDocument 与 tgbotapi.InputMediaAudio
+func (b *MediaGroupBuilder) Add(media ...interface{}) *MediaGroupBuilder {
+	b.media = append(b.media, media...)
+	return b
+}
+
+// Len 返回已追加的媒体数量
+func (b *MediaGroupBuilder) Len() int {
+	return len(b.media)
+}
+
+// Build 构建切分后的相册，只有第一个相册会带有 ReplyTo 所设定的回复消息，
+// 之后的相册所回复的消息需要在发送时才能确定
+func (b *MediaGroupBuilder) Build() []tgbotapi.MediaGroupConfig {
+	chunks := make([][]interface{}, 0)
+	for _, group := range groupMediaByKind(b.media) {
+		media := lo.Map(group, func(index int, _ int) interface{} { return b.media[index] })
+		chunks = append(chunks, lo.Chunk(media, MaxMediaGroupSize)...)
+	}
+
+	configs := make([]tgbotapi.MediaGroupConfig, 0, len(chunks))
+
+	for i, chunk := range chunks {
+		media := make([]interface{}, len(chunk))
+		copy(media, chunk)
+
+		if i == 0 && b.caption != "" {
+			media[0] = withCaption(media[0], b.caption, b.parseMode)
+		}
+
+		config := tgbotapi.MediaGroupConfig{
+			ChatID: b.chatID,
+			Media:  media,
+		}
+		if i == 0 {
+			config.ReplyToMessageID = b.replyToMessageID
+		}
+
+		configs = append(configs, config)
+	}
+
+	return configs
+}
+
+// Send 依次发送切分后的相册，并按顺序返回所有已发送的消息
+//
+// 如果中途发送失败，会返回已经发送成功的消息以及错误。
+func (b *MediaGroupBuilder) Send(bot *tgbotapi.BotAPI) ([]tgbotapi.Message, error) {
+	if len(b.media) == 0 {
+		return nil, ErrEmptyMediaGroup
+	}
+
+	messages := make([]tgbotapi.Message, 0, len(b.media))
+	previousFirstMessageID := b.replyToMessageID
+
+	for i, config := range b.Build() {
+		if i > 0 {
+			config.ReplyToMessageID = previousFirstMessageID
+		}
+
+		sent, err := sendMediaGroup(bot, config, b.spoiler)
+		if err != nil {
+			return messages, err
+		}
+		if len(sent) > 0 {
+			previousFirstMessageID = sent[0].MessageID
+		}
+
+		messages = append(messages, sent...)
+		if b.onSent != nil {
+			b.onSent(sent)
+		}
+	}
+
+	return messages, nil
+}
+
+type mediaKind int
+
+const (
+	mediaKindVisual mediaKind = iota
+	mediaKindDocument
+	mediaKindAudio
+)
+
+// SentOrder 返回发送时各个媒体的顺序，第 i 个元素是 Send 返回的第 i 条消息所对应的、
+// 通过 Add 追加时的下标
+func (b *MediaGroupBuilder) SentOrder() []int {
+	return lo.Flatten(groupMediaByKind(b.media))
+}
+
+// groupMediaByKind 将可以放在同一个相册中的连续媒体分为一组，返回各组中媒体的下标，保持媒体原本的顺序
+func groupMediaByKind(media []interface{}) [][]int {
+	groups := make([][]int, 0)
+	previousKind := mediaKind(-1)
+	for i, m := range media {
+		var kind mediaKind
+
+		switch m.(type) {
+		case tgbotapi.InputMediaDocument:
+			kind = mediaKindDocument
+		case tgbotapi.InputMediaAudio:
+			kind = mediaKindAudio
+		default:
+			kind = mediaKindVisual
+		}
+
+		if kind != previousKind {
+			groups = append(groups, make([]int, 0))
+			previousKind = kind
+		}
+
+		groups[len(groups)-1] = append(groups[len(groups)-1], i)
+	}
+
+	return groups
+}
+
+func withCaption(media interface{}, caption string, parseMode string) interface{} {
+	switch m := media.(type) {
+	case tgbotapi.InputMediaPhoto:
+		m.Caption = caption
+		m.ParseMode = parseMode
+		return m
+	case tgbotapi.InputMediaVideo:
+		m.Caption = caption
+		m.ParseMode = parseMode
+		return m
+	case tgbotapi.InputMediaDocument:
+		m.Caption = caption
+		m.ParseMode = parseMode
+		return m
+	case tgbotapi.InputMediaAudio:
+		m.Caption = caption
+		m.ParseMode = parseMode
+		return m
+	default:
+		return media
+	}
+}
+
+// sendMediaGroup 发送单个相册
+//
+// tgbotapi 上传缩略图时所使用的文件名与 attach:// 引用不一致，导致缩略图不会生效，
+// 并且不支持 has_spoiler，因此自行构造 sendMediaGroup 请求。
+func sendMediaGroup(bot *tgbotapi.BotAPI, config tgbotapi.MediaGroupConfig, spoiler bool) ([]tgbotapi.Message, error) {
+	params, files, err := prepareMediaGroupRequest(config, spoiler)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := bot.UploadFiles("sendMediaGroup", params, files)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []tgbotapi.Message
+
+	err = json.Unmarshal(resp.Result, &messages)
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func prepareMediaGroupRequest(config tgbotapi.MediaGroupConfig, spoiler bool) (tgbotapi.Params, []tgbotapi.RequestFile, error) {
+	params := make(tgbotapi.Params)
+
+	err := params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	params.AddNonZero("reply_to_message_id", config.ReplyToMessageID)
+	params.AddBool("disable_notification", config.DisableNotification)
+
+	files := make([]tgbotapi.RequestFile, 0)
+	media := make([]map[string]interface{}, 0, len(config.Media))
+
+	for i, m := range config.Media {
+		attached, attachedFiles, supportsSpoiler := attachInputMedia(m, i)
+		files = append(files, attachedFiles...)
+
+		content, err := json.Marshal(attached)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		fields := make(map[string]interface{})
+
+		err = json.Unmarshal(content, &fields)
+		if err != nil {
+			return nil, nil, err
+		}
+		if spoiler && supportsSpoiler {
+			fields["has_spoiler"] = true
+		}
+
+		media = append(media, fields)
+	}
+
+	err = params.AddInterface("media", media)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return params, files, nil
+}
+
+// attachInputMedia 将需要上传的媒体和缩略图替换为 attach:// 引用，返回替换后的媒体、需要上传的文件，
+// 以及媒体是否支持剧透遮罩
+func attachInputMedia(media interface{}, index int) (interface{}, []tgbotapi.RequestFile, bool) {
+	files := make([]tgbotapi.RequestFile, 0)
+	attach := func(data tgbotapi.RequestFileData, name string) tgbotapi.RequestFileData {
+		if data == nil || !data.NeedsUpload() {
+			return data
+		}
+
+		files = append(files, tgbotapi.RequestFile{Name: name, Data: data})
+		return tgbotapi.FileURL("attach://" + name)
+	}
+
+	fileName := fmt.Sprintf("file-%d", index)
+	thumbName := fmt.Sprintf("file-%d-thumb", index)
+
+	switch m := media.(type) {
+	case tgbotapi.InputMediaPhoto:
+		m.Media = attach(m.Media, fileName)
+		return m, files, true
+	case tgbotapi.InputMediaVideo:
+		m.Media = attach(m.Media, fileName)
+		m.Thumb = attach(m.Thumb, thumbName)
+		return m, files, true
+	case tgbotapi.InputMediaDocument:
+		m.Media = attach(m.Media, fileName)
+		m.Thumb = attach(m.Thumb, thumbName)
+		return m, files, false
+	case tgbotapi.InputMediaAudio:
+		m.Media = attach(m.Media, fileName)
+		m.Thumb = attach(m.Thumb, thumbName)
+		return m, files, false
+	default:
+		return media, files, false
+	}
+}
@@ -0,0 +1,119 @@
+package telegram
+
+import (
+	"encoding/json"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMediaGroupBuilderBuild(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	builder := NewMediaGroupBuilder(1234).ReplyTo(5678).Caption("caption", "HTML")
+	for i := 0; i < 23; i++ {
+		builder.Add(tgbotapi.NewInputMediaPhoto(tgbotapi.FileID("file")))
+	}
+
+	configs := builder.Build()
+	require.Len(configs, 3)
+	assert.Len(configs[0].Media, 10)
+	assert.Len(configs[1].Media, 10)
+	assert.Len(configs[2].Media, 3)
+
+	assert.Equal(5678, configs[0].ReplyToMessageID)
+	assert.Zero(configs[1].ReplyToMessageID)
+	assert.Zero(configs[2].ReplyToMessageID)
+
+	first, ok := configs[0].Media[0].(tgbotapi.InputMediaPhoto)
+	require.True(ok)
+	assert.Equal("caption", first.Caption)
+	assert.Equal("HTML", first.ParseMode)
+
+	for _, config := range configs[1:] {
+		assert.Equal(int64(1234), config.ChatID)
+
+		media, ok := config.Media[0].(tgbotapi.InputMediaPhoto)
+		require.True(ok)
+		assert.Empty(media.Caption)
+	}
+}
+
+func TestMediaGroupBuilderBuildEmpty(t *testing.T) {
+	assert.Empty(t, NewMediaGroupBuilder(1234).Build())
+}
+
+func TestMediaGroupBuilderBuildMixed(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	builder := NewMediaGroupBuilder(1234).Caption("caption", "HTML")
+	builder.Add(
+		tgbotapi.NewInputMediaDocument(tgbotapi.FileID("document-1")),
+		tgbotapi.NewInputMediaPhoto(tgbotapi.FileID("photo-1")),
+		tgbotapi.NewInputMediaVideo(tgbotapi.FileID("video-1")),
+		tgbotapi.NewInputMediaDocument(tgbotapi.FileID("document-2")),
+	)
+
+	// 种类变化时开始新的相册，保持追加的顺序
+	configs := builder.Build()
+	require.Len(configs, 3)
+	require.Len(configs[0].Media, 1)
+	require.Len(configs[1].Media, 2)
+	require.Len(configs[2].Media, 1)
+
+	document, ok := configs[0].Media[0].(tgbotapi.InputMediaDocument)
+	require.True(ok)
+	assert.Equal("caption", document.Caption)
+
+	photo, ok := configs[1].Media[0].(tgbotapi.InputMediaPhoto)
+	require.True(ok)
+	assert.Empty(photo.Caption)
+
+	_, ok = configs[1].Media[1].(tgbotapi.InputMediaVideo)
+	assert.True(ok)
+
+	document, ok = configs[2].Media[0].(tgbotapi.InputMediaDocument)
+	require.True(ok)
+	assert.Empty(document.Caption)
+
+	assert.Equal([]int{0, 1, 2, 3}, builder.SentOrder())
+}
+
+func TestPrepareMediaGroupRequest(t *testing.T) {
+	photo := tgbotapi.NewInputMediaPhoto(tgbotapi.FileBytes{Name: "photo.jpg", Bytes: []byte("photo")})
+	video := tgbotapi.NewInputMediaVideo(tgbotapi.FileID("video-file-id"))
+	document := tgbotapi.NewInputMediaDocument(tgbotapi.FileBytes{Name: "original.png", Bytes: []byte("original")})
+	document.Thumb = tgbotapi.FileBytes{Name: "thumbnail.jpg", Bytes: []byte("thumbnail")}
+
+	params, files, err := prepareMediaGroupRequest(tgbotapi.MediaGroupConfig{
+		ChatID:           1234,
+		Media:            []interface{}{photo, video, document},
+		ReplyToMessageID: 42,
+	}, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, "1234", params["chat_id"])
+	assert.Equal(t, "42", params["reply_to_message_id"])
+
+	fileNames := lo.Map(files, func(file tgbotapi.RequestFile, _ int) string { return file.Name })
+	assert.Equal(t, []string{"file-0", "file-2", "file-2-thumb"}, fileNames)
+
+	var media []map[string]interface{}
+
+	err = json.Unmarshal([]byte(params["media"]), &media)
+	require.NoError(t, err)
+	require.Len(t, media, 3)
+
+	assert.Equal(t, "attach://file-0", media[0]["media"])
+	assert.Equal(t, true, media[0]["has_spoiler"])
+	assert.Equal(t, "video-file-id", media[1]["media"])
+	assert.Equal(t, true, media[1]["has_spoiler"])
+	assert.Equal(t, "attach://file-2", media[2]["media"])
+	assert.Equal(t, "attach://file-2-thumb", media[2]["thumb"])
+	assert.NotContains(t, media[2], "has_spoiler")
+}
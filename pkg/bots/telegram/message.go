@@ -0,0 +1,90 @@
+package telegram
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	MediaTypePhoto     = "photo"
+	MediaTypeVideo     = "video"
+	MediaTypeAnimation = "animation"
+	MediaTypeDocument  = "document"
+	MediaTypeAudio     = "audio"
+)
+
+const (
+	// MaxCaptionLength 媒体说明文字的最大长度，以 UTF-16 码元计算
+	MaxCaptionLength = 1024
+)
+
+// MessageMedia 消息中所包含的媒体
+type MessageMedia struct {
+	Type         string
+	FileID       string
+	FileUniqueID string
+}
+
+// MessageMediaOf 返回消息中所包含的媒体，照片会返回尺寸最大的版本
+func MessageMediaOf(message tgbotapi.Message) (MessageMedia, bool) {
+	switch {
+	case len(message.Photo) > 0:
+		photo := message.Photo[len(message.Photo)-1]
+		return MessageMedia{Type: MediaTypePhoto, FileID: photo.FileID, FileUniqueID: photo.FileUniqueID}, true
+	case message.Video != nil:
+		return MessageMedia{Type: MediaTypeVideo, FileID: message.Video.FileID, FileUniqueID: message.Video.FileUniqueID}, true
+	case message.Animation != nil:
+		return MessageMedia{Type: MediaTypeAnimation, FileID: message.Animation.FileID, FileUniqueID: message.Animation.FileUniqueID}, true
+	case message.Document != nil:
+		return MessageMedia{Type: MediaTypeDocument, FileID: message.Document.FileID, FileUniqueID: message.Document.FileUniqueID}, true
+	case message.Audio != nil:
+		return MessageMedia{Type: MediaTypeAudio, FileID: message.Audio.FileID, FileUniqueID: message.Audio.FileUniqueID}, true
+	default:
+		return MessageMedia{}, false
+	}
+}
+
+// MessageLink 返回消息的 t.me 链接，公开的频道和群组使用用户名，私有的频道和超级群组使用 /c/ 链接
+func MessageLink(chat *tgbotapi.Chat, messageID int) string {
+	if chat == nil {
+		return ""
+	}
+	if chat.UserName != "" {
+		return fmt.Sprintf("https://t.me/%s/%d", chat.UserName, messageID)
+	}
+
+	// 私有频道和超级群组的 ID 以 -100 开头，/c/ 链接中需要去掉这个前缀
+	chatID := strconv.FormatInt(chat.ID, 10)
+	if !strings.HasPrefix(chatID, "-100") {
+		return ""
+	}
+
+	return fmt.Sprintf("https://t.me/c/%s/%d", strings.TrimPrefix(chatID, "-100"), messageID)
+}
+
+// TruncateText 将纯文本截断到不超过 maxLength 个 UTF-16 码元，被截断时以省略号结尾
+func TruncateText(text string, maxLength int) string {
+	if len(utf16.Encode([]rune(text))) <= maxLength {
+		return text
+	}
+
+	length := 0
+	for i, r := range text {
+		runeLength := 1
+		if r > 0xFFFF {
+			runeLength = 2
+		}
+		// 为省略号预留一个码元
+		if length+runeLength > maxLength-1 {
+			return text[:i] + "…"
+		}
+
+		length += runeLength
+	}
+
+	return text
+}
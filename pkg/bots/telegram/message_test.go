@@ -0,0 +1,23 @@
+package telegram
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageLink(t *testing.T) {
+	assert.Equal(t, "https://t.me/perobot/42", MessageLink(&tgbotapi.Chat{ID: -1001234567890, UserName: "perobot"}, 42))
+	assert.Equal(t, "https://t.me/c/1234567890/42", MessageLink(&tgbotapi.Chat{ID: -1001234567890}, 42))
+	assert.Equal(t, "", MessageLink(&tgbotapi.Chat{ID: 1234}, 42))
+	assert.Equal(t, "", MessageLink(nil, 42))
+}
+
+func TestTruncateText(t *testing.T) {
+	assert.Equal(t, "猫猫", TruncateText("猫猫", 2))
+	assert.Equal(t, "猫…", TruncateText("猫猫猫", 2))
+	assert.Equal(t, "ab…", TruncateText("ab😺cd", 4))
+	assert.Equal(t, "ab😺…", TruncateText("ab😺cd", 5))
+	assert.Equal(t, "", TruncateText("", 10))
+}
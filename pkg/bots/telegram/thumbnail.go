@@ -0,0 +1,38 @@
+package telegram
+
+import (
+	"bytes"
+
+	"github.com/nekomeowww/imaging"
+)
+
+const (
+	// MaxThumbnailDimension 缩略图宽高的最大值
+	//
+	// https://core.telegram.org/bots/api#inputmediadocument
+	MaxThumbnailDimension = 320
+)
+
+// Thumbnail 生成满足 Telegram 对缩略图限制的 JPEG 缩略图
+//
+// 图片会被等比缩放到宽度为 MaxThumbnailDimension，缩放后高度超出时居中裁剪为正方形。
+func Thumbnail(data []byte) ([]byte, error) {
+	img, err := imaging.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnail := imaging.Resize(img, MaxThumbnailDimension, 0, imaging.Lanczos)
+	if thumbnail.Rect.Dy() > MaxThumbnailDimension {
+		thumbnail = imaging.CropCenter(thumbnail, MaxThumbnailDimension, MaxThumbnailDimension)
+	}
+
+	buffer := new(bytes.Buffer)
+
+	err = imaging.Encode(buffer, thumbnail, imaging.JPEG)
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,26 @@
+package telegram
+
+import (
+	"bytes"
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThumbnail(t *testing.T) {
+	for _, size := range []image.Point{{X: 1280, Y: 720}, {X: 720, Y: 1280}, {X: 100, Y: 100}} {
+		thumbnail, err := Thumbnail(newTestPNG(t, size.X, size.Y))
+		require.NoError(t, err)
+
+		config, format, err := image.DecodeConfig(bytes.NewReader(thumbnail))
+		require.NoError(t, err)
+		assert.Equal(t, "jpeg", format)
+		assert.LessOrEqual(t, config.Width, MaxThumbnailDimension)
+		assert.LessOrEqual(t, config.Height, MaxThumbnailDimension)
+	}
+
+	_, err := Thumbnail([]byte("not an image"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,221 @@
+package telegram
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/nekomeowww/perobot/pkg/options"
+	"github.com/nekomeowww/perobot/pkg/spool"
+)
+
+// LocalBotAPISizePolicy 使用自建 Bot API 服务器（--local 模式）时的限制
+//
+// https://github.com/tdlib/telegram-bot-api#usage
+var LocalBotAPISizePolicy = SizePolicy{
+	MaxPhotoSize:         10 * MB,
+	MaxPhotoDimensionSum: 10000,
+	MaxPhotoAspectRatio:  20,
+	MaxUploadSize:        2000 * MB,
+}
+
+// BotAPIEndpoint 将自建 Bot API 服务器的地址转换为 tgbotapi 所使用的 endpoint 格式，
+// 例如 http://localhost:8081 会被转换为 http://localhost:8081/bot%s/%s
+func BotAPIEndpoint(serverURL string) string {
+	if serverURL == "" {
+		return tgbotapi.APIEndpoint
+	}
+	if strings.Contains(serverURL, "%s") {
+		return serverURL
+	}
+
+	return strings.TrimSuffix(serverURL, "/") + "/bot%s/%s"
+}
+
+type FileUploaderOptions struct {
+	SizePolicy     SizePolicy
+	LocalDir       string
+	LocalServerDir string
+}
+
+// WithSizePolicy 设定上传时所遵循的大小限制
+func WithSizePolicy(sizePolicy SizePolicy) options.CallOptions[FileUploaderOptions] {
+	return options.NewCallOptions(func(o *FileUploaderOptions) {
+		o.SizePolicy = sizePolicy
+	})
+}
+
+// WithLocalFiles 设定与自建 Bot API 服务器共享的目录，dir 为机器人所见的路径，
+// serverDir 为 Bot API 服务器所见的路径，为空时与 dir 相同
+func WithLocalFiles(dir string, serverDir string) options.CallOptions[FileUploaderOptions] {
+	return options.NewCallOptions(func(o *FileUploaderOptions) {
+		o.LocalDir = dir
+		o.LocalServerDir = serverDir
+	})
+}
+
+// FileUploader 决定文件以何种方式上传到 Telegram
+type FileUploader struct {
+	sizePolicy     SizePolicy
+	localDir       string
+	localServerDir string
+}
+
+func NewFileUploader(callOpts ...options.CallOptions[FileUploaderOptions]) *FileUploader {
+	opts := options.ApplyCallOptions(callOpts, FileUploaderOptions{
+		SizePolicy: DefaultSizePolicy,
+	})
+
+	uploader := &FileUploader{
+		sizePolicy:     opts.SizePolicy,
+		localDir:       opts.LocalDir,
+		localServerDir: opts.LocalServerDir,
+	}
+	if uploader.localServerDir == "" {
+		uploader.localServerDir = uploader.localDir
+	}
+
+	return uploader
+}
+
+// SizePolicy 返回上传时所遵循的大小限制
+func (u *FileUploader) SizePolicy() SizePolicy {
+	return u.sizePolicy
+}
+
+// LocalFilesEnabled 是否配置了与自建 Bot API 服务器共享的目录
+func (u *FileUploader) LocalFilesEnabled() bool {
+	return u.localDir != ""
+}
+
+// NewBatch 创建一批一起发送的文件，发送完成后需要调用 FileBatch.Cleanup 清理
+func (u *FileUploader) NewBatch() *FileBatch {
+	return &FileBatch{
+		uploader:   u,
+		localPaths: make([]string, 0),
+		readers:    make([]io.Closer, 0),
+	}
+}
+
+// FileBatch 一批一起发送的文件
+type FileBatch struct {
+	uploader *FileUploader
+
+	mutex      sync.Mutex
+	localPaths []string
+	readers    []io.Closer
+}
+
+// File 返回上传 data 所使用的 tgbotapi.RequestFileData
+//
+// 配置了共享目录时，超出官方 Bot API 上传限制的文件会被写入共享目录，
+// 并以 file:// 的形式交给自建 Bot API 服务器直接读取。
+func (b *FileBatch) File(name string, data []byte) (tgbotapi.RequestFileData, error) {
+	if !b.uploader.LocalFilesEnabled() || DefaultSizePolicy.FitsUpload(int64(len(data))) {
+		return tgbotapi.FileBytes{Name: name, Bytes: data}, nil
+	}
+
+	return b.localFile(name, func(localPath string) error {
+		return os.WriteFile(localPath, data, 0644)
+	})
+}
+
+// Spooled 返回上传暂存的 file 所使用的 tgbotapi.RequestFileData
+//
+// 保存在临时文件中的数据会在发送时以流的形式读取并上传，不会整个读入内存；
+// 配置了共享目录时，超出官方 Bot API 上传限制的文件会被链接或复制到共享目录。
+func (b *FileBatch) Spooled(name string, file *spool.File) (tgbotapi.RequestFileData, error) {
+	if file.Path() == "" {
+		data, err := file.Bytes()
+		if err != nil {
+			return nil, err
+		}
+
+		return b.File(name, data)
+	}
+	if b.uploader.LocalFilesEnabled() && !DefaultSizePolicy.FitsUpload(file.Size()) {
+		return b.localFile(name, func(localPath string) error {
+			return linkOrCopy(file, localPath)
+		})
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	b.mutex.Lock()
+	b.readers = append(b.readers, reader)
+	b.mutex.Unlock()
+
+	return tgbotapi.FileReader{Name: name, Reader: reader}, nil
+}
+
+// localFile 调用 write 将文件写入共享目录，并返回自建 Bot API 服务器读取该文件所使用的 file:// 地址
+func (b *FileBatch) localFile(name string, write func(localPath string) error) (tgbotapi.RequestFileData, error) {
+	localName := fmt.Sprintf("%d-%s", time.Now().UnixNano(), filepath.Base(name))
+	localPath := filepath.Join(b.uploader.localDir, localName)
+
+	err := write(localPath)
+	if err != nil {
+		_ = os.Remove(localPath)
+		return nil, fmt.Errorf("failed to write %s to local files directory: %w", name, err)
+	}
+
+	b.mutex.Lock()
+	b.localPaths = append(b.localPaths, localPath)
+	b.mutex.Unlock()
+
+	return tgbotapi.FileURL("file://" + filepath.Join(b.uploader.localServerDir, localName)), nil
+}
+
+// linkOrCopy 将暂存的临时文件硬链接到 localPath，不在同一个文件系统中时复制
+func linkOrCopy(file *spool.File, localPath string) error {
+	err := os.Link(file.Path(), localPath)
+	if err == nil {
+		return os.Chmod(localPath, 0644)
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	localFile, err := os.OpenFile(localPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(localFile, reader)
+	if err != nil {
+		_ = localFile.Close()
+		return err
+	}
+
+	return localFile.Close()
+}
+
+// Cleanup 删除这批文件写入共享目录的所有文件，并关闭以流的形式上传的临时文件
+func (b *FileBatch) Cleanup() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, reader := range b.readers {
+		_ = reader.Close()
+	}
+
+	b.readers = b.readers[:0]
+
+	for _, localPath := range b.localPaths {
+		_ = os.Remove(localPath)
+	}
+
+	b.localPaths = b.localPaths[:0]
+}
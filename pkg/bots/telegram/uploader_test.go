@@ -0,0 +1,120 @@
+package telegram
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nekomeowww/perobot/pkg/spool"
+)
+
+func TestBotAPIEndpoint(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(tgbotapi.APIEndpoint, BotAPIEndpoint(""))
+	assert.Equal("http://localhost:8081/bot%s/%s", BotAPIEndpoint("http://localhost:8081"))
+	assert.Equal("http://localhost:8081/bot%s/%s", BotAPIEndpoint("http://localhost:8081/"))
+	assert.Equal("http://localhost:8081/custom/bot%s/%s", BotAPIEndpoint("http://localhost:8081/custom/bot%s/%s"))
+}
+
+func TestFileBatchFile(t *testing.T) {
+	dir := t.TempDir()
+	uploader := NewFileUploader(
+		WithSizePolicy(LocalBotAPISizePolicy),
+		WithLocalFiles(dir, "/var/lib/telegram-bot-api/shared"),
+	)
+
+	files := uploader.NewBatch()
+
+	small, err := files.File("small.jpg", []byte("small"))
+	require.NoError(t, err)
+	assert.IsType(t, tgbotapi.FileBytes{}, small)
+
+	large, err := files.File("large.mp4", make([]byte, DefaultSizePolicy.MaxUploadSize+1))
+	require.NoError(t, err)
+	require.IsType(t, tgbotapi.FileURL(""), large)
+	assert.False(t, large.NeedsUpload())
+	assert.True(t, strings.HasPrefix(large.SendData(), "file:///var/lib/telegram-bot-api/shared/"))
+	assert.True(t, strings.HasSuffix(large.SendData(), "-large.mp4"))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, filepath.Base(large.SendData()), entries[0].Name())
+
+	files.Cleanup()
+
+	entries, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestFileBatchSpooled(t *testing.T) {
+	store, err := spool.Open(spool.WithDir(t.TempDir()), spool.WithThreshold(16))
+	require.NoError(t, err)
+
+	small, err := store.Download(func(w io.Writer) error {
+		_, err := w.Write([]byte("small"))
+		return err
+	})
+	require.NoError(t, err)
+
+	large, err := store.Download(func(w io.Writer) error {
+		_, err := w.Write(bytes.Repeat([]byte("large"), 10))
+		return err
+	})
+	require.NoError(t, err)
+
+	t.Run("Remote", func(t *testing.T) {
+		files := NewFileUploader().NewBatch()
+		defer files.Cleanup()
+
+		file, err := files.Spooled("small.jpg", small)
+		require.NoError(t, err)
+		assert.IsType(t, tgbotapi.FileBytes{}, file)
+
+		file, err = files.Spooled("large.mp4", large)
+		require.NoError(t, err)
+		require.IsType(t, tgbotapi.FileReader{}, file)
+		assert.Equal(t, "large.mp4", file.(tgbotapi.FileReader).Name)
+
+		data, err := io.ReadAll(file.(tgbotapi.FileReader).Reader)
+		require.NoError(t, err)
+		assert.Equal(t, bytes.Repeat([]byte("large"), 10), data)
+	})
+
+	t.Run("LocalFiles", func(t *testing.T) {
+		dir := t.TempDir()
+		uploader := NewFileUploader(
+			WithSizePolicy(LocalBotAPISizePolicy),
+			WithLocalFiles(dir, "/var/lib/telegram-bot-api/shared"),
+		)
+
+		// 未超出官方 Bot API 上传限制的文件不需要写入共享目录
+		files := uploader.NewBatch()
+
+		file, err := files.Spooled("large.mp4", large)
+		require.NoError(t, err)
+		assert.IsType(t, tgbotapi.FileReader{}, file)
+
+		files.Cleanup()
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+
+		err = linkOrCopy(large, filepath.Join(dir, "large.mp4"))
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(filepath.Join(dir, "large.mp4"))
+		require.NoError(t, err)
+		assert.Equal(t, bytes.Repeat([]byte("large"), 10), data)
+	})
+}
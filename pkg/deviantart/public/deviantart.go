@@ -0,0 +1,104 @@
+package deviantart_public
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/imroc/req/v3"
+	"github.com/sirupsen/logrus"
+
+	deviantart_public_types "github.com/nekomeowww/perobot/pkg/deviantart/public/types"
+	"github.com/nekomeowww/perobot/pkg/options"
+)
+
+const (
+	// DefaultOEmbedURL DeviantArt 的 oEmbed 接口
+	DefaultOEmbedURL = "https://backend.deviantart.com/oembed"
+)
+
+type ClientOptions struct {
+	Logger    *logrus.Entry
+	OEmbedURL string
+}
+
+func WithLogger(logger *logrus.Entry) options.CallOptions[ClientOptions] {
+	return options.NewCallOptions(func(o *ClientOptions) {
+		o.Logger = logger
+	})
+}
+
+// WithOEmbedURL 设定 oEmbed 接口的地址
+func WithOEmbedURL(oEmbedURL string) options.CallOptions[ClientOptions] {
+	return options.NewCallOptions(func(o *ClientOptions) {
+		o.OEmbedURL = oEmbedURL
+	})
+}
+
+// Client 通过 oEmbed 接口获取 DeviantArt 作品，无需登录和 API 凭据
+type Client struct {
+	reqClient *req.Client
+	logger    *logrus.Entry
+	oEmbedURL string
+}
+
+func NewClient(callOpts ...options.CallOptions[ClientOptions]) (*Client, error) {
+	opts := options.ApplyCallOptions(callOpts, ClientOptions{
+		Logger:    logrus.NewEntry(logrus.New()),
+		OEmbedURL: DefaultOEmbedURL,
+	})
+
+	c := req.
+		C().
+		SetUserAgent("Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/109.0.0.0 Safari/537.36 Edg/109.0.1518.52")
+
+	client := &Client{
+		reqClient: c,
+		logger:    opts.Logger,
+		oEmbedURL: strings.TrimSuffix(opts.OEmbedURL, "/"),
+	}
+
+	return client, nil
+}
+
+// GetOEmbed 返回作品的 oEmbed 信息，作品不存在或已被删除时返回 nil
+func (c *Client) GetOEmbed(deviationURL string) (*deviantart_public_types.OEmbed, error) {
+	var oEmbed deviantart_public_types.OEmbed
+
+	resp, err := c.reqClient.R().
+		SetQueryParam("url", deviationURL).
+		SetQueryParam("format", "json").
+		SetSuccessResult(&oEmbed).
+		Get(c.oEmbedURL)
+	if err != nil {
+		c.logger.Errorf("failed to get deviantart oembed, err: %v", err)
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to get deviantart oembed, status code: %d", resp.StatusCode)
+		return nil, fmt.Errorf("request to %s failed: status code: %d", resp.Request.URL, resp.StatusCode)
+	}
+
+	return &oEmbed, nil
+}
+
+// Download 下载 DeviantArt CDN 上的图片，并以流的形式写入 w
+func (c *Client) Download(link string, w io.Writer) error {
+	resp, err := c.reqClient.R().
+		SetOutput(w).
+		Get(link)
+	if err != nil {
+		c.logger.Errorf("failed to fetch deviantart image, err: %v", err)
+		return err
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to fetch deviantart image, status code: %d", resp.StatusCode)
+		return fmt.Errorf("failed to fetch deviantart image, status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package deviantart_public
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOEmbed(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oembed", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "json", r.URL.Query().Get("format"))
+
+		switch r.URL.Query().Get("url") {
+		case "https://www.deviantart.com/alice/art/Castle-123456789":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			_, _ = w.Write([]byte(`{
+				"version": "1.0",
+				"type": "photo",
+				"title": "Castle",
+				"url": "https://images-wixmp-0.wixmp.com/f/uuid/castle.png/v1/fill/w_1024,h_1280,q_80,strp/castle_by_alice.jpg?token=abc",
+				"author_name": "alice",
+				"author_url": "https://www.deviantart.com/alice",
+				"safety": "nonadult",
+				"tags": "fantasy, castle, , digitalart",
+				"width": "1024",
+				"height": 1280,
+				"imagetype": "png"
+			}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient(WithOEmbedURL(server.URL + "/oembed"))
+	require.NoError(t, err)
+
+	oEmbed, err := client.GetOEmbed("https://www.deviantart.com/alice/art/Castle-123456789")
+	require.NoError(t, err)
+	require.NotNil(t, oEmbed)
+	assert.True(t, oEmbed.IsPhoto())
+	assert.False(t, oEmbed.IsAdult())
+	assert.Equal(t, []string{"fantasy", "castle", "digitalart"}, oEmbed.TagList())
+	assert.Equal(t, "https://images-wixmp-0.wixmp.com/f/uuid/castle.png?token=abc", oEmbed.FullSizeURL())
+
+	width, height := oEmbed.Size()
+	assert.Equal(t, 1024, width)
+	assert.Equal(t, 1280, height)
+
+	oEmbed, err = client.GetOEmbed("https://www.deviantart.com/alice/art/Deleted-1")
+	require.NoError(t, err)
+	assert.Nil(t, oEmbed)
+}
@@ -0,0 +1,84 @@
+package deviantart_public_types
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+const (
+	OEmbedTypePhoto = "photo"
+
+	SafetyAdult = "adult"
+)
+
+// OEmbed DeviantArt 作品的 oEmbed 信息
+//
+// https://www.deviantart.com/developers/oembed
+type OEmbed struct {
+	// Type 图片作品为 photo，文学等其他作品为 rich 或 link
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	// URL 作品的 fullview 图片，宽高受站点限制，可能经过缩小和压缩
+	URL        string `json:"url"`
+	AuthorName string `json:"author_name"`
+	AuthorURL  string `json:"author_url"`
+	// Safety 成人内容为 adult，其他为 nonadult
+	Safety string `json:"safety"`
+	// Tags 以 ", " 分隔的标签
+	Tags         string      `json:"tags"`
+	Width        json.Number `json:"width"`
+	Height       json.Number `json:"height"`
+	ThumbnailURL string      `json:"thumbnail_url"`
+	ImageType    string      `json:"imagetype"`
+}
+
+// IsPhoto 作品是否为图片
+func (o *OEmbed) IsPhoto() bool {
+	return o.Type == OEmbedTypePhoto && o.URL != ""
+}
+
+// IsAdult 作品是否为成人内容
+func (o *OEmbed) IsAdult() bool {
+	return o.Safety == SafetyAdult
+}
+
+// TagList 返回作品的标签
+func (o *OEmbed) TagList() []string {
+	tags := make([]string, 0)
+	for _, tag := range strings.Split(o.Tags, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
+// Size 返回作品的宽高，未知时为 0
+func (o *OEmbed) Size() (int, int) {
+	width, _ := o.Width.Int64()
+	height, _ := o.Height.Int64()
+
+	return int(width), int(height)
+}
+
+// FullSizeURL 返回去掉缩放参数的图片链接，即 https://<host>/f/<uuid>/<file>/v1/fill/<参数>/<name>?token=<token>
+// 中 /v1/ 之后的路径，作品不允许下载原图时链接会无法访问
+func (o *OEmbed) FullSizeURL() string {
+	link, query, _ := strings.Cut(o.URL, "?")
+
+	index := strings.Index(link, "/v1/")
+	if index < 0 {
+		return o.URL
+	}
+
+	link = link[:index]
+	if query != "" {
+		link += "?" + query
+	}
+
+	return link
+}
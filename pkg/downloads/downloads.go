@@ -0,0 +1,263 @@
+package downloads
+
+import (
+	"io"
+	"net/url"
+	"sync"
+
+	"github.com/samber/lo"
+
+	"github.com/nekomeowww/perobot/pkg/options"
+)
+
+const (
+	// DefaultConcurrency 默认同时进行的下载数量
+	DefaultConcurrency = 8
+	// DefaultHostConcurrency 默认同一个域名同时进行的下载数量
+	DefaultHostConcurrency = 4
+	// DefaultBudget 默认所有尚未释放的下载已写入的字节数上限
+	DefaultBudget int64 = 256 * 1024 * 1024
+)
+
+var (
+	// DefaultHostsConcurrency 图片和视频所在域名的默认并发数量，视频较大，限制更严格
+	DefaultHostsConcurrency = map[string]int{
+		"pbs.twimg.com":   4,
+		"video.twimg.com": 2,
+		"i.pximg.net":     4,
+	}
+)
+
+type ManagerOptions struct {
+	Concurrency      int
+	HostConcurrency  int
+	HostsConcurrency map[string]int
+	Budget           int64
+}
+
+// WithConcurrency 设定同时进行的下载数量
+func WithConcurrency(concurrency int) options.CallOptions[ManagerOptions] {
+	return options.NewCallOptions(func(o *ManagerOptions) {
+		o.Concurrency = concurrency
+	})
+}
+
+// WithHostConcurrency 设定没有单独设定的域名同时进行的下载数量
+func WithHostConcurrency(concurrency int) options.CallOptions[ManagerOptions] {
+	return options.NewCallOptions(func(o *ManagerOptions) {
+		o.HostConcurrency = concurrency
+	})
+}
+
+// WithHostsConcurrency 单独设定域名同时进行的下载数量
+func WithHostsConcurrency(hostsConcurrency map[string]int) options.CallOptions[ManagerOptions] {
+	return options.NewCallOptions(func(o *ManagerOptions) {
+		o.HostsConcurrency = hostsConcurrency
+	})
+}
+
+// WithBudget 设定所有尚未释放的下载已写入的字节数上限
+func WithBudget(budget int64) options.CallOptions[ManagerOptions] {
+	return options.NewCallOptions(func(o *ManagerOptions) {
+		o.Budget = budget
+	})
+}
+
+// Manager 在所有处理器之间共享的下载管理器，限制全局和每个域名的并发数量，
+// 以及下载已写入、尚未释放的字节数：下载完成后数据仍在等待发送，直到调用 Download 返回的 release 才扣除。
+// 超过上限时，新的下载不会开始，进行中的下载的写入也会阻塞，直到有数据释放或下载完成；
+// 最早开始的下载始终可以写入，没有进行中的下载时新的下载始终可以开始，保证下载之间不会互相等待
+type Manager struct {
+	global chan struct{}
+
+	hostsMutex       sync.Mutex
+	hosts            map[string]chan struct{}
+	hostConcurrency  int
+	hostsConcurrency map[string]int
+
+	budgetMutex sync.Mutex
+	budgetCond  *sync.Cond
+	budget      int64
+	held        int64
+	// running 按照开始时间排列的进行中的下载
+	running []*countingWriter
+}
+
+func New(callOpts ...options.CallOptions[ManagerOptions]) *Manager {
+	opts := options.ApplyCallOptions(callOpts, ManagerOptions{
+		Concurrency:      DefaultConcurrency,
+		HostConcurrency:  DefaultHostConcurrency,
+		HostsConcurrency: DefaultHostsConcurrency,
+		Budget:           DefaultBudget,
+	})
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+	if opts.HostConcurrency <= 0 {
+		opts.HostConcurrency = DefaultHostConcurrency
+	}
+	if opts.Budget <= 0 {
+		opts.Budget = DefaultBudget
+	}
+
+	m := &Manager{
+		global:           make(chan struct{}, opts.Concurrency),
+		hosts:            make(map[string]chan struct{}),
+		hostConcurrency:  opts.HostConcurrency,
+		hostsConcurrency: opts.HostsConcurrency,
+		budget:           opts.Budget,
+	}
+	m.budgetCond = sync.NewCond(&m.budgetMutex)
+
+	return m
+}
+
+// Download 在获得下载名额后调用 fetch 将 link 的内容写入 w，
+// 写入 w 的字节数在写入之前计入已写入的字节数，fetch 成功时直到调用返回的 release 才扣除，
+// release 应在写入的数据不再使用时调用，可以重复调用；fetch 返回错误时立即扣除
+func (m *Manager) Download(link string, w io.Writer, fetch func(w io.Writer) error) (release func(), err error) {
+	releaseSlots := m.acquire(hostOf(link))
+	defer releaseSlots()
+
+	writer := m.start(w)
+
+	err = fetch(writer)
+	m.finish(writer)
+	if err != nil {
+		m.release(writer)
+		return nil, err
+	}
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			m.release(writer)
+		})
+	}, nil
+}
+
+// Held 返回下载已写入、尚未释放的字节数
+func (m *Manager) Held() int64 {
+	m.budgetMutex.Lock()
+	defer m.budgetMutex.Unlock()
+
+	return m.held
+}
+
+// acquire 依次获取域名和全局的名额，先获取域名的名额，避免等待同一个域名的下载占用全局名额
+func (m *Manager) acquire(host string) func() {
+	hostSlots := m.hostSlots(host)
+	hostSlots <- struct{}{}
+	m.global <- struct{}{}
+
+	return func() {
+		<-m.global
+		<-hostSlots
+	}
+}
+
+func (m *Manager) hostSlots(host string) chan struct{} {
+	m.hostsMutex.Lock()
+	defer m.hostsMutex.Unlock()
+
+	slots, ok := m.hosts[host]
+	if ok {
+		return slots
+	}
+
+	concurrency, ok := m.hostsConcurrency[host]
+	if !ok || concurrency <= 0 {
+		concurrency = m.hostConcurrency
+	}
+
+	slots = make(chan struct{}, concurrency)
+	m.hosts[host] = slots
+
+	return slots
+}
+
+// start 在字节数低于上限或没有进行中的下载时记录开始的下载
+func (m *Manager) start(w io.Writer) *countingWriter {
+	m.budgetMutex.Lock()
+	defer m.budgetMutex.Unlock()
+
+	for m.held >= m.budget && len(m.running) > 0 {
+		m.budgetCond.Wait()
+	}
+
+	writer := &countingWriter{manager: m, writer: w}
+	m.running = append(m.running, writer)
+
+	return writer
+}
+
+// finish 将下载移出进行中的下载，唤醒等待的下载和写入
+func (m *Manager) finish(writer *countingWriter) {
+	m.budgetMutex.Lock()
+	defer m.budgetMutex.Unlock()
+
+	m.running = lo.Without(m.running, writer)
+	m.budgetCond.Broadcast()
+}
+
+// release 扣除下载已写入的字节数，唤醒等待的下载和写入
+func (m *Manager) release(writer *countingWriter) {
+	m.budgetMutex.Lock()
+	defer m.budgetMutex.Unlock()
+
+	m.held -= writer.written
+	writer.written = 0
+	m.budgetCond.Broadcast()
+}
+
+// reserve 在写入 n 个字节之前计入已写入的字节数，超过上限时等待，最早开始的下载不需要等待
+func (m *Manager) reserve(writer *countingWriter, n int64) {
+	m.budgetMutex.Lock()
+	defer m.budgetMutex.Unlock()
+
+	for m.held+n > m.budget && m.running[0] != writer {
+		m.budgetCond.Wait()
+	}
+
+	m.held += n
+	writer.written += n
+}
+
+// refund 扣除预留但没有写入的字节数
+func (m *Manager) refund(writer *countingWriter, n int64) {
+	m.budgetMutex.Lock()
+	defer m.budgetMutex.Unlock()
+
+	m.held -= n
+	writer.written -= n
+	m.budgetCond.Broadcast()
+}
+
+func hostOf(link string) string {
+	parsedURL, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	return parsedURL.Hostname()
+}
+
+// countingWriter 在写入之前预留字节数，释放时从已写入的字节数中扣除
+type countingWriter struct {
+	manager *Manager
+	writer  io.Writer
+	// written 已计入已写入的字节数，由 budgetMutex 保护
+	written int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.manager.reserve(w, int64(len(p)))
+
+	n, err := w.writer.Write(p)
+	if n < len(p) {
+		w.manager.refund(w, int64(len(p)-n))
+	}
+
+	return n, err
+}
@@ -0,0 +1,246 @@
+package downloads
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// trackConcurrency 返回记录同时运行数量最大值的 fetch
+func trackConcurrency(running *int32, maxRunning *int32) func(w io.Writer) error {
+	return func(w io.Writer) error {
+		current := atomic.AddInt32(running, 1)
+		defer atomic.AddInt32(running, -1)
+
+		for {
+			max := atomic.LoadInt32(maxRunning)
+			if current <= max || atomic.CompareAndSwapInt32(maxRunning, max, current) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		_, err := w.Write([]byte("data"))
+
+		return err
+	}
+}
+
+func TestManagerDownload(t *testing.T) {
+	t.Run("Write", func(t *testing.T) {
+		manager := New()
+
+		buffer := new(bytes.Buffer)
+		release, err := manager.Download("https://i.pximg.net/img-original/1.png", buffer, func(w io.Writer) error {
+			_, err := w.Write([]byte("image"))
+			return err
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "image", buffer.String())
+		assert.Equal(t, int64(5), manager.Held())
+
+		release()
+		release()
+		assert.Zero(t, manager.Held())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		manager := New()
+		expectedErr := errors.New("failed")
+
+		_, err := manager.Download("https://pbs.twimg.com/media/1.jpg", io.Discard, func(w io.Writer) error {
+			_, _ = w.Write([]byte("partial"))
+			return expectedErr
+		})
+		assert.ErrorIs(t, err, expectedErr)
+		assert.Zero(t, manager.Held())
+	})
+
+	t.Run("GlobalConcurrency", func(t *testing.T) {
+		manager := New(WithConcurrency(2), WithHostConcurrency(10))
+
+		var running, maxRunning int32
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = manager.Download("https://example.com/1.jpg", io.Discard, trackConcurrency(&running, &maxRunning))
+			}()
+		}
+
+		wg.Wait()
+		assert.Equal(t, int32(2), maxRunning)
+	})
+
+	t.Run("HostConcurrency", func(t *testing.T) {
+		manager := New(
+			WithConcurrency(10),
+			WithHostsConcurrency(map[string]int{"video.twimg.com": 1}),
+		)
+
+		var running, maxRunning int32
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = manager.Download("https://video.twimg.com/1.mp4", io.Discard, trackConcurrency(&running, &maxRunning))
+			}()
+		}
+
+		wg.Wait()
+		assert.Equal(t, int32(1), maxRunning)
+	})
+
+	t.Run("Budget", func(t *testing.T) {
+		manager := New(WithBudget(10))
+
+		started := make(chan struct{})
+		finish := make(chan struct{})
+		go func() {
+			_, _ = manager.Download("https://pbs.twimg.com/1.jpg", io.Discard, func(w io.Writer) error {
+				_, err := w.Write(bytes.Repeat([]byte("0"), 20))
+				close(started)
+				<-finish
+
+				return err
+			})
+		}()
+
+		<-started
+		assert.Equal(t, int64(20), manager.Held())
+
+		done := make(chan struct{})
+		go func() {
+			_, _ = manager.Download("https://i.pximg.net/1.jpg", io.Discard, func(w io.Writer) error {
+				close(done)
+				return nil
+			})
+		}()
+
+		select {
+		case <-done:
+			t.Fatal("download started while budget is exhausted")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(finish)
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("download did not start after budget was released")
+		}
+	})
+
+	t.Run("BudgetBlocksWriters", func(t *testing.T) {
+		manager := New(WithBudget(10))
+
+		started := make(chan struct{})
+		finish := make(chan struct{})
+		go func() {
+			release, _ := manager.Download("https://pbs.twimg.com/1.jpg", io.Discard, func(w io.Writer) error {
+				_, err := w.Write(bytes.Repeat([]byte("0"), 8))
+				close(started)
+				<-finish
+
+				// 最早开始的下载超过上限时仍然可以写入
+				_, err = w.Write(bytes.Repeat([]byte("0"), 8))
+
+				return err
+			})
+			release()
+		}()
+
+		<-started
+
+		written := make(chan struct{})
+		go func() {
+			release, _ := manager.Download("https://i.pximg.net/1.jpg", io.Discard, func(w io.Writer) error {
+				_, err := w.Write(bytes.Repeat([]byte("0"), 5))
+				close(written)
+
+				return err
+			})
+			release()
+		}()
+
+		select {
+		case <-written:
+			t.Fatal("write was not blocked while budget is exhausted")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(finish)
+
+		select {
+		case <-written:
+		case <-time.After(time.Second):
+			t.Fatal("write was not resumed after budget was released")
+		}
+
+		assert.Eventually(t, func() bool { return manager.Held() == 0 }, time.Second, 10*time.Millisecond)
+	})
+	t.Run("HeldUntilReleased", func(t *testing.T) {
+		manager := New(WithBudget(10))
+
+		// 下载完成后数据仍在等待发送，释放之前继续占用上限
+		release, err := manager.Download("https://pbs.twimg.com/1.jpg", io.Discard, func(w io.Writer) error {
+			_, err := w.Write(bytes.Repeat([]byte("0"), 20))
+			return err
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(20), manager.Held())
+
+		// 没有进行中的下载时新的下载仍然可以开始，避免等待自己持有的数据
+		started := make(chan struct{})
+		finish := make(chan struct{})
+		go func() {
+			_, _ = manager.Download("https://i.pximg.net/1.jpg", io.Discard, func(w io.Writer) error {
+				close(started)
+				<-finish
+
+				return nil
+			})
+		}()
+
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatal("download did not start while no download is running")
+		}
+
+		done := make(chan struct{})
+		go func() {
+			_, _ = manager.Download("https://i.pximg.net/2.jpg", io.Discard, func(w io.Writer) error {
+				close(done)
+				return nil
+			})
+		}()
+
+		select {
+		case <-done:
+			t.Fatal("download started while held data exceeds the budget")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		release()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("download did not start after held data was released")
+		}
+
+		close(finish)
+	})
+}
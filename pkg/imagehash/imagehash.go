@@ -0,0 +1,67 @@
+package imagehash
+
+import (
+	"image"
+	"image/color"
+	"math/bits"
+
+	"github.com/nekomeowww/imaging"
+)
+
+const (
+	// MaxDistance 两个哈希之间汉明距离的最大值
+	MaxDistance = 64
+	// MinLuminanceRange 缩小后的灰度图中最亮和最暗的像素至少相差多少时才认为图片有足够的细节，
+	// 约为 8 级 8 位亮度
+	MinLuminanceRange = 8 * 0x101
+)
+
+// DHash 计算图片的差异哈希（dHash）
+//
+// 图片会被缩小为 9x8 的灰度图，逐行比较相邻像素的亮度，左侧像素更亮时对应的位为 1。
+// 缩放、重新编码和轻微调色后的同一张图片，其哈希之间的汉明距离通常很小。
+//
+// https://www.hackerfactor.com/blog/index.php?/archives/529-Kind-of-Like-That.html
+func DHash(img image.Image) uint64 {
+	hash, _ := DHashWithDetail(img)
+	return hash
+}
+
+// DHashWithDetail 计算图片的差异哈希，并返回图片是否有足够的细节用于比较
+//
+// 纯色或接近纯色的图片缩小后相邻像素的亮度几乎相同，哈希接近 0 并且主要由噪点决定，
+// 这样的图片之间会被误判为相同，不应该用于查找重复的图片。
+func DHashWithDetail(img image.Image) (uint64, bool) {
+	small := imaging.Resize(imaging.Grayscale(img), 9, 8, imaging.Box)
+
+	var hash uint64
+	minLuminance, maxLuminance := uint32(0xFFFF), uint32(0)
+
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 9; x++ {
+			current := luminance(small.At(x, y))
+			minLuminance = min(minLuminance, current)
+			maxLuminance = max(maxLuminance, current)
+			if x == 8 {
+				continue
+			}
+
+			hash <<= 1
+			if current > luminance(small.At(x+1, y)) {
+				hash |= 1
+			}
+		}
+	}
+
+	return hash, maxLuminance-minLuminance >= MinLuminanceRange
+}
+
+// Distance 返回两个哈希之间的汉明距离，距离越小图片越相似
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+func luminance(c color.Color) uint32 {
+	r, g, b, _ := c.RGBA()
+	return (299*r + 587*g + 114*b) / 1000
+}
@@ -0,0 +1,72 @@
+package imagehash
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/nekomeowww/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+func newGradient(width, height int, reversed bool) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			value := uint8(x * 255 / width)
+			if reversed {
+				value = 255 - value
+			}
+			if y < height/2 {
+				value /= 2
+			}
+
+			img.Set(x, y, color.NRGBA{R: value, G: value, B: value, A: 255})
+		}
+	}
+
+	return img
+}
+
+func TestDHash(t *testing.T) {
+	original := newGradient(400, 300, false)
+
+	t.Run("Resized", func(t *testing.T) {
+		resized := imaging.Resize(original, 120, 90, imaging.Lanczos)
+		assert.LessOrEqual(t, Distance(DHash(original), DHash(resized)), 4)
+	})
+
+	t.Run("Different", func(t *testing.T) {
+		reversed := newGradient(400, 300, true)
+		assert.Greater(t, Distance(DHash(original), DHash(reversed)), 32)
+	})
+}
+
+func TestDistance(t *testing.T) {
+	assert.Equal(t, 0, Distance(0b1010, 0b1010))
+	assert.Equal(t, 2, Distance(0b1010, 0b0110))
+	assert.Equal(t, MaxDistance, Distance(0, ^uint64(0)))
+}
+
+func TestDHashWithDetail(t *testing.T) {
+	t.Run("Gradient", func(t *testing.T) {
+		// 从左到右逐渐变亮的图片的哈希为 0，但仍然有足够的细节
+		hash, hasDetail := DHashWithDetail(newGradient(400, 300, false))
+		assert.Zero(t, hash)
+		assert.True(t, hasDetail)
+	})
+
+	t.Run("Flat", func(t *testing.T) {
+		flat := image.NewNRGBA(image.Rect(0, 0, 400, 300))
+		for y := 0; y < 300; y++ {
+			for x := 0; x < 400; x++ {
+				// 接近纯白的图片，带有少量噪点
+				value := uint8(250 + (x*7+y*13)%4)
+				flat.Set(x, y, color.NRGBA{R: value, G: value, B: value, A: 255})
+			}
+		}
+
+		_, hasDetail := DHashWithDetail(flat)
+		assert.False(t, hasDetail)
+	})
+}
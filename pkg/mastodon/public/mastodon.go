@@ -0,0 +1,108 @@
+package mastodon_public
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/imroc/req/v3"
+	"github.com/sirupsen/logrus"
+
+	mastodon_public_types "github.com/nekomeowww/perobot/pkg/mastodon/public/types"
+	"github.com/nekomeowww/perobot/pkg/options"
+	"github.com/nekomeowww/perobot/pkg/publicnet"
+)
+
+type ClientOptions struct {
+	Logger *logrus.Entry
+	// AllowPrivateAddresses 允许连接内网、本机和链路本地地址，仅用于测试
+	AllowPrivateAddresses bool
+}
+
+func WithLogger(logger *logrus.Entry) options.CallOptions[ClientOptions] {
+	return options.NewCallOptions(func(o *ClientOptions) {
+		o.Logger = logger
+	})
+}
+
+// WithPrivateAddressesAllowed 允许连接内网、本机和链路本地地址，仅用于测试
+func WithPrivateAddressesAllowed() options.CallOptions[ClientOptions] {
+	return options.NewCallOptions(func(o *ClientOptions) {
+		o.AllowPrivateAddresses = true
+	})
+}
+
+// Client 访问任意 Mastodon 实例以及兼容 Mastodon API 的实例（例如 Pleroma、Akkoma）的公开接口
+type Client struct {
+	reqClient *req.Client
+	logger    *logrus.Entry
+}
+
+func NewClient(callOpts ...options.CallOptions[ClientOptions]) (*Client, error) {
+	opts := options.ApplyCallOptions(callOpts, ClientOptions{
+		Logger: logrus.NewEntry(logrus.New()),
+	})
+
+	c := req.
+		C().
+		SetCommonHeader("Accept", "application/json").
+		SetUserAgent("Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/109.0.0.0 Safari/537.36 Edg/109.0.1518.52")
+
+	// 实例的域名和媒体文件的链接都由第三方决定，连接时拒绝内网地址，
+	// 以免访问到本机的 Bot API 服务器等内部服务
+	if !opts.AllowPrivateAddresses {
+		c.SetDial(publicnet.NewDialer().DialContext)
+	}
+
+	client := &Client{
+		reqClient: c,
+		logger:    opts.Logger,
+	}
+
+	return client, nil
+}
+
+// GetStatus 返回实例 instanceURL 上的嘟文，嘟文不存在或不公开时返回 nil
+//
+// https://docs.joinmastodon.org/methods/statuses/#get
+func (c *Client) GetStatus(instanceURL string, statusID string) (*mastodon_public_types.Status, error) {
+	var status mastodon_public_types.Status
+	var errorResp mastodon_public_types.ErrorResp
+
+	resp, err := c.reqClient.R().
+		SetSuccessResult(&status).
+		SetErrorResult(&errorResp).
+		Get(fmt.Sprintf("%s/api/v1/statuses/%s", strings.TrimSuffix(instanceURL, "/"), statusID))
+	if err != nil {
+		c.logger.Errorf("failed to get mastodon status, err: %v", err)
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to get mastodon status, status code: %d, error: %s", resp.StatusCode, errorResp.Error)
+		return nil, fmt.Errorf("request to %s failed: status code: %d, error: %s", resp.Request.URL, resp.StatusCode, errorResp.Error)
+	}
+
+	return &status, nil
+}
+
+// Download 下载实例上的媒体文件，并以流的形式写入 w
+func (c *Client) Download(link string, w io.Writer) error {
+	resp, err := c.reqClient.R().
+		SetHeader("Accept", "*/*").
+		SetOutput(w).
+		Get(link)
+	if err != nil {
+		c.logger.Errorf("failed to fetch mastodon media, err: %v", err)
+		return err
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to fetch mastodon media, status code: %d", resp.StatusCode)
+		return fmt.Errorf("failed to fetch mastodon media, status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package mastodon_public
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mastodon_public_types "github.com/nekomeowww/perobot/pkg/mastodon/public/types"
+	"github.com/nekomeowww/perobot/pkg/publicnet"
+)
+
+func TestGetStatus(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/statuses/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v1/statuses/109876543210":
+			_, _ = w.Write([]byte(`{
+				"id": "109876543210",
+				"url": "https://mastodon.social/@alice/109876543210",
+				"content": "<p>sketch</p>",
+				"sensitive": true,
+				"spoiler_text": "nsfw",
+				"account": { "id": "1", "username": "alice", "acct": "alice", "display_name": "Alice", "url": "https://mastodon.social/@alice" },
+				"media_attachments": [
+					{
+						"id": "2",
+						"type": "image",
+						"url": "https://files.mastodon.social/media_attachments/files/original/sketch.png",
+						"preview_url": "https://files.mastodon.social/media_attachments/files/small/sketch.png",
+						"description": "a pencil sketch of a cat",
+						"meta": { "original": { "width": 2000, "height": 1500 } }
+					}
+				]
+			}`))
+		case "/api/v1/statuses/500":
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"error":"Internal server error"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"Record not found"}`))
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient(WithPrivateAddressesAllowed())
+	require.NoError(t, err)
+
+	status, err := client.GetStatus(server.URL+"/", "109876543210")
+	require.NoError(t, err)
+	require.NotNil(t, status)
+	assert.True(t, status.Sensitive)
+	assert.Equal(t, "nsfw", status.SpoilerText)
+	assert.Equal(t, "Alice", status.Account.Name())
+	require.Len(t, status.MediaAttachments, 1)
+	assert.Equal(t, mastodon_public_types.MediaAttachmentTypeImage, status.MediaAttachments[0].Type)
+	assert.Equal(t, "a pencil sketch of a cat", status.MediaAttachments[0].Description)
+
+	width, height, duration := status.MediaAttachments[0].OriginalSize()
+	assert.Equal(t, 2000, width)
+	assert.Equal(t, 1500, height)
+	assert.Zero(t, duration)
+
+	status, err = client.GetStatus(server.URL, "1")
+	require.NoError(t, err)
+	assert.Nil(t, status)
+
+	_, err = client.GetStatus(server.URL, "500")
+	assert.Error(t, err)
+}
+
+func TestDownload(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/files/a.png", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("image"))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient(WithPrivateAddressesAllowed())
+	require.NoError(t, err)
+
+	buffer := new(bytes.Buffer)
+	err = client.Download(server.URL+"/files/a.png", buffer)
+	require.NoError(t, err)
+	assert.Equal(t, "image", buffer.String())
+
+	err = client.Download(server.URL+"/files/b.png", io.Discard)
+	assert.Error(t, err)
+}
+
+func TestPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient()
+	require.NoError(t, err)
+
+	_, err = client.GetStatus(server.URL, "109876543210")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, publicnet.ErrForbiddenAddress))
+
+	err = client.Download(server.URL+"/files/a.png", io.Discard)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, publicnet.ErrForbiddenAddress))
+}
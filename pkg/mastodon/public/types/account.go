@@ -0,0 +1,32 @@
+package mastodon_public_types
+
+import "strings"
+
+type Account struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	// Acct 本站用户为 username，其他实例的用户为 username@domain
+	Acct        string `json:"acct"`
+	DisplayName string `json:"display_name"`
+	URL         string `json:"url"`
+	Avatar      string `json:"avatar"`
+	Bot         bool   `json:"bot"`
+}
+
+// Name 返回用户的显示名称，没有设置时使用用户名
+func (a *Account) Name() string {
+	if a.DisplayName != "" {
+		return a.DisplayName
+	}
+
+	return a.Username
+}
+
+// FullAcct 返回带有实例域名的完整用户名 username@domain，instanceHost 为请求所在的实例
+func (a *Account) FullAcct(instanceHost string) string {
+	if strings.Contains(a.Acct, "@") {
+		return a.Acct
+	}
+
+	return a.Acct + "@" + instanceHost
+}
@@ -0,0 +1,7 @@
+package mastodon_public_types
+
+// ErrorResp Mastodon API 请求失败时返回的错误
+type ErrorResp struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
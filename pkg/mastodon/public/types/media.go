@@ -0,0 +1,51 @@
+package mastodon_public_types
+
+type MediaAttachmentType string
+
+const (
+	MediaAttachmentTypeImage MediaAttachmentType = "image"
+	// MediaAttachmentTypeGifv 转换为无声 MP4 的 GIF
+	MediaAttachmentTypeGifv    MediaAttachmentType = "gifv"
+	MediaAttachmentTypeVideo   MediaAttachmentType = "video"
+	MediaAttachmentTypeAudio   MediaAttachmentType = "audio"
+	MediaAttachmentTypeUnknown MediaAttachmentType = "unknown"
+)
+
+// MediaAttachment 嘟文的附件
+//
+// https://docs.joinmastodon.org/entities/MediaAttachment/
+type MediaAttachment struct {
+	ID   string              `json:"id"`
+	Type MediaAttachmentType `json:"type"`
+	// URL 实例上保存的文件，其他实例的附件会被缓存到本实例
+	URL string `json:"url"`
+	// PreviewURL 缩小的预览图，视频为封面图
+	PreviewURL string `json:"preview_url"`
+	// RemoteURL 其他实例附件的原始链接，本站附件为空
+	RemoteURL string `json:"remote_url"`
+	// Description 附件的替代文字
+	Description string               `json:"description"`
+	Blurhash    string               `json:"blurhash"`
+	Meta        *MediaAttachmentMeta `json:"meta,omitempty"`
+}
+
+type MediaAttachmentMeta struct {
+	Original *MediaAttachmentMetaSize `json:"original,omitempty"`
+	Small    *MediaAttachmentMetaSize `json:"small,omitempty"`
+}
+
+type MediaAttachmentMetaSize struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	// Duration 视频和 GIF 的时长，单位为秒
+	Duration float64 `json:"duration"`
+}
+
+// OriginalSize 返回附件原始的宽高和时长，未知时为 0
+func (m *MediaAttachment) OriginalSize() (int, int, float64) {
+	if m.Meta == nil || m.Meta.Original == nil {
+		return 0, 0, 0
+	}
+
+	return m.Meta.Original.Width, m.Meta.Original.Height, m.Meta.Original.Duration
+}
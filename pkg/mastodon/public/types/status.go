@@ -0,0 +1,79 @@
+package mastodon_public_types
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Status 嘟文
+//
+// https://docs.joinmastodon.org/entities/Status/
+type Status struct {
+	ID        string `json:"id"`
+	URI       string `json:"uri"`
+	URL       string `json:"url"`
+	CreatedAt string `json:"created_at"`
+	// Content 嘟文的 HTML 正文
+	Content string `json:"content"`
+	// SpoilerText 内容警告，没有时为空
+	SpoilerText      string             `json:"spoiler_text"`
+	Sensitive        bool               `json:"sensitive"`
+	Visibility       string             `json:"visibility"`
+	Account          *Account           `json:"account"`
+	MediaAttachments []*MediaAttachment `json:"media_attachments"`
+	Tags             []*Tag             `json:"tags"`
+	// Reblog 转嘟的原嘟文，不是转嘟时为 nil
+	Reblog *Status `json:"reblog,omitempty"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// ContentInHTML 将嘟文的 HTML 正文转换为 Telegram 支持的 HTML，段落和换行转换为换行，
+// 链接和提及的用户保留为链接，话题标签保留原文
+func (s *Status) ContentInHTML() string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(s.Content))
+	if err != nil {
+		return html.EscapeString(s.Content)
+	}
+
+	var sb strings.Builder
+	writeContent(&sb, doc.Find("body"))
+
+	return strings.TrimSpace(sb.String())
+}
+
+func writeContent(sb *strings.Builder, selection *goquery.Selection) {
+	selection.Contents().Each(func(_ int, child *goquery.Selection) {
+		switch goquery.NodeName(child) {
+		case "#text":
+			sb.WriteString(html.EscapeString(child.Text()))
+		case "br":
+			sb.WriteString("\n")
+		case "p":
+			writeContent(sb, child)
+			sb.WriteString("\n\n")
+		case "a":
+			writeContentLink(sb, child)
+		default:
+			writeContent(sb, child)
+		}
+	})
+}
+
+func writeContentLink(sb *strings.Builder, selection *goquery.Selection) {
+	text := selection.Text()
+
+	href, _ := selection.Attr("href")
+	if href == "" || selection.HasClass("hashtag") || strings.HasPrefix(text, "#") {
+		sb.WriteString(html.EscapeString(text))
+		return
+	}
+
+	sb.WriteString(fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(href), html.EscapeString(text)))
+}
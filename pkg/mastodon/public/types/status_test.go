@@ -0,0 +1,29 @@
+package mastodon_public_types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentInHTML(t *testing.T) {
+	status := &Status{
+		Content: `<p>New piece &lt;3<br>for <span class="h-card"><a href="https://mastodon.social/@alice" class="u-url mention">@<span>alice</span></a></span></p>` +
+			`<p><a href="https://example.com/artworks?id=1&amp;page=2" rel="nofollow noopener" target="_blank"><span class="invisible">https://</span><span class="ellipsis">example.com/artworks?id=1&amp;</span><span class="invisible">page=2</span></a> ` +
+			`<a href="https://mastodon.social/tags/art" class="mention hashtag" rel="tag">#<span>art</span></a></p>`,
+	}
+
+	assert.Equal(t, ""+
+		"New piece &lt;3\n"+
+		`for <a href="https://mastodon.social/@alice">@alice</a>`+"\n\n"+
+		`<a href="https://example.com/artworks?id=1&amp;page=2">https://example.com/artworks?id=1&amp;page=2</a> #art`,
+		status.ContentInHTML())
+
+	status.Content = ""
+	assert.Empty(t, status.ContentInHTML())
+}
+
+func TestFullAcct(t *testing.T) {
+	assert.Equal(t, "alice@mastodon.social", (&Account{Acct: "alice"}).FullAcct("mastodon.social"))
+	assert.Equal(t, "bob@misskey.io", (&Account{Acct: "bob@misskey.io"}).FullAcct("mastodon.social"))
+}
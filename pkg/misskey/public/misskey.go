@@ -0,0 +1,111 @@
+package misskey_public
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/imroc/req/v3"
+	"github.com/sirupsen/logrus"
+
+	misskey_public_types "github.com/nekomeowww/perobot/pkg/misskey/public/types"
+	"github.com/nekomeowww/perobot/pkg/options"
+	"github.com/nekomeowww/perobot/pkg/publicnet"
+)
+
+type ClientOptions struct {
+	Logger *logrus.Entry
+	// AllowPrivateAddresses 允许连接内网、本机和链路本地地址，仅用于测试
+	AllowPrivateAddresses bool
+}
+
+func WithLogger(logger *logrus.Entry) options.CallOptions[ClientOptions] {
+	return options.NewCallOptions(func(o *ClientOptions) {
+		o.Logger = logger
+	})
+}
+
+// WithPrivateAddressesAllowed 允许连接内网、本机和链路本地地址，仅用于测试
+func WithPrivateAddressesAllowed() options.CallOptions[ClientOptions] {
+	return options.NewCallOptions(func(o *ClientOptions) {
+		o.AllowPrivateAddresses = true
+	})
+}
+
+// Client 访问任意 Misskey 实例以及兼容 Misskey API 的实例（例如 Firefish、Sharkey）的公开接口
+type Client struct {
+	reqClient *req.Client
+	logger    *logrus.Entry
+}
+
+func NewClient(callOpts ...options.CallOptions[ClientOptions]) (*Client, error) {
+	opts := options.ApplyCallOptions(callOpts, ClientOptions{
+		Logger: logrus.NewEntry(logrus.New()),
+	})
+
+	c := req.
+		C().
+		SetUserAgent("Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/109.0.0.0 Safari/537.36 Edg/109.0.1518.52")
+
+	// 实例的域名和媒体文件的链接都由第三方决定，连接时拒绝内网地址，
+	// 以免访问到本机的 Bot API 服务器等内部服务
+	if !opts.AllowPrivateAddresses {
+		c.SetDial(publicnet.NewDialer().DialContext)
+	}
+
+	client := &Client{
+		reqClient: c,
+		logger:    opts.Logger,
+	}
+
+	return client, nil
+}
+
+// ShowNote 返回实例 instanceURL 上的笔记，笔记不存在或不公开时返回 nil
+//
+// https://misskey-hub.net/en/docs/for-developers/api/endpoints/notes/show
+func (c *Client) ShowNote(instanceURL string, noteID string) (*misskey_public_types.Note, error) {
+	var note misskey_public_types.Note
+	var errorResp misskey_public_types.ErrorResp
+
+	resp, err := c.reqClient.R().
+		SetBodyJsonMarshal(map[string]string{"noteId": noteID}).
+		SetSuccessResult(&note).
+		SetErrorResult(&errorResp).
+		Post(fmt.Sprintf("%s/api/notes/show", strings.TrimSuffix(instanceURL, "/")))
+	if err != nil {
+		c.logger.Errorf("failed to show misskey note, err: %v", err)
+		return nil, err
+	}
+	if errorResp.Error != nil && errorResp.Error.Code == misskey_public_types.ErrorCodeNoSuchNote {
+		return nil, nil
+	}
+	if !resp.IsSuccess() {
+		var code string
+		if errorResp.Error != nil {
+			code = errorResp.Error.Code
+		}
+
+		c.logger.Errorf("failed to show misskey note, status code: %d, error: %s", resp.StatusCode, code)
+		return nil, fmt.Errorf("request to %s failed: status code: %d, error: %s", resp.Request.URL, resp.StatusCode, code)
+	}
+
+	return &note, nil
+}
+
+// Download 下载实例上的媒体文件，并以流的形式写入 w
+func (c *Client) Download(link string, w io.Writer) error {
+	resp, err := c.reqClient.R().
+		SetOutput(w).
+		Get(link)
+	if err != nil {
+		c.logger.Errorf("failed to fetch misskey media, err: %v", err)
+		return err
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to fetch misskey media, status code: %d", resp.StatusCode)
+		return fmt.Errorf("failed to fetch misskey media, status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package misskey_public
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nekomeowww/perobot/pkg/publicnet"
+)
+
+func TestShowNote(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/notes/show", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+
+		var body struct {
+			NoteID string `json:"noteId"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch body.NoteID {
+		case "9abcdefghi":
+			_, _ = w.Write([]byte(`{
+				"id": "9abcdefghi",
+				"text": "描いた",
+				"cw": null,
+				"user": { "id": "1", "name": null, "username": "bob", "host": null },
+				"files": [
+					{
+						"id": "2",
+						"type": "image/webp",
+						"url": "https://media.misskey.io/files/drawing.webp",
+						"thumbnailUrl": "https://media.misskey.io/files/thumbnail-drawing.webp",
+						"isSensitive": true,
+						"comment": "落書き",
+						"properties": { "width": 1200, "height": 900 }
+					}
+				]
+			}`))
+		case "server-error":
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"error":{"message":"Internal error occurred.","code":"INTERNAL_ERROR","id":"5d37dbcb"}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"message":"No such note.","code":"NO_SUCH_NOTE","id":"24fcbfc6"}}`))
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient(WithPrivateAddressesAllowed())
+	require.NoError(t, err)
+
+	note, err := client.ShowNote(server.URL, "9abcdefghi")
+	require.NoError(t, err)
+	require.NotNil(t, note)
+	assert.Equal(t, "描いた", note.Text)
+	assert.Empty(t, note.CW)
+	assert.Equal(t, "bob", note.User.DisplayName())
+	assert.Equal(t, "bob@misskey.io", note.User.FullAcct("misskey.io"))
+	assert.True(t, note.HasSensitiveFiles())
+	require.Len(t, note.Files, 1)
+	assert.True(t, note.Files[0].IsImage())
+	assert.Equal(t, "落書き", note.Files[0].Comment)
+
+	note, err = client.ShowNote(server.URL, "deleted")
+	require.NoError(t, err)
+	assert.Nil(t, note)
+
+	_, err = client.ShowNote(server.URL, "server-error")
+	assert.Error(t, err)
+}
+
+func TestDownload(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/files/a.png", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("image"))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient(WithPrivateAddressesAllowed())
+	require.NoError(t, err)
+
+	buffer := new(bytes.Buffer)
+	err = client.Download(server.URL+"/files/a.png", buffer)
+	require.NoError(t, err)
+	assert.Equal(t, "image", buffer.String())
+
+	err = client.Download(server.URL+"/files/b.png", io.Discard)
+	assert.Error(t, err)
+}
+
+func TestPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient()
+	require.NoError(t, err)
+
+	_, err = client.ShowNote(server.URL, "9abcdefghi")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, publicnet.ErrForbiddenAddress))
+
+	err = client.Download(server.URL+"/files/a.png", io.Discard)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, publicnet.ErrForbiddenAddress))
+}
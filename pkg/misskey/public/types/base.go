@@ -0,0 +1,16 @@
+package misskey_public_types
+
+// ErrorResp Misskey API 请求失败时返回的错误
+type ErrorResp struct {
+	Error *Error `json:"error"`
+}
+
+type Error struct {
+	Message string `json:"message"`
+	Code    string `json:"code"`
+	ID      string `json:"id"`
+}
+
+const (
+	ErrorCodeNoSuchNote = "NO_SUCH_NOTE"
+)
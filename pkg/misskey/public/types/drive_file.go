@@ -0,0 +1,36 @@
+package misskey_public_types
+
+import "strings"
+
+// DriveFile 笔记的附件
+//
+// https://misskey-hub.net/en/docs/for-developers/api/
+type DriveFile struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Type 文件的 MIME 类型
+	Type string `json:"type"`
+	Size int64  `json:"size"`
+	// IsSensitive 附件被标记为敏感内容
+	IsSensitive bool `json:"isSensitive"`
+	// Comment 附件的替代文字
+	Comment      string              `json:"comment"`
+	URL          string              `json:"url"`
+	ThumbnailURL string              `json:"thumbnailUrl"`
+	Properties   DriveFileProperties `json:"properties"`
+}
+
+type DriveFileProperties struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// IsImage 附件为图片
+func (f *DriveFile) IsImage() bool {
+	return strings.HasPrefix(f.Type, "image/")
+}
+
+// IsVideo 附件为视频
+func (f *DriveFile) IsVideo() bool {
+	return strings.HasPrefix(f.Type, "video/")
+}
@@ -0,0 +1,35 @@
+package misskey_public_types
+
+import "github.com/samber/lo"
+
+// Note 笔记
+type Note struct {
+	ID        string `json:"id"`
+	CreatedAt string `json:"createdAt"`
+	// Text 笔记的 MFM 正文，只转发时为空
+	Text string `json:"text"`
+	// CW 内容警告，没有时为空
+	CW         string       `json:"cw"`
+	User       *UserLite    `json:"user"`
+	Visibility string       `json:"visibility"`
+	Files      []*DriveFile `json:"files"`
+	Tags       []string     `json:"tags"`
+	// URI 其他实例笔记的 ActivityPub URI，本站笔记为空
+	URI string `json:"uri"`
+	// URL 其他实例笔记的网页链接，本站笔记为空
+	URL string `json:"url"`
+	// Renote 转发的笔记，不是转发时为 nil
+	Renote *Note `json:"renote,omitempty"`
+}
+
+// IsPureRenote 笔记只是转发，没有附加正文和附件
+func (n *Note) IsPureRenote() bool {
+	return n.Renote != nil && n.Text == "" && n.CW == "" && len(n.Files) == 0
+}
+
+// HasSensitiveFiles 笔记中有被标记为敏感内容的附件
+func (n *Note) HasSensitiveFiles() bool {
+	return lo.SomeBy(n.Files, func(file *DriveFile) bool {
+		return file.IsSensitive
+	})
+}
@@ -0,0 +1,29 @@
+package misskey_public_types
+
+// UserLite 笔记中附带的用户信息
+type UserLite struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	// Host 其他实例用户所在的实例域名，本站用户为空
+	Host      string `json:"host"`
+	AvatarURL string `json:"avatarUrl"`
+}
+
+// DisplayName 返回用户的显示名称，没有设置时使用用户名
+func (u *UserLite) DisplayName() string {
+	if u.Name != "" {
+		return u.Name
+	}
+
+	return u.Username
+}
+
+// FullAcct 返回带有实例域名的完整用户名 username@host，instanceHost 为请求所在的实例
+func (u *UserLite) FullAcct(instanceHost string) string {
+	if u.Host != "" {
+		return u.Username + "@" + u.Host
+	}
+
+	return u.Username + "@" + instanceHost
+}
@@ -0,0 +1,246 @@
+package opengraph_public
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/imroc/req/v3"
+	"github.com/samber/lo"
+	"github.com/sirupsen/logrus"
+
+	opengraph_public_types "github.com/nekomeowww/perobot/pkg/opengraph/public/types"
+	"github.com/nekomeowww/perobot/pkg/options"
+	"github.com/nekomeowww/perobot/pkg/publicnet"
+)
+
+const (
+	// MaxPageSize 读取网页的最大字节数，元数据位于 <head> 中，不需要读取完整的网页
+	MaxPageSize = 2 * 1024 * 1024
+)
+
+type ClientOptions struct {
+	Logger *logrus.Entry
+	// AllowPrivateAddresses 允许连接内网、本机和链路本地地址，仅用于测试
+	AllowPrivateAddresses bool
+}
+
+func WithLogger(logger *logrus.Entry) options.CallOptions[ClientOptions] {
+	return options.NewCallOptions(func(o *ClientOptions) {
+		o.Logger = logger
+	})
+}
+
+// WithPrivateAddressesAllowed 允许连接内网、本机和链路本地地址，仅用于测试
+func WithPrivateAddressesAllowed() options.CallOptions[ClientOptions] {
+	return options.NewCallOptions(func(o *ClientOptions) {
+		o.AllowPrivateAddresses = true
+	})
+}
+
+// Client 读取任意网页中的 OpenGraph 和 Twitter Card 元数据
+type Client struct {
+	reqClient *req.Client
+	logger    *logrus.Entry
+}
+
+func NewClient(callOpts ...options.CallOptions[ClientOptions]) (*Client, error) {
+	opts := options.ApplyCallOptions(callOpts, ClientOptions{
+		Logger: logrus.NewEntry(logrus.New()),
+	})
+
+	c := req.
+		C().
+		SetRedirectPolicy(req.MaxRedirectPolicy(5))
+
+	// 网页和其中的图片链接都由第三方决定，包括重定向之后的地址，连接时拒绝内网地址，
+	// 以免访问到本机的 Bot API 服务器等内部服务
+	if !opts.AllowPrivateAddresses {
+		c.SetDial(publicnet.NewDialer().DialContext)
+	}
+
+	client := &Client{
+		reqClient: c,
+		logger:    opts.Logger,
+	}
+
+	return client, nil
+}
+
+// GetPage 返回网页中的元数据，网页不存在或者不是 HTML 时返回 nil
+func (c *Client) GetPage(link string) (*opengraph_public_types.Page, error) {
+	resp, err := c.reqClient.R().
+		SetHeader("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.8").
+		DisableAutoReadResponse().
+		Get(link)
+	if err != nil {
+		c.logger.Errorf("failed to get page, err: %v", err)
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return nil, nil
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to get page, status code: %d", resp.StatusCode)
+		return nil, fmt.Errorf("request to %s failed: status code: %d", link, resp.StatusCode)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.GetHeader("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		c.logger.Warnf("page %s is not html but %s", link, mediaType)
+		return nil, nil
+	}
+
+	pageURL := resp.Response.Request.URL
+
+	dom, err := goquery.NewDocumentFromReader(io.LimitReader(resp.Body, MaxPageSize))
+	if err != nil {
+		c.logger.Errorf("failed to parse page from response body, err: %v", err)
+		return nil, err
+	}
+
+	return ParsePage(pageURL, dom), nil
+}
+
+// ParsePage 从网页的 <meta> 标签中读取元数据，pageURL 用于将图片的相对链接转换为绝对链接
+func ParsePage(pageURL *url.URL, dom *goquery.Document) *opengraph_public_types.Page {
+	page := &opengraph_public_types.Page{
+		URL: pageURL.String(),
+	}
+
+	var twitterTitle, twitterDescription string
+	openGraphImages := make([]*opengraph_public_types.Image, 0)
+	twitterImages := make([]*opengraph_public_types.Image, 0)
+
+	dom.Find("meta").Each(func(_ int, selection *goquery.Selection) {
+		// OpenGraph 使用 property 属性，Twitter Card 和部分网站使用 name 属性
+		property, ok := selection.Attr("property")
+		if !ok || property == "" {
+			property = selection.AttrOr("name", "")
+		}
+
+		property = strings.ToLower(strings.TrimSpace(property))
+		content := strings.TrimSpace(selection.AttrOr("content", ""))
+		if property == "" || content == "" {
+			return
+		}
+
+		switch property {
+		case "og:title":
+			page.Title = lo.Ternary(page.Title != "", page.Title, content)
+		case "og:description":
+			page.Description = lo.Ternary(page.Description != "", page.Description, content)
+		case "og:site_name":
+			page.SiteName = lo.Ternary(page.SiteName != "", page.SiteName, content)
+		case "twitter:title":
+			twitterTitle = lo.Ternary(twitterTitle != "", twitterTitle, content)
+		case "twitter:description":
+			twitterDescription = lo.Ternary(twitterDescription != "", twitterDescription, content)
+		case "og:image", "og:image:url":
+			imageURL := resolveURL(pageURL, content)
+			if imageURL == "" {
+				return
+			}
+			// og:image 与 og:image:url 同时出现时指向同一张图片
+			if len(openGraphImages) > 0 && openGraphImages[len(openGraphImages)-1].URL == imageURL {
+				return
+			}
+
+			openGraphImages = append(openGraphImages, &opengraph_public_types.Image{URL: imageURL})
+		case "og:image:secure_url":
+			imageURL := resolveURL(pageURL, content)
+			if imageURL == "" {
+				return
+			}
+			if len(openGraphImages) == 0 {
+				openGraphImages = append(openGraphImages, &opengraph_public_types.Image{URL: imageURL})
+				return
+			}
+
+			openGraphImages[len(openGraphImages)-1].URL = imageURL
+		case "og:image:width", "og:image:height", "og:image:alt":
+			// 图片的属性跟在对应的 og:image 之后
+			if len(openGraphImages) == 0 {
+				return
+			}
+
+			setImageProperty(openGraphImages[len(openGraphImages)-1], strings.TrimPrefix(property, "og:image:"), content)
+		case "twitter:image", "twitter:image:src":
+			imageURL := resolveURL(pageURL, content)
+			if imageURL == "" {
+				return
+			}
+
+			twitterImages = append(twitterImages, &opengraph_public_types.Image{URL: imageURL})
+		case "twitter:image:alt":
+			if len(twitterImages) == 0 {
+				return
+			}
+
+			setImageProperty(twitterImages[len(twitterImages)-1], "alt", content)
+		}
+	})
+
+	if page.Title == "" {
+		page.Title = lo.Ternary(twitterTitle != "", twitterTitle, strings.TrimSpace(dom.Find("title").First().Text()))
+	}
+	if page.Description == "" {
+		page.Description = twitterDescription
+	}
+
+	page.Images = lo.UniqBy(lo.Ternary(len(openGraphImages) > 0, openGraphImages, twitterImages), func(image *opengraph_public_types.Image) string {
+		return image.URL
+	})
+
+	return page
+}
+
+// setImageProperty 设置图片的 width、height 或 alt
+func setImageProperty(image *opengraph_public_types.Image, name string, content string) {
+	switch name {
+	case "width":
+		image.Width, _ = strconv.Atoi(content)
+	case "height":
+		image.Height, _ = strconv.Atoi(content)
+	case "alt":
+		image.Alt = content
+	}
+}
+
+// resolveURL 将 link 转换为相对于 pageURL 的绝对链接，不是 http 或 https 链接时返回空字符串
+func resolveURL(pageURL *url.URL, link string) string {
+	parsedURL, err := pageURL.Parse(link)
+	if err != nil {
+		return ""
+	}
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return ""
+	}
+
+	return parsedURL.String()
+}
+
+// Download 下载网页中的图片，并以流的形式写入 w
+func (c *Client) Download(link string, w io.Writer) error {
+	resp, err := c.reqClient.R().
+		SetOutput(w).
+		Get(link)
+	if err != nil {
+		c.logger.Errorf("failed to fetch page image, err: %v", err)
+		return err
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to fetch page image, status code: %d", resp.StatusCode)
+		return fmt.Errorf("failed to fetch page image, status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
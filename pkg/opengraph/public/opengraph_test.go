@@ -0,0 +1,106 @@
+package opengraph_public
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nekomeowww/perobot/pkg/publicnet"
+)
+
+func TestGetPage(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/posts/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(`<!DOCTYPE html>
+			<html>
+				<head>
+					<title>Fallback title</title>
+					<meta property="og:title" content="Castle &amp; Keep">
+					<meta property="og:description" content="A castle.">
+					<meta property="og:site_name" content="Example Blog">
+					<meta property="og:image" content="/images/castle.jpg">
+					<meta property="og:image:secure_url" content="https://cdn.example.com/images/castle.jpg">
+					<meta property="og:image:width" content="1200">
+					<meta property="og:image:height" content="630">
+					<meta property="og:image:alt" content="The castle at dawn">
+					<meta property="og:image" content="javascript:alert(1)">
+					<meta property="og:image" content="https://cdn.example.com/images/keep.png">
+					<meta name="twitter:image" content="https://cdn.example.com/images/twitter.jpg">
+				</head>
+				<body></body>
+			</html>`))
+	})
+	mux.HandleFunc("/posts/2", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html><head>
+			<title> Plain page </title>
+			<meta name="twitter:description" content="Only twitter card">
+			<meta name="twitter:image:src" content="images/card.png">
+		</head></html>`))
+	})
+	mux.HandleFunc("/image.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte("png"))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient(WithPrivateAddressesAllowed())
+	require.NoError(t, err)
+
+	page, err := client.GetPage(server.URL + "/posts/1")
+	require.NoError(t, err)
+	require.NotNil(t, page)
+	assert.Equal(t, "Castle & Keep", page.Title)
+	assert.Equal(t, "A castle.", page.Description)
+	assert.Equal(t, "Example Blog", page.SiteName)
+	require.Len(t, page.Images, 2)
+	assert.Equal(t, "https://cdn.example.com/images/castle.jpg", page.Images[0].URL)
+	assert.Equal(t, 1200, page.Images[0].Width)
+	assert.Equal(t, 630, page.Images[0].Height)
+	assert.Equal(t, "The castle at dawn", page.Images[0].Alt)
+	assert.Equal(t, "https://cdn.example.com/images/keep.png", page.Images[1].URL)
+
+	page, err = client.GetPage(server.URL + "/posts/2")
+	require.NoError(t, err)
+	require.NotNil(t, page)
+	assert.Equal(t, "Plain page", page.Title)
+	assert.Equal(t, "Only twitter card", page.Description)
+	require.Len(t, page.Images, 1)
+	assert.Equal(t, server.URL+"/posts/images/card.png", page.Images[0].URL)
+
+	page, err = client.GetPage(server.URL + "/image.png")
+	require.NoError(t, err)
+	assert.Nil(t, page)
+
+	page, err = client.GetPage(server.URL + "/missing")
+	require.NoError(t, err)
+	assert.Nil(t, page)
+}
+
+func TestPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html></html>`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient()
+	require.NoError(t, err)
+
+	_, err = client.GetPage(server.URL)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, publicnet.ErrForbiddenAddress))
+
+	err = client.Download(server.URL+"/image.png", io.Discard)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, publicnet.ErrForbiddenAddress))
+
+}
@@ -0,0 +1,29 @@
+package opengraph_public_types
+
+// Image 网页元数据中的一张图片
+type Image struct {
+	URL string
+	// Width 和 Height 为网页声明的图片尺寸，未声明时为 0
+	Width  int
+	Height int
+	// Alt 图片的替代文字，没有时为空
+	Alt string
+}
+
+// Page 网页中 OpenGraph 和 Twitter Card 的元数据
+//
+// https://ogp.me/
+//
+// https://developer.x.com/en/docs/twitter-for-websites/cards/markup-reference
+type Page struct {
+	// URL 跟随重定向之后的网页链接
+	URL string
+	// Title og:title，没有时依次使用 twitter:title 和 <title>
+	Title string
+	// Description og:description，没有时使用 twitter:description
+	Description string
+	// SiteName og:site_name，没有时为空
+	SiteName string
+	// Images og:image 中的图片，没有时使用 twitter:image 中的图片，链接已经转换为绝对链接
+	Images []*Image
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/PuerkitoBio/goquery"
@@ -175,20 +176,55 @@ func (c *Client) IllustDetailPages(illustID string) (*pixiv_public_types.IllustD
 	return &illustDetailPages, nil
 }
 
+// UgoiraMeta 获取 Pixiv 动图的帧信息，帧压缩包可以通过 GetImage 下载
+//
+// https://natescarlet.github.io/pixiv/artwork.html#id3
+func (c *Client) UgoiraMeta(illustID string) (*pixiv_public_types.UgoiraMetaResp, error) {
+	var ugoiraMeta pixiv_public_types.UgoiraMetaResp
+
+	resp, err := c.reqClient.R().
+		SetResult(&ugoiraMeta).
+		Get(fmt.Sprintf("https://www.pixiv.net/ajax/illust/%s/ugoira_meta", illustID))
+	if err != nil {
+		c.logger.Errorf("failed to get ugoira meta: %v, full request: %s", err, resp.Dump())
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to get ugoira meta, status code: %d, full request: %s", resp.StatusCode, resp.Dump())
+		return nil, fmt.Errorf("request to %s failed: status code: %d", resp.Request.URL, resp.StatusCode)
+	}
+	if ugoiraMeta.Error {
+		c.logger.Errorf("failed to get ugoira meta, error: %s, full request: %s", ugoiraMeta.Message, resp.Dump())
+		return nil, fmt.Errorf("request to %s failed: error: %s", resp.Request.URL, ugoiraMeta.Message)
+	}
+
+	return &ugoiraMeta, nil
+}
+
 func (c *Client) GetImage(link string) (*bytes.Buffer, error) {
 	buffer := new(bytes.Buffer)
 
+	err := c.GetImageTo(link, buffer)
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer, nil
+}
+
+// GetImageTo 下载 Pixiv 的图片或动图帧压缩包，并以流的形式写入 w
+func (c *Client) GetImageTo(link string, w io.Writer) error {
 	resp, err := c.reqClient.R().
-		SetOutput(buffer).
+		SetOutput(w).
 		Get(link)
 	if err != nil {
 		c.logger.Errorf("failed to fetch pixiv image, err: %v, full request: %s", err, resp.Dump())
-		return nil, err
+		return err
 	}
 	if !resp.IsSuccess() {
 		c.logger.Errorf("failed to fetch pixiv image, status code: %d, full request: %s", resp.StatusCode, resp.Dump())
-		return nil, fmt.Errorf("failed to fetch pixiv image, status code: %d", resp.StatusCode)
+		return fmt.Errorf("failed to fetch pixiv image, status code: %d", resp.StatusCode)
 	}
 
-	return buffer, nil
+	return nil
 }
@@ -65,6 +65,38 @@ type IllustTitleCaptionTranslation struct {
 	WorkCaption interface{} `json:"workCaption"`
 }
 
+const (
+	// XRestrictAllAges 全年龄
+	XRestrictAllAges = 0
+	// XRestrictR18 R-18
+	XRestrictR18 = 1
+	// XRestrictR18G R-18G
+	XRestrictR18G = 2
+)
+
+const (
+	// IllustTypeIllust 插画
+	IllustTypeIllust = 0
+	// IllustTypeManga 漫画
+	IllustTypeManga = 1
+	// IllustTypeUgoira 动图
+	IllustTypeUgoira = 2
+)
+
+const (
+	// AiTypeUnknown 作者未声明是否为 AI 生成
+	AiTypeUnknown = 0
+	// AiTypeNotAIGenerated 作者声明不是 AI 生成
+	AiTypeNotAIGenerated = 1
+	// AiTypeAIGenerated 作者声明是 AI 生成
+	AiTypeAIGenerated = 2
+)
+
+const (
+	// SlSensitive 作品的 sanity level 不低于该值时，Pixiv 会将其视为敏感内容
+	SlSensitive = 4
+)
+
 type Illust struct {
 	IllustID                string                         `json:"illustId"`
 	IllustTitle             string                         `json:"illustTitle"`
@@ -120,6 +152,42 @@ type Illust struct {
 	AiType                  int                            `json:"aiType"`
 }
 
+// IsSensitive 作品是否为 R-18、R-18G 或被 Pixiv 视为敏感内容
+func (i *Illust) IsSensitive() bool {
+	return i.XRestrict != XRestrictAllAges || i.Sl >= SlSensitive
+}
+
+// RestrictLabel 返回作品的年龄限制标签，全年龄作品返回空字符串
+func (i *Illust) RestrictLabel() string {
+	switch i.XRestrict {
+	case XRestrictR18:
+		return "R-18"
+	case XRestrictR18G:
+		return "R-18G"
+	default:
+		return ""
+	}
+}
+
+// IllustTypeName 返回作品类型的名称，即 illust、manga 或 ugoira，未知类型返回空字符串
+func (i *Illust) IllustTypeName() string {
+	switch i.IllustType {
+	case IllustTypeIllust:
+		return "illust"
+	case IllustTypeManga:
+		return "manga"
+	case IllustTypeUgoira:
+		return "ugoira"
+	default:
+		return ""
+	}
+}
+
+// IsAIGenerated 作者是否声明作品为 AI 生成
+func (i *Illust) IsAIGenerated() bool {
+	return i.AiType == AiTypeAIGenerated
+}
+
 type UserIllusts struct {
 	ID                      string                         `json:"id"`
 	Title                   string                         `json:"title"`
@@ -0,0 +1,21 @@
+package pixiv_public_types
+
+// UgoiraFrame 动图中的一帧
+type UgoiraFrame struct {
+	// File 帧在压缩包中的文件名
+	File string `json:"file"`
+	// Delay 帧的显示时长，单位为毫秒
+	Delay int `json:"delay"`
+}
+
+// UgoiraMeta 动图的帧信息
+type UgoiraMeta struct {
+	// Src 预览尺寸的帧压缩包
+	Src string `json:"src"`
+	// OriginalSrc 原始尺寸的帧压缩包
+	OriginalSrc string         `json:"originalSrc"`
+	MimeType    string         `json:"mime_type"`
+	Frames      []*UgoiraFrame `json:"frames"`
+}
+
+type UgoiraMetaResp = BaseResp[*UgoiraMeta]
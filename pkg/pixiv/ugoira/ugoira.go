@@ -0,0 +1,283 @@
+package ugoira
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/lzw"
+	"errors"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+
+	pixiv_public_types "github.com/nekomeowww/perobot/pkg/pixiv/public/types"
+)
+
+const (
+	// minGIFDelay GIF 中帧的最短显示时长，单位为 1/100 秒，更短的时长会被大部分客户端当作 1/10 秒处理
+	minGIFDelay = 2
+	// maxGIFDimension GIF 中宽高的最大值
+	maxGIFDimension = 1<<16 - 1
+)
+
+var (
+	ErrNoFrames = errors.New("ugoira has no frames")
+)
+
+// Decode 按照 frames 的顺序从帧压缩包中逐帧解码，每解码一帧调用一次 fn，fn 返回后不再持有该帧
+func Decode(zipData []byte, frames []*pixiv_public_types.UgoiraFrame, fn func(img image.Image, frame *pixiv_public_types.UgoiraFrame) error) error {
+	if len(frames) == 0 {
+		return ErrNoFrames
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(zipData), int64(len(zipData)))
+	if err != nil {
+		return fmt.Errorf("failed to open ugoira zip: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(reader.File))
+	for _, file := range reader.File {
+		files[file.Name] = file
+	}
+
+	for _, frame := range frames {
+		file, ok := files[frame.File]
+		if !ok {
+			return fmt.Errorf("frame %s not found in ugoira zip", frame.File)
+		}
+
+		img, err := decodeFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to decode frame %s: %w", frame.File, err)
+		}
+
+		err = fn(img, frame)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func decodeFile(file *zip.File) (image.Image, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	img, _, err := image.Decode(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	return img, nil
+}
+
+// EncodeGIF 将帧压缩包按照 frames 中的显示时长编码为无限循环的 GIF 并写入 w，返回第一帧
+//
+// 帧逐一解码和编码，内存中只保留第一帧和正在编码的帧。
+func EncodeGIF(w io.Writer, zipData []byte, frames []*pixiv_public_types.UgoiraFrame) (image.Image, error) {
+	var first image.Image
+	var encoder *GIFEncoder
+
+	err := Decode(zipData, frames, func(img image.Image, frame *pixiv_public_types.UgoiraFrame) error {
+		if encoder == nil {
+			first = img
+			encoder = NewGIFEncoder(w, img.Bounds())
+		}
+
+		return encoder.Encode(img, frame.Delay)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = encoder.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return first, nil
+}
+
+// GIFEncoder 逐帧编码无限循环的 GIF，所有帧使用 Plan 9 调色板作为全局调色板，
+// 与 gif.EncodeAll 不同，不需要在内存中保留所有帧
+type GIFEncoder struct {
+	writer *bufio.Writer
+	bounds image.Rectangle
+	// frames 已编码的帧数，为 0 时需要先写入文件头
+	frames int
+	err    error
+}
+
+// NewGIFEncoder 创建画布为 bounds 的 GIFEncoder，所有帧都需要位于 bounds 之内，编码完成后需要调用 Close
+func NewGIFEncoder(w io.Writer, bounds image.Rectangle) *GIFEncoder {
+	return &GIFEncoder{
+		writer: bufio.NewWriter(w),
+		bounds: bounds,
+	}
+}
+
+// Encode 以 Floyd–Steinberg 抖动将 img 量化到 Plan 9 调色板并写入一帧，delay 为显示时长，单位为毫秒
+func (e *GIFEncoder) Encode(img image.Image, delay int) error {
+	if e.err != nil {
+		return e.err
+	}
+
+	bounds := img.Bounds()
+	if !bounds.In(e.bounds) {
+		return fmt.Errorf("frame bounds %v is out of gif bounds %v", bounds, e.bounds)
+	}
+	if e.frames == 0 {
+		e.err = e.writeHeader()
+		if e.err != nil {
+			return e.err
+		}
+	}
+
+	paletted := image.NewPaletted(bounds, palette.Plan9)
+	draw.FloydSteinberg.Draw(paletted, bounds, img, bounds.Min)
+
+	e.err = e.writeFrame(paletted, max(delay/10, minGIFDelay))
+	if e.err != nil {
+		return e.err
+	}
+
+	e.frames++
+
+	return nil
+}
+
+// Close 写入文件尾，没有写入任何帧时返回 ErrNoFrames
+func (e *GIFEncoder) Close() error {
+	if e.err != nil {
+		return e.err
+	}
+	if e.frames == 0 {
+		return ErrNoFrames
+	}
+
+	e.err = e.writer.WriteByte(0x3b)
+	if e.err != nil {
+		return e.err
+	}
+
+	return e.writer.Flush()
+}
+
+// writeHeader 写入文件头、逻辑屏幕描述符、全局调色板和 NETSCAPE2.0 循环扩展
+func (e *GIFEncoder) writeHeader() error {
+	width, height := e.bounds.Dx(), e.bounds.Dy()
+	if width > maxGIFDimension || height > maxGIFDimension {
+		return fmt.Errorf("gif dimensions %dx%d are too large", width, height)
+	}
+
+	header := make([]byte, 0, 13+3*256+19)
+	header = append(header, "GIF89a"...)
+	// 存在全局调色板，颜色深度为 8 位，调色板大小为 2^(7+1)
+	header = append(header, byte(width), byte(width>>8), byte(height), byte(height>>8), 0xf7, 0x00, 0x00)
+	for _, c := range palette.Plan9 {
+		r, g, b, _ := c.RGBA()
+		header = append(header, byte(r>>8), byte(g>>8), byte(b>>8))
+	}
+
+	// 循环次数为 0，即无限循环
+	header = append(header, 0x21, 0xff, 0x0b)
+	header = append(header, "NETSCAPE2.0"...)
+	header = append(header, 0x03, 0x01, 0x00, 0x00, 0x00)
+
+	_, err := e.writer.Write(header)
+	return err
+}
+
+// writeFrame 写入图形控制扩展、图像描述符和 LZW 压缩的像素数据
+func (e *GIFEncoder) writeFrame(paletted *image.Paletted, delay int) error {
+	bounds := paletted.Bounds()
+	left, top := bounds.Min.X-e.bounds.Min.X, bounds.Min.Y-e.bounds.Min.Y
+	width, height := bounds.Dx(), bounds.Dy()
+
+	_, err := e.writer.Write([]byte{
+		0x21, 0xf9, 0x04, 0x00, byte(delay), byte(delay >> 8), 0x00, 0x00,
+		0x2c, byte(left), byte(left >> 8), byte(top), byte(top >> 8), byte(width), byte(width >> 8), byte(height), byte(height >> 8), 0x00,
+		// LZW 最小码长
+		0x08,
+	})
+	if err != nil {
+		return err
+	}
+
+	blocks := &blockWriter{writer: e.writer}
+	compressor := lzw.NewWriter(blocks, lzw.LSB, 8)
+
+	for y := 0; y < height; y++ {
+		_, err = compressor.Write(paletted.Pix[y*paletted.Stride : y*paletted.Stride+width])
+		if err != nil {
+			return err
+		}
+	}
+
+	err = compressor.Close()
+	if err != nil {
+		return err
+	}
+
+	return blocks.close()
+}
+
+// blockWriter 将数据分割为最多 255 字节的数据子块
+type blockWriter struct {
+	writer *bufio.Writer
+	buffer [255]byte
+	length int
+}
+
+func (b *blockWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := copy(b.buffer[b.length:], p)
+		b.length += n
+		written += n
+		p = p[n:]
+
+		if b.length == len(b.buffer) {
+			err := b.flush()
+			if err != nil {
+				return written, err
+			}
+		}
+	}
+
+	return written, nil
+}
+
+func (b *blockWriter) flush() error {
+	if b.length == 0 {
+		return nil
+	}
+
+	err := b.writer.WriteByte(byte(b.length))
+	if err != nil {
+		return err
+	}
+
+	_, err = b.writer.Write(b.buffer[:b.length])
+	b.length = 0
+
+	return err
+}
+
+// close 写入剩余的数据和块终止符
+func (b *blockWriter) close() error {
+	err := b.flush()
+	if err != nil {
+		return err
+	}
+
+	return b.writer.WriteByte(0x00)
+}
@@ -0,0 +1,109 @@
+package ugoira
+
+import (
+	"archive/zip"
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pixiv_public_types "github.com/nekomeowww/perobot/pkg/pixiv/public/types"
+)
+
+func newTestZip(t *testing.T, names ...string) []byte {
+	buffer := new(bytes.Buffer)
+	writer := zip.NewWriter(buffer)
+
+	for i, name := range names {
+		img := image.NewRGBA(image.Rect(0, 0, 16, 8))
+		for x := 0; x < 16; x++ {
+			for y := 0; y < 8; y++ {
+				img.Set(x, y, color.RGBA{R: uint8(i * 100), G: uint8(x * 16), B: 0, A: 255})
+			}
+		}
+
+		file, err := writer.Create(name)
+		require.NoError(t, err)
+		require.NoError(t, png.Encode(file, img))
+	}
+
+	require.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
+
+func TestDecodeAndEncodeGIF(t *testing.T) {
+	frames := []*pixiv_public_types.UgoiraFrame{
+		{File: "000001.png", Delay: 100},
+		{File: "000000.png", Delay: 5},
+	}
+
+	buffer := new(bytes.Buffer)
+	first, err := EncodeGIF(buffer, newTestZip(t, "000000.png", "000001.png"), frames)
+	require.NoError(t, err)
+
+	// 帧按照 frames 的顺序编码
+	r, _, _, _ := first.At(0, 0).RGBA()
+	assert.Equal(t, uint32(100*0x101), r)
+
+	decoded, err := gif.DecodeAll(buffer)
+	require.NoError(t, err)
+	assert.Len(t, decoded.Image, 2)
+	assert.Equal(t, []int{10, minGIFDelay}, decoded.Delay)
+	assert.Equal(t, 0, decoded.LoopCount)
+	assert.Equal(t, image.Rect(0, 0, 16, 8), decoded.Image[0].Bounds())
+	assert.Equal(t, image.Rect(0, 0, 16, 8), image.Rect(0, 0, decoded.Config.Width, decoded.Config.Height))
+
+	// 量化后的颜色与原始帧相近
+	r, g, _, _ := decoded.Image[1].At(15, 0).RGBA()
+	assert.InDelta(t, 0, float64(r>>8), 40)
+	assert.InDelta(t, 240, float64(g>>8), 40)
+}
+
+func TestGIFEncoder(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	encoder := NewGIFEncoder(buffer, image.Rect(0, 0, 300, 200))
+	assert.ErrorIs(t, encoder.Close(), ErrNoFrames)
+
+	// 超出画布的帧
+	img := image.NewRGBA(image.Rect(10, 20, 310, 220))
+	assert.Error(t, encoder.Encode(img, 100))
+
+	// 压缩后的数据超过一个子块，并且帧的大小和位置各不相同
+	buffer = new(bytes.Buffer)
+	encoder = NewGIFEncoder(buffer, image.Rect(0, 0, 300, 200))
+	for i := 0; i < 3; i++ {
+		img := image.NewRGBA(image.Rect(10, 20, 110+i*50, 120+i*40))
+		for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+			for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+				img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: uint8(i * 80), A: 255})
+			}
+		}
+
+		require.NoError(t, encoder.Encode(img, 30))
+	}
+	require.NoError(t, encoder.Close())
+
+	decoded, err := gif.DecodeAll(buffer)
+	require.NoError(t, err)
+	require.Len(t, decoded.Image, 3)
+	assert.Equal(t, []int{3, 3, 3}, decoded.Delay)
+	assert.Equal(t, image.Rect(10, 20, 210, 200), decoded.Image[2].Bounds())
+}
+
+func TestDecode(t *testing.T) {
+	noop := func(image.Image, *pixiv_public_types.UgoiraFrame) error { return nil }
+
+	err := Decode(newTestZip(t, "000000.png"), nil, noop)
+	assert.ErrorIs(t, err, ErrNoFrames)
+
+	err = Decode(newTestZip(t, "000000.png"), []*pixiv_public_types.UgoiraFrame{{File: "000001.png"}}, noop)
+	assert.Error(t, err)
+
+	err = Decode([]byte("not a zip"), []*pixiv_public_types.UgoiraFrame{{File: "000000.png"}}, noop)
+	assert.Error(t, err)
+}
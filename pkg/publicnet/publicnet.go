@@ -0,0 +1,59 @@
+package publicnet
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrForbiddenAddress = errors.New("address is not allowed")
+
+	// cgnatPrefix 100.64.0.0/10 运营商级 NAT 地址同样不应从外部访问
+	cgnatPrefix = netip.MustParsePrefix("100.64.0.0/10")
+)
+
+// NewDialer 返回只能连接公网地址的 Dialer，域名解析后的地址在建立连接之前检查，
+// 用于访问由第三方决定的地址，以免访问到本机的 Bot API 服务器等内部服务
+func NewDialer() *net.Dialer {
+	return &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   DenyPrivateAddresses,
+	}
+}
+
+// DenyPrivateAddresses 在建立连接之前检查域名解析后的地址，拒绝内网、本机、链路本地和组播地址
+func DenyPrivateAddresses(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if IsPrivateAddress(addr) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, addr)
+	}
+
+	return nil
+}
+
+// IsPrivateAddress 判断 addr 是否为不应该由第三方链接访问的内网、本机、链路本地、组播或未指定地址
+func IsPrivateAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	return addr.IsPrivate() ||
+		addr.IsLoopback() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() ||
+		addr.IsUnspecified() ||
+		cgnatPrefix.Contains(addr)
+}
@@ -0,0 +1,33 @@
+package publicnet
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPrivateAddress(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, address := range []string{"127.0.0.1", "10.0.0.1", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1", "0.0.0.0", "::1", "fe80::1", "fd00::1", "::ffff:127.0.0.1"} {
+		assert.True(IsPrivateAddress(netip.MustParseAddr(address)), address)
+	}
+	for _, address := range []string{"93.184.216.34", "2606:4700::1111"} {
+		assert.False(IsPrivateAddress(netip.MustParseAddr(address)), address)
+	}
+}
+
+func TestNewDialer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	_, err = NewDialer().DialContext(context.Background(), "tcp", listener.Addr().String())
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrForbiddenAddress))
+}
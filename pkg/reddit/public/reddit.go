@@ -0,0 +1,123 @@
+package reddit_public
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/imroc/req/v3"
+	"github.com/sirupsen/logrus"
+
+	"github.com/nekomeowww/perobot/pkg/options"
+	reddit_public_types "github.com/nekomeowww/perobot/pkg/reddit/public/types"
+)
+
+const (
+	// DefaultBaseURL Reddit 的网站地址，在页面链接后加上 .json 即可获取 JSON 格式的数据
+	DefaultBaseURL = "https://www.reddit.com"
+)
+
+type ClientOptions struct {
+	Logger  *logrus.Entry
+	BaseURL string
+}
+
+func WithLogger(logger *logrus.Entry) options.CallOptions[ClientOptions] {
+	return options.NewCallOptions(func(o *ClientOptions) {
+		o.Logger = logger
+	})
+}
+
+// WithBaseURL 设定 Reddit 的网站地址
+func WithBaseURL(baseURL string) options.CallOptions[ClientOptions] {
+	return options.NewCallOptions(func(o *ClientOptions) {
+		o.BaseURL = baseURL
+	})
+}
+
+// Client 访问 Reddit 页面的 .json 接口，无需登录
+type Client struct {
+	reqClient *req.Client
+	logger    *logrus.Entry
+}
+
+func NewClient(callOpts ...options.CallOptions[ClientOptions]) (*Client, error) {
+	opts := options.ApplyCallOptions(callOpts, ClientOptions{
+		Logger:  logrus.NewEntry(logrus.New()),
+		BaseURL: DefaultBaseURL,
+	})
+
+	// Reddit 会限制使用浏览器和通用 User-Agent 的请求，需要带上能识别出客户端的 User-Agent
+	c := req.
+		C().
+		SetBaseURL(strings.TrimSuffix(opts.BaseURL, "/")).
+		SetUserAgent("perobot/1.0 (+https://github.com/nekomeowww/perobot)")
+
+	client := &Client{
+		reqClient: c,
+		logger:    opts.Logger,
+	}
+
+	return client, nil
+}
+
+// GetLink 返回帖子详情，帖子不存在时返回 nil
+func (c *Client) GetLink(id string) (*reddit_public_types.Link, error) {
+	var listings []*reddit_public_types.Listing
+
+	resp, err := c.reqClient.R().
+		// 不带 raw_json 时返回的链接中的 & 会被转义为 &amp;
+		SetQueryParam("raw_json", "1").
+		SetSuccessResult(&listings).
+		Get(fmt.Sprintf("/comments/%s.json", id))
+	if err != nil {
+		c.logger.Errorf("failed to get reddit link, err: %v", err)
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to get reddit link, status code: %d", resp.StatusCode)
+		return nil, fmt.Errorf("request to %s failed: status code: %d", resp.Request.URL, resp.StatusCode)
+	}
+
+	// 第一个列表为帖子本身，第二个列表为评论
+	if len(listings) == 0 || listings[0].Data == nil || len(listings[0].Data.Children) == 0 {
+		return nil, nil
+	}
+
+	thing := listings[0].Data.Children[0]
+	if thing.Kind != reddit_public_types.KindLink {
+		return nil, fmt.Errorf("unexpected reddit thing kind: %s", thing.Kind)
+	}
+
+	var link reddit_public_types.Link
+
+	err = json.Unmarshal(thing.Data, &link)
+	if err != nil {
+		c.logger.Errorf("failed to unmarshal reddit link, err: %v", err)
+		return nil, err
+	}
+
+	return &link, nil
+}
+
+// Download 下载 i.redd.it、preview.redd.it 上的图片或者 v.redd.it 上的视频，并以流的形式写入 w
+func (c *Client) Download(link string, w io.Writer) error {
+	resp, err := c.reqClient.R().
+		SetOutput(w).
+		Get(link)
+	if err != nil {
+		c.logger.Errorf("failed to fetch reddit media, err: %v", err)
+		return err
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to fetch reddit media, status code: %d", resp.StatusCode)
+		return fmt.Errorf("failed to fetch reddit media, status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
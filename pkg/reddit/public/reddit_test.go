@@ -0,0 +1,65 @@
+package reddit_public
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLink(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/comments/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1", r.URL.Query().Get("raw_json"))
+
+		switch r.URL.Path {
+		case "/comments/abc123.json":
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+			_, _ = w.Write([]byte(`[
+				{
+					"kind": "Listing",
+					"data": {
+						"children": [
+							{
+								"kind": "t3",
+								"data": {
+									"id": "abc123",
+									"title": "My OC",
+									"author": "alice",
+									"subreddit": "Art",
+									"permalink": "/r/Art/comments/abc123/my_oc/",
+									"url": "https://i.redd.it/xyz.png",
+									"post_hint": "image",
+									"over_18": true
+								}
+							}
+						]
+					}
+				},
+				{ "kind": "Listing", "data": { "children": [] } }
+			]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Not Found", "error": 404}`))
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient(WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	link, err := client.GetLink("abc123")
+	require.NoError(t, err)
+	require.NotNil(t, link)
+	assert.Equal(t, "My OC", link.Title)
+	assert.True(t, link.Over18)
+	assert.Equal(t, "https://www.reddit.com/r/Art/comments/abc123/my_oc/", link.PostURL())
+
+	link, err = client.GetLink("deleted")
+	require.NoError(t, err)
+	assert.Nil(t, link)
+}
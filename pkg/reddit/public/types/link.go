@@ -0,0 +1,155 @@
+package reddit_public_types
+
+import (
+	"fmt"
+)
+
+const (
+	PostHintImage       = "image"
+	PostHintHostedVideo = "hosted:video"
+
+	MediaMetadataTypeImage         = "Image"
+	MediaMetadataTypeAnimatedImage = "AnimatedImage"
+)
+
+// Link 帖子
+type Link struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Author    string `json:"author"`
+	Subreddit string `json:"subreddit"`
+	Permalink string `json:"permalink"`
+	// URL 图片帖子为图片链接，链接帖子为外部链接
+	URL      string `json:"url"`
+	PostHint string `json:"post_hint"`
+	// Over18 是否为 NSFW 帖子
+	Over18        bool                      `json:"over_18"`
+	IsGallery     bool                      `json:"is_gallery"`
+	GalleryData   *GalleryData              `json:"gallery_data,omitempty"`
+	MediaMetadata map[string]*MediaMetadata `json:"media_metadata,omitempty"`
+	SecureMedia   *Media                    `json:"secure_media,omitempty"`
+	Preview       *Preview                  `json:"preview,omitempty"`
+	// CrosspostParentList 转发帖子的原帖
+	CrosspostParentList []*Link `json:"crosspost_parent_list,omitempty"`
+}
+
+// GalleryData 相册中图片的顺序和说明
+type GalleryData struct {
+	Items []*GalleryItem `json:"items"`
+}
+
+type GalleryItem struct {
+	MediaID string `json:"media_id"`
+	Caption string `json:"caption"`
+}
+
+// MediaMetadata 相册中的图片
+type MediaMetadata struct {
+	Status string `json:"status"`
+	// E 图片的类型，Image 或 AnimatedImage
+	E string `json:"e"`
+	// M 图片的 MIME 类型
+	M string `json:"m"`
+	// S 最大尺寸的图片
+	S *MediaMetadataSource `json:"s,omitempty"`
+}
+
+type MediaMetadataSource struct {
+	// U 图片的链接，AnimatedImage 没有
+	U string `json:"u"`
+	X int    `json:"x"`
+	Y int    `json:"y"`
+	// GIF 和 MP4 AnimatedImage 的链接
+	GIF string `json:"gif"`
+	MP4 string `json:"mp4"`
+}
+
+type Media struct {
+	RedditVideo *RedditVideo `json:"reddit_video,omitempty"`
+}
+
+// RedditVideo 上传到 v.redd.it 的视频
+type RedditVideo struct {
+	// FallbackURL 没有音轨的 MP4 文件
+	FallbackURL string `json:"fallback_url"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Duration    int    `json:"duration"`
+	// IsGIF 是否为上传时转换为视频的 GIF
+	IsGIF bool `json:"is_gif"`
+}
+
+type Preview struct {
+	Images []*PreviewImage `json:"images"`
+}
+
+type PreviewImage struct {
+	Source   *PreviewSource   `json:"source,omitempty"`
+	Variants *PreviewVariants `json:"variants,omitempty"`
+}
+
+type PreviewSource struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+type PreviewVariants struct {
+	GIF *PreviewImage `json:"gif,omitempty"`
+	MP4 *PreviewImage `json:"mp4,omitempty"`
+}
+
+// PostURL 返回帖子的链接
+func (l *Link) PostURL() string {
+	return "https://www.reddit.com" + l.Permalink
+}
+
+// AuthorURL 返回作者的主页
+func (l *Link) AuthorURL() string {
+	return fmt.Sprintf("https://www.reddit.com/user/%s", l.Author)
+}
+
+// PreviewSource 返回帖子预览图中最大尺寸的图片，没有预览图时返回 nil
+func (l *Link) PreviewSource() *PreviewSource {
+	if l.Preview == nil || len(l.Preview.Images) == 0 {
+		return nil
+	}
+
+	return l.Preview.Images[0].Source
+}
+
+// PreviewMP4Source 返回 GIF 帖子转换为 MP4 的预览，没有时返回 nil
+func (l *Link) PreviewMP4Source() *PreviewSource {
+	if l.Preview == nil || len(l.Preview.Images) == 0 {
+		return nil
+	}
+
+	variants := l.Preview.Images[0].Variants
+	if variants == nil || variants.MP4 == nil {
+		return nil
+	}
+
+	return variants.MP4.Source
+}
+
+// IsValid 图片是否已经处理完成并且可以访问
+func (m *MediaMetadata) IsValid() bool {
+	return m.Status == "valid" && m.S != nil
+}
+
+// OriginalURL 返回 i.redd.it 上未经压缩的原图链接，S.U 是 preview.redd.it 上带有签名的链接
+func (m *MediaMetadata) OriginalURL(mediaID string) string {
+	var ext string
+	switch m.M {
+	case "image/png":
+		ext = ".png"
+	case "image/gif":
+		ext = ".gif"
+	case "image/webp":
+		ext = ".webp"
+	default:
+		ext = ".jpg"
+	}
+
+	return fmt.Sprintf("https://i.redd.it/%s%s", mediaID, ext)
+}
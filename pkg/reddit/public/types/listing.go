@@ -0,0 +1,25 @@
+package reddit_public_types
+
+import "encoding/json"
+
+// Listing Reddit 列表形式的返回值
+//
+// https://www.reddit.com/dev/api/#listings
+type Listing struct {
+	Kind string       `json:"kind"`
+	Data *ListingData `json:"data"`
+}
+
+type ListingData struct {
+	Children []*Thing `json:"children"`
+}
+
+// Thing 列表中的元素，帖子的 Kind 为 t3，评论为 t1
+type Thing struct {
+	Kind string          `json:"kind"`
+	Data json.RawMessage `json:"data"`
+}
+
+const (
+	KindLink = "t3"
+)
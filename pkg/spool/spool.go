@@ -0,0 +1,284 @@
+package spool
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/nekomeowww/perobot/pkg/options"
+)
+
+const (
+	// DefaultThreshold 默认超过 8 MiB 的数据写入临时文件
+	DefaultThreshold int64 = 8 * 1024 * 1024
+
+	filePattern = "perobot-spool-*"
+)
+
+var (
+	ErrClosed = errors.New("spooled file is closed")
+	// ErrTooLarge 写入的数据超过了 DownloadLimited 的大小限制
+	ErrTooLarge = errors.New("spooled data exceeds the size limit")
+)
+
+type StoreOptions struct {
+	Dir       string
+	Threshold int64
+}
+
+// WithDir 设定临时文件所在的目录，默认为系统的临时目录
+func WithDir(dir string) options.CallOptions[StoreOptions] {
+	return options.NewCallOptions(func(o *StoreOptions) {
+		o.Dir = dir
+	})
+}
+
+// WithThreshold 设定数据超过多少字节后写入临时文件
+func WithThreshold(threshold int64) options.CallOptions[StoreOptions] {
+	return options.NewCallOptions(func(o *StoreOptions) {
+		o.Threshold = threshold
+	})
+}
+
+// Store 暂存下载的媒体，较小的数据保存在内存中，超过阈值的数据写入临时文件
+type Store struct {
+	dir       string
+	threshold int64
+}
+
+// Open 创建 Store，并删除目录中上次运行时遗留的临时文件
+func Open(callOpts ...options.CallOptions[StoreOptions]) (*Store, error) {
+	opts := options.ApplyCallOptions(callOpts, StoreOptions{
+		Dir:       os.TempDir(),
+		Threshold: DefaultThreshold,
+	})
+
+	err := os.MkdirAll(opts.Dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	leftovers, err := filepath.Glob(filepath.Join(opts.Dir, filePattern))
+	if err != nil {
+		return nil, err
+	}
+	for _, leftover := range leftovers {
+		_ = os.Remove(leftover)
+	}
+
+	return &Store{
+		dir:       opts.Dir,
+		threshold: opts.Threshold,
+	}, nil
+}
+
+// Download 将 fn 写入的数据暂存为 File，fn 返回错误时丢弃已写入的数据
+func (s *Store) Download(fn func(w io.Writer) error) (*File, error) {
+	writer := s.NewWriter()
+
+	err := fn(writer)
+	if err != nil {
+		writer.Abort()
+		return nil, err
+	}
+
+	return writer.Finish()
+}
+
+// DownloadLimited 与 Download 相同，但 fn 写入的数据超过 limit 字节时写入会返回 ErrTooLarge，
+// 使下载在超出限制时立即中止，而不是下载完成之后才发现无法使用
+func (s *Store) DownloadLimited(limit int64, fn func(w io.Writer) error) (*File, error) {
+	writer := s.NewWriter()
+	writer.limit = limit
+
+	err := fn(writer)
+	if err != nil {
+		writer.Abort()
+		if writer.exceeded && !errors.Is(err, ErrTooLarge) {
+			return nil, fmt.Errorf("%w: %v", ErrTooLarge, err)
+		}
+
+		return nil, err
+	}
+
+	return writer.Finish()
+}
+
+// NewWriter 创建写入 Store 的 Writer，写入完成后需要调用 Finish 或 Abort
+func (s *Store) NewWriter() *Writer {
+	return &Writer{store: s}
+}
+
+// Writer 先写入内存，超过阈值后将已写入的数据和之后的数据写入临时文件
+type Writer struct {
+	store  *Store
+	buffer bytes.Buffer
+	file   *os.File
+	size   int64
+	// limit 大于 0 时允许写入的最大字节数
+	limit    int64
+	exceeded bool
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.limit > 0 && w.size+int64(len(p)) > w.limit {
+		w.exceeded = true
+		return 0, ErrTooLarge
+	}
+
+	if w.file == nil && int64(w.buffer.Len()+len(p)) > w.store.threshold {
+		file, err := os.CreateTemp(w.store.dir, filePattern)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create spool file: %w", err)
+		}
+
+		w.file = file
+
+		_, err = w.file.Write(w.buffer.Bytes())
+		if err != nil {
+			return 0, err
+		}
+
+		w.buffer = bytes.Buffer{}
+	}
+
+	var n int
+	var err error
+	if w.file != nil {
+		n, err = w.file.Write(p)
+	} else {
+		n, err = w.buffer.Write(p)
+	}
+
+	w.size += int64(n)
+	return n, err
+}
+
+// Finish 结束写入并返回暂存的 File
+func (w *Writer) Finish() (*File, error) {
+	if w.file == nil {
+		return FromBytes(w.buffer.Bytes()), nil
+	}
+
+	err := w.file.Close()
+	if err != nil {
+		_ = os.Remove(w.file.Name())
+		return nil, err
+	}
+
+	return &File{path: w.file.Name(), size: w.size}, nil
+}
+
+// Abort 丢弃已写入的数据
+func (w *Writer) Abort() {
+	w.buffer = bytes.Buffer{}
+	if w.file != nil {
+		_ = w.file.Close()
+		_ = os.Remove(w.file.Name())
+	}
+}
+
+// File 暂存的数据，使用完毕后需要调用 Close 释放内存或删除临时文件
+type File struct {
+	mutex  sync.Mutex
+	data   []byte
+	path   string
+	size   int64
+	closed bool
+	// onClose Close 时调用的函数
+	onClose []func()
+}
+
+// FromBytes 将内存中的数据包装为 File
+func FromBytes(data []byte) *File {
+	return &File{data: data, size: int64(len(data))}
+}
+
+// Size 返回数据的大小
+func (f *File) Size() int64 {
+	return f.size
+}
+
+// Path 返回临时文件的路径，数据保存在内存中时返回空字符串
+func (f *File) Path() string {
+	return f.path
+}
+
+// Bytes 返回全部数据，数据保存在临时文件中时会读入内存，仅用于需要解码的图片等较小的数据
+func (f *File) Bytes() ([]byte, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.closed {
+		return nil, ErrClosed
+	}
+	if f.path == "" {
+		return f.data, nil
+	}
+
+	return os.ReadFile(f.path)
+}
+
+// Open 打开数据以流式读取，读取完毕后需要关闭返回的 io.ReadCloser
+func (f *File) Open() (io.ReadCloser, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.closed {
+		return nil, ErrClosed
+	}
+	if f.path == "" {
+		return io.NopCloser(bytes.NewReader(f.data)), nil
+	}
+
+	return os.Open(f.path)
+}
+
+// OnClose 注册 Close 时调用的函数，已经关闭时立即调用
+func (f *File) OnClose(fn func()) {
+	f.mutex.Lock()
+	if !f.closed {
+		f.onClose = append(f.onClose, fn)
+		f.mutex.Unlock()
+
+		return
+	}
+
+	f.mutex.Unlock()
+	fn()
+}
+
+// Close 释放内存中的数据或删除临时文件，并调用 OnClose 注册的函数，可以重复调用，nil 的 File 不做任何处理
+func (f *File) Close() error {
+	if f == nil {
+		return nil
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.closed {
+		return nil
+	}
+
+	f.closed = true
+	f.data = nil
+	for _, fn := range f.onClose {
+		fn()
+	}
+	f.onClose = nil
+	if f.path == "" {
+		return nil
+	}
+
+	err := os.Remove(f.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
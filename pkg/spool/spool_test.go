@@ -0,0 +1,151 @@
+package spool
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := Open(WithDir(dir), WithThreshold(16))
+	require.NoError(t, err)
+
+	t.Run("InMemory", func(t *testing.T) {
+		file, err := store.Download(func(w io.Writer) error {
+			_, err := w.Write([]byte("small"))
+			return err
+		})
+		require.NoError(t, err)
+
+		assert.Empty(t, file.Path())
+		assert.Equal(t, int64(5), file.Size())
+
+		data, err := file.Bytes()
+		require.NoError(t, err)
+		assert.Equal(t, []byte("small"), data)
+
+		require.NoError(t, file.Close())
+		_, err = file.Bytes()
+		assert.ErrorIs(t, err, ErrClosed)
+	})
+
+	t.Run("Spooled", func(t *testing.T) {
+		content := bytes.Repeat([]byte("0123456789"), 10)
+
+		file, err := store.Download(func(w io.Writer) error {
+			for i := 0; i < 10; i++ {
+				_, err := w.Write(content[i*10 : (i+1)*10])
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		require.NotEmpty(t, file.Path())
+		assert.Equal(t, dir, filepath.Dir(file.Path()))
+		assert.Equal(t, int64(100), file.Size())
+
+		reader, err := file.Open()
+		require.NoError(t, err)
+
+		data, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.NoError(t, reader.Close())
+		assert.Equal(t, content, data)
+
+		require.NoError(t, file.Close())
+		require.NoError(t, file.Close())
+		assert.NoFileExists(t, file.Path())
+	})
+
+	t.Run("Abort", func(t *testing.T) {
+		_, err := store.Download(func(w io.Writer) error {
+			_, err := w.Write(bytes.Repeat([]byte("a"), 32))
+			require.NoError(t, err)
+
+			return errors.New("connection reset")
+		})
+		assert.Error(t, err)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("Limited", func(t *testing.T) {
+		written := 0
+
+		_, err := store.DownloadLimited(64, func(w io.Writer) error {
+			for i := 0; i < 10; i++ {
+				n, err := w.Write(bytes.Repeat([]byte("a"), 10))
+				written += n
+				if err != nil {
+					// 模拟 HTTP 客户端包装写入时的错误
+					return fmt.Errorf("failed to write response body: %s", err)
+				}
+			}
+
+			return nil
+		})
+		assert.ErrorIs(t, err, ErrTooLarge)
+		assert.Equal(t, 60, written)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+
+		file, err := store.DownloadLimited(64, func(w io.Writer) error {
+			_, err := w.Write(bytes.Repeat([]byte("a"), 64))
+			return err
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(64), file.Size())
+		require.NoError(t, file.Close())
+	})
+}
+
+func TestOpenRemovesLeftovers(t *testing.T) {
+	dir := t.TempDir()
+	leftover := filepath.Join(dir, "perobot-spool-123")
+	require.NoError(t, os.WriteFile(leftover, []byte("leftover"), 0644))
+
+	other := filepath.Join(dir, "other")
+	require.NoError(t, os.WriteFile(other, []byte("other"), 0644))
+
+	_, err := Open(WithDir(dir))
+	require.NoError(t, err)
+	assert.NoFileExists(t, leftover)
+	assert.FileExists(t, other)
+}
+
+func TestNilFileClose(t *testing.T) {
+	var file *File
+	assert.NoError(t, file.Close())
+}
+
+func TestFileOnClose(t *testing.T) {
+	file := FromBytes([]byte("data"))
+
+	closed := 0
+	file.OnClose(func() { closed++ })
+
+	require.NoError(t, file.Close())
+	require.NoError(t, file.Close())
+	assert.Equal(t, 1, closed)
+
+	// 已经关闭时立即调用
+	file.OnClose(func() { closed++ })
+	assert.Equal(t, 2, closed)
+}
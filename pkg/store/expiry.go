@@ -0,0 +1,78 @@
+package store
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+const (
+	// pruneInterval 两次自动清理过期值之间的最短间隔
+	pruneInterval = time.Hour
+)
+
+// Expiry 按照保留时间清理 bucket 中过期的值，供会持续增长的数据使用
+type Expiry struct {
+	store  *Store
+	bucket string
+	ttl    time.Duration
+	// expired 判断值是否在 before 之前写入，无法解析的值同样视为过期
+	expired func(raw json.RawMessage, before time.Time) bool
+
+	mutex    sync.Mutex
+	prunedAt time.Time
+}
+
+// NewExpiry 创建清理 bucket 中超过 ttl 的值的 Expiry，值解析为 T 后由 timeOf 返回其写入时间
+func NewExpiry[T any](s *Store, bucket string, ttl time.Duration, timeOf func(value T) time.Time) *Expiry {
+	return &Expiry{
+		store:  s,
+		bucket: bucket,
+		ttl:    ttl,
+		expired: func(raw json.RawMessage, before time.Time) bool {
+			var value T
+
+			err := json.Unmarshal(raw, &value)
+			if err != nil {
+				return true
+			}
+
+			return timeOf(value).Before(before)
+		},
+	}
+}
+
+// TTL 返回值的保留时间
+func (e *Expiry) TTL() time.Duration {
+	return e.ttl
+}
+
+// PruneIfDue 距离上次清理超过一小时时删除过期的值，第一次调用时也会清理，适合在每次写入之前调用
+func (e *Expiry) PruneIfDue() {
+	e.mutex.Lock()
+	if time.Since(e.prunedAt) < pruneInterval {
+		e.mutex.Unlock()
+		return
+	}
+
+	e.prunedAt = time.Now()
+	e.mutex.Unlock()
+
+	e.Prune(time.Now().Add(-e.ttl))
+}
+
+// Prune 删除 before 之前写入的值和无法解析的值，返回删除的数量
+func (e *Expiry) Prune(before time.Time) int {
+	deleted, err := e.store.DeleteFunc(e.bucket, func(_ string, raw json.RawMessage) bool {
+		return e.expired(raw, before)
+	})
+	if err != nil {
+		e.store.logger.Errorf("failed to prune expired entries from %s, err: %v", e.bucket, err)
+		return 0
+	}
+	if deleted > 0 {
+		e.store.logger.Infof("pruned %d expired entries from %s", deleted, e.bucket)
+	}
+
+	return deleted
+}
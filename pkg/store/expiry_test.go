@@ -0,0 +1,42 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type expiringEntry struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func TestExpiry(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "store.json"))
+	require.NoError(t, err)
+
+	defer s.Close()
+
+	expiry := NewExpiry(s, "bucket", 24*time.Hour, func(entry expiringEntry) time.Time { return entry.CreatedAt })
+	assert.Equal(t, 24*time.Hour, expiry.TTL())
+
+	require.NoError(t, s.Set("bucket", "expired", expiringEntry{CreatedAt: time.Now().Add(-25 * time.Hour)}))
+	require.NoError(t, s.Set("bucket", "invalid", "invalid"))
+	require.NoError(t, s.Set("bucket", "fresh", expiringEntry{CreatedAt: time.Now()}))
+	require.NoError(t, s.Set("other", "expired", expiringEntry{CreatedAt: time.Now().Add(-25 * time.Hour)}))
+
+	// 第一次调用时清理过期和无法解析的值，只影响所属的 bucket
+	expiry.PruneIfDue()
+	assert.Equal(t, []string{"fresh"}, s.Keys("bucket"))
+	assert.Equal(t, []string{"expired"}, s.Keys("other"))
+
+	// 距离上次清理不足间隔时不会清理
+	require.NoError(t, s.Set("bucket", "expired", expiringEntry{CreatedAt: time.Now().Add(-25 * time.Hour)}))
+	expiry.PruneIfDue()
+	assert.Equal(t, []string{"expired", "fresh"}, s.Keys("bucket"))
+
+	assert.Equal(t, 2, expiry.Prune(time.Now().Add(time.Minute)))
+	assert.Empty(t, s.Keys("bucket"))
+}
@@ -0,0 +1,288 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/nekomeowww/perobot/pkg/options"
+)
+
+const (
+	// DefaultFlushDelay 默认第一次修改之后等待多久写入文件，期间的修改合并为一次写入
+	DefaultFlushDelay = time.Second
+)
+
+type StoreOptions struct {
+	Logger *logrus.Entry
+	// FlushDelay 第一次修改之后等待多久写入文件，为 0 时每次修改都立即写入
+	FlushDelay time.Duration
+}
+
+func WithLogger(logger *logrus.Entry) options.CallOptions[StoreOptions] {
+	return options.NewCallOptions(func(o *StoreOptions) {
+		o.Logger = logger
+	})
+}
+
+// WithFlushDelay 设定第一次修改之后等待多久写入文件，为 0 时每次修改都立即写入
+func WithFlushDelay(delay time.Duration) options.CallOptions[StoreOptions] {
+	return options.NewCallOptions(func(o *StoreOptions) {
+		o.FlushDelay = delay
+	})
+}
+
+// Store 以 JSON 文件持久化的键值存储
+//
+// 数据按 bucket 分组保存在同一个文件中，写入时将全部数据写入临时文件并 fsync 后再重命名覆盖，
+// 以保证文件在写入途中崩溃或断电时不会损坏。写入的耗时与数据总量成正比，因此修改只会标记数据已变更，
+// 在第一次修改的 FlushDelay 之后合并为一次写入，崩溃时最多丢失这段时间内的修改，退出前需要调用 Close。
+// 只适用于数据量不大的场景，会持续增长的数据需要由使用者通过 DeleteFunc 定期清理。
+type Store struct {
+	path       string
+	logger     *logrus.Entry
+	flushDelay time.Duration
+
+	mutex   sync.RWMutex
+	buckets map[string]map[string]json.RawMessage
+	// dirty 数据已修改但尚未写入文件，由 mutex 保护
+	dirty bool
+	// flushTimer 等待写入文件的定时器，由 mutex 保护
+	flushTimer *time.Timer
+}
+
+// Open 打开 path 所指向的存储文件，文件不存在时会在第一次写入时创建
+func Open(path string, callOpts ...options.CallOptions[StoreOptions]) (*Store, error) {
+	opts := options.ApplyCallOptions(callOpts, StoreOptions{
+		Logger:     logrus.NewEntry(logrus.New()),
+		FlushDelay: DefaultFlushDelay,
+	})
+
+	s := &Store{
+		path:       path,
+		logger:     opts.Logger,
+		flushDelay: opts.FlushDelay,
+		buckets:    make(map[string]map[string]json.RawMessage),
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return s, nil
+		}
+
+		return nil, err
+	}
+	if len(content) == 0 {
+		return s, nil
+	}
+
+	err = json.Unmarshal(content, &s.buckets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse store file %s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// Get 读取 bucket 中 key 对应的值并解析到 value 中，返回值是否存在
+func (s *Store) Get(bucket string, key string, value any) (bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	raw, ok := s.buckets[bucket][key]
+	if !ok {
+		return false, nil
+	}
+
+	err := json.Unmarshal(raw, value)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Set 写入 bucket 中 key 对应的值
+func (s *Store) Set(bucket string, key string, value any) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.buckets[bucket] == nil {
+		s.buckets[bucket] = make(map[string]json.RawMessage)
+	}
+
+	s.buckets[bucket][key] = raw
+	return s.changed()
+}
+
+// Delete 删除 bucket 中 key 对应的值
+func (s *Store) Delete(bucket string, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.buckets[bucket][key]; !ok {
+		return nil
+	}
+
+	delete(s.buckets[bucket], key)
+	return s.changed()
+}
+
+// DeleteFunc 删除 bucket 中 fn 返回 true 的所有值，返回删除的数量
+func (s *Store) DeleteFunc(bucket string, fn func(key string, raw json.RawMessage) bool) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	deleted := 0
+	for key, raw := range s.buckets[bucket] {
+		if fn(key, raw) {
+			delete(s.buckets[bucket], key)
+			deleted++
+		}
+	}
+	if deleted == 0 {
+		return 0, nil
+	}
+
+	return deleted, s.changed()
+}
+
+// Keys 返回 bucket 中按字典序排列的所有 key
+func (s *Store) Keys(bucket string) []string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	keys := make([]string, 0, len(s.buckets[bucket]))
+	for key := range s.buckets[bucket] {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// Flush 立即将尚未写入的修改写入文件
+func (s *Store) Flush() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.flushTimer != nil {
+		s.flushTimer.Stop()
+		s.flushTimer = nil
+	}
+	if !s.dirty {
+		return nil
+	}
+
+	return s.flush()
+}
+
+// Close 将尚未写入的修改写入文件，之后的修改仍然会在 FlushDelay 之后写入
+func (s *Store) Close() error {
+	return s.Flush()
+}
+
+// changed 标记数据已修改，FlushDelay 为 0 时立即写入文件，否则在尚未安排写入时安排一次写入
+func (s *Store) changed() error {
+	s.dirty = true
+	if s.flushDelay <= 0 {
+		return s.flush()
+	}
+	if s.flushTimer == nil {
+		s.flushTimer = time.AfterFunc(s.flushDelay, s.flushLater)
+	}
+
+	return nil
+}
+
+// flushLater 由定时器调用，写入失败时保留修改并重新安排写入
+func (s *Store) flushLater() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.flushTimer = nil
+	if !s.dirty {
+		return
+	}
+
+	err := s.flush()
+	if err != nil {
+		s.logger.Errorf("failed to flush store %s, err: %v", s.path, err)
+		s.flushTimer = time.AfterFunc(s.flushDelay, s.flushLater)
+	}
+}
+
+// flush 将全部数据写入临时文件，写入磁盘后再重命名覆盖，断电时文件要么是旧的内容要么是新的内容
+func (s *Store) flush() error {
+	content, err := json.Marshal(s.buckets)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(s.path)
+
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := s.path + ".tmp"
+
+	err = writeFileSync(tmpPath, content)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tmpPath, s.path)
+	if err != nil {
+		return err
+	}
+
+	s.dirty = false
+
+	// 重命名本身也需要写入磁盘，部分文件系统不支持对目录调用 fsync，忽略其错误
+	dirFile, err := os.Open(dir)
+	if err != nil {
+		return nil
+	}
+
+	_ = dirFile.Sync()
+	_ = dirFile.Close()
+
+	return nil
+}
+
+// writeFileSync 写入文件并在关闭之前调用 fsync
+func writeFileSync(path string, content []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(content)
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	err = file.Sync()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
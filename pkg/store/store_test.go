@@ -0,0 +1,126 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "data", "store.json")
+
+	s, err := Open(path)
+	require.NoError(err)
+
+	var value string
+	ok, err := s.Get("bucket", "key", &value)
+	require.NoError(err)
+	assert.False(ok)
+
+	require.NoError(s.Set("bucket", "key", "value"))
+	require.NoError(s.Set("bucket", "another", "another value"))
+	require.NoError(s.Close())
+
+	reopened, err := Open(path)
+	require.NoError(err)
+
+	ok, err = reopened.Get("bucket", "key", &value)
+	require.NoError(err)
+	assert.True(ok)
+	assert.Equal("value", value)
+	assert.Equal([]string{"another", "key"}, reopened.Keys("bucket"))
+
+	require.NoError(reopened.Delete("bucket", "key"))
+	require.NoError(reopened.Delete("bucket", "missing"))
+	assert.Equal([]string{"another"}, reopened.Keys("bucket"))
+	assert.Empty(reopened.Keys("missing"))
+}
+
+func TestStoreDeleteFunc(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	s, err := Open(path)
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, s.Set("bucket", fmt.Sprintf("key-%d", i), i))
+	}
+
+	deleted, err := s.DeleteFunc("bucket", func(_ string, raw json.RawMessage) bool {
+		var value int
+		require.NoError(t, json.Unmarshal(raw, &value))
+
+		return value < 3
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, deleted)
+	require.NoError(t, s.Close())
+
+	reopened, err := Open(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key-3", "key-4"}, reopened.Keys("bucket"))
+
+	deleted, err = reopened.DeleteFunc("missing", func(string, json.RawMessage) bool { return true })
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+}
+
+func TestStoreFlush(t *testing.T) {
+	t.Run("Delayed", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "store.json")
+
+		s, err := Open(path, WithFlushDelay(20*time.Millisecond))
+		require.NoError(t, err)
+
+		defer s.Close()
+
+		// 修改合并为一次写入，第一次修改之后等待 FlushDelay 才写入文件
+		for i := 0; i < 5; i++ {
+			require.NoError(t, s.Set("bucket", fmt.Sprintf("key-%d", i), i))
+		}
+		assert.NoFileExists(t, path)
+
+		assert.Eventually(t, func() bool {
+			reopened, err := Open(path)
+			return err == nil && len(reopened.Keys("bucket")) == 5
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "store.json")
+
+		s, err := Open(path, WithFlushDelay(time.Hour))
+		require.NoError(t, err)
+
+		require.NoError(t, s.Set("bucket", "key", "value"))
+		assert.NoFileExists(t, path)
+
+		require.NoError(t, s.Close())
+
+		reopened, err := Open(path)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"key"}, reopened.Keys("bucket"))
+	})
+
+	t.Run("Immediate", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "store.json")
+
+		s, err := Open(path, WithFlushDelay(0))
+		require.NoError(t, err)
+
+		require.NoError(t, s.Set("bucket", "key", "value"))
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"bucket":{"key":"value"}}`, string(content))
+	})
+}
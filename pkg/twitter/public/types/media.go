@@ -1,5 +1,11 @@
 package twitter_public_types
 
+import (
+	"sort"
+
+	"github.com/samber/lo"
+)
+
 type EntityMediaSize struct {
 	H      int    `json:"h"`
 	W      int    `json:"w"`
@@ -79,8 +85,10 @@ type ExtendedEntityMedia struct {
 }
 
 type ExtendedEntityMediaVideoInfo struct {
-	AspectRatio []int                             `json:"aspect_ratio"`
-	Variants    []ExtendedEntityMediaVideoVariant `json:"variants"`
+	AspectRatio []int `json:"aspect_ratio"`
+	// DurationMillis 视频的时长，单位为毫秒，GIF 没有该字段
+	DurationMillis int                               `json:"duration_millis"`
+	Variants       []ExtendedEntityMediaVideoVariant `json:"variants"`
 }
 
 type ExtendedEntityMediaVideoVariant struct {
@@ -88,3 +96,21 @@ type ExtendedEntityMediaVideoVariant struct {
 	ContentType string `json:"content_type"`
 	URL         string `json:"url"`
 }
+
+const (
+	// VideoVariantContentTypeMP4 可以直接下载的 MP4 变体，另一种常见的变体为 HLS 播放列表 application/x-mpegURL
+	VideoVariantContentTypeMP4 = "video/mp4"
+)
+
+// MP4Variants 返回按照码率从高到低排列的 MP4 变体，不会修改 Variants 的顺序
+func (i *ExtendedEntityMediaVideoInfo) MP4Variants() []ExtendedEntityMediaVideoVariant {
+	variants := lo.Filter(i.Variants, func(variant ExtendedEntityMediaVideoVariant, _ int) bool {
+		return variant.ContentType == VideoVariantContentTypeMP4 && variant.URL != ""
+	})
+
+	sort.SliceStable(variants, func(i, j int) bool {
+		return variants[i].Bitrate > variants[j].Bitrate
+	})
+
+	return variants
+}
@@ -0,0 +1,28 @@
+package twitter_public_types
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMP4Variants(t *testing.T) {
+	videoInfo := &ExtendedEntityMediaVideoInfo{
+		Variants: []ExtendedEntityMediaVideoVariant{
+			{Bitrate: 832000, ContentType: "video/mp4", URL: "https://video.twimg.com/832.mp4"},
+			{Bitrate: 0, ContentType: "application/x-mpegURL", URL: "https://video.twimg.com/playlist.m3u8"},
+			{Bitrate: 2176000, ContentType: "video/mp4", URL: "https://video.twimg.com/2176.mp4"},
+			{Bitrate: 256000, ContentType: "video/mp4", URL: "https://video.twimg.com/256.mp4"},
+		},
+	}
+
+	variants := videoInfo.MP4Variants()
+	assert.Equal(t, []int{2176000, 832000, 256000}, lo.Map(variants, func(variant ExtendedEntityMediaVideoVariant, _ int) int {
+		return variant.Bitrate
+	}))
+	assert.Equal(t, "https://video.twimg.com/832.mp4", videoInfo.Variants[0].URL)
+
+	videoInfo.Variants = videoInfo.Variants[1:2]
+	assert.Empty(t, videoInfo.MP4Variants())
+}
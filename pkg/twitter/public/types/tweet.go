@@ -182,6 +182,15 @@ func (r *TweetResultsResult) DisplayTextWithURLsMappedEmbeddedInHTML() string {
 	return displayText
 }
 
+// PossiblySensitive 推文是否被标记为可能包含敏感内容
+func (r *TweetResultsResult) PossiblySensitive() bool {
+	if r.Legacy == nil {
+		return false
+	}
+
+	return r.Legacy.PossiblySensitive
+}
+
 func (r *TweetResultsResult) User() *UserResultsResultLegacy {
 	if r.Core == nil {
 		return nil
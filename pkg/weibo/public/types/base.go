@@ -0,0 +1,19 @@
+package weibo_public_types
+
+// StatusResp 移动版接口 /statuses/show 的返回值，微博不存在或无权查看时 OK 为 0
+type StatusResp struct {
+	OK   int     `json:"ok"`
+	Msg  string  `json:"msg"`
+	Data *Status `json:"data"`
+}
+
+// LongTextResp 移动版接口 /statuses/extend 的返回值
+type LongTextResp struct {
+	OK   int       `json:"ok"`
+	Data *LongText `json:"data"`
+}
+
+type LongText struct {
+	// LongTextContent 长微博的完整 HTML 正文
+	LongTextContent string `json:"longTextContent"`
+}
@@ -0,0 +1,70 @@
+package weibo_public_types
+
+import "github.com/samber/lo"
+
+const (
+	PageInfoTypeVideo = "video"
+)
+
+// PageInfo 微博附带的卡片，视频微博的视频信息也在卡片中
+type PageInfo struct {
+	Type      string     `json:"type"`
+	PagePic   *PagePic   `json:"page_pic,omitempty"`
+	MediaInfo *MediaInfo `json:"media_info,omitempty"`
+	// URLs 各个清晰度的视频链接，键为 mp4_1080p_mp4、mp4_720p_mp4、mp4_hd_mp4、mp4_ld_mp4 等
+	URLs map[string]string `json:"urls,omitempty"`
+}
+
+type PagePic struct {
+	URL string `json:"url"`
+}
+
+type MediaInfo struct {
+	StreamURLHD string `json:"stream_url_hd"`
+	StreamURL   string `json:"stream_url"`
+	MP4720pMP4  string `json:"mp4_720p_mp4"`
+	MP4HDURL    string `json:"mp4_hd_url"`
+	MP4SDURL    string `json:"mp4_sd_url"`
+	// Duration 视频时长，单位为秒
+	Duration float64 `json:"duration"`
+}
+
+// IsVideo 卡片是否为视频
+func (p *PageInfo) IsVideo() bool {
+	return p != nil && p.Type == PageInfoTypeVideo
+}
+
+// VideoURLs 返回按照清晰度从高到低排列的视频链接
+func (p *PageInfo) VideoURLs() []string {
+	if p == nil {
+		return []string{}
+	}
+
+	urls := make([]string, 0)
+	for _, key := range []string{"mp4_1080p_mp4", "mp4_720p_mp4", "mp4_hd_mp4", "mp4_ld_mp4"} {
+		urls = append(urls, p.URLs[key])
+	}
+	if p.MediaInfo != nil {
+		urls = append(urls, p.MediaInfo.MP4720pMP4, p.MediaInfo.MP4HDURL, p.MediaInfo.StreamURLHD, p.MediaInfo.MP4SDURL, p.MediaInfo.StreamURL)
+	}
+
+	return lo.Uniq(lo.Compact(urls))
+}
+
+// PosterURL 返回视频的封面图
+func (p *PageInfo) PosterURL() string {
+	if p == nil || p.PagePic == nil {
+		return ""
+	}
+
+	return p.PagePic.URL
+}
+
+// Duration 返回视频的时长，单位为秒
+func (p *PageInfo) Duration() int {
+	if p == nil || p.MediaInfo == nil {
+		return 0
+	}
+
+	return int(p.MediaInfo.Duration)
+}
@@ -0,0 +1,74 @@
+package weibo_public_types
+
+import (
+	"encoding/json"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Pic 微博中的图片
+type Pic struct {
+	PID string `json:"pid"`
+	// URL 缩略图的链接
+	URL string `json:"url"`
+	// Large 原图
+	Large *PicSize `json:"large,omitempty"`
+	// Type 实况照片为 livephoto，动图为 gifvideos，普通图片为空
+	Type string `json:"type"`
+}
+
+type PicSize struct {
+	URL string  `json:"url"`
+	Geo *PicGeo `json:"geo,omitempty"`
+}
+
+// PicGeo 图片的宽高，接口返回的值可能是数字也可能是字符串
+type PicGeo struct {
+	Width  json.Number `json:"width"`
+	Height json.Number `json:"height"`
+}
+
+// LargeURL 返回原图的链接，没有原图信息时将缩略图链接中的尺寸替换为 large
+func (p *Pic) LargeURL() string {
+	if p.Large != nil && p.Large.URL != "" {
+		return p.Large.URL
+	}
+
+	return ResizedURL(p.URL, "large")
+}
+
+// Size 返回原图的宽高，未知时为 0
+func (p *Pic) Size() (int, int) {
+	if p.Large == nil || p.Large.Geo == nil {
+		return 0, 0
+	}
+
+	width, _ := p.Large.Geo.Width.Int64()
+	height, _ := p.Large.Geo.Height.Int64()
+
+	return int(width), int(height)
+}
+
+// IsGIF 图片是否为 GIF 动图
+func (p *Pic) IsGIF() bool {
+	return strings.EqualFold(path.Ext(p.LargeURL()), ".gif")
+}
+
+// ResizedURL 将新浪图床链接 https://<host>/<尺寸>/<pid>.<ext> 中的尺寸替换为 size，
+// 常用的尺寸有 large（原图）、mw2000（最长边 2000）和 orj360（缩略图）
+func ResizedURL(link string, size string) string {
+	parsedURL, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(parsedURL.Path, "/"), "/", 2)
+	if len(parts) != 2 {
+		return link
+	}
+
+	parsedURL.Path = "/" + size + "/" + parts[1]
+
+	return parsedURL.String()
+}
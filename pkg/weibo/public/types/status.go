@@ -0,0 +1,92 @@
+package weibo_public_types
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Status 移动版接口返回的微博
+type Status struct {
+	// ID 微博的数字 ID（mid）
+	ID string `json:"id"`
+	// BID 微博链接中使用的 base62 形式的 ID
+	BID  string `json:"bid"`
+	Text string `json:"text"`
+	// IsLongText 为 true 时 Text 只包含正文的开头，需要通过 /statuses/extend 获取完整正文
+	IsLongText      bool      `json:"isLongText"`
+	User            *User     `json:"user,omitempty"`
+	Pics            []*Pic    `json:"pics,omitempty"`
+	PageInfo        *PageInfo `json:"page_info,omitempty"`
+	RetweetedStatus *Status   `json:"retweeted_status,omitempty"`
+}
+
+// HasMedia 微博本身是否带有图片或视频
+func (s *Status) HasMedia() bool {
+	return len(s.Pics) > 0 || s.PageInfo.IsVideo()
+}
+
+// URL 返回微博在网页版上的链接
+func (s *Status) URL() string {
+	if s.User == nil || s.BID == "" {
+		return fmt.Sprintf("https://m.weibo.cn/detail/%s", s.ID)
+	}
+
+	return fmt.Sprintf("https://weibo.com/%d/%s", s.User.ID, s.BID)
+}
+
+// TextInHTML 将微博的 HTML 正文转换为 Telegram 支持的 HTML，表情转换为表情的名称，
+// 提及的用户和话题保留原文，其他链接保留为链接，展开全文的链接会被去掉
+func (s *Status) TextInHTML() string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(s.Text))
+	if err != nil {
+		return html.EscapeString(s.Text)
+	}
+
+	var sb strings.Builder
+	writeText(&sb, doc.Find("body"))
+
+	return strings.TrimSpace(sb.String())
+}
+
+func writeText(sb *strings.Builder, selection *goquery.Selection) {
+	selection.Contents().Each(func(_ int, child *goquery.Selection) {
+		switch goquery.NodeName(child) {
+		case "#text":
+			sb.WriteString(html.EscapeString(child.Text()))
+		case "br":
+			sb.WriteString("\n")
+		case "img":
+			alt, _ := child.Attr("alt")
+			sb.WriteString(html.EscapeString(alt))
+		case "a":
+			writeTextLink(sb, child)
+		default:
+			writeText(sb, child)
+		}
+	})
+}
+
+func writeTextLink(sb *strings.Builder, selection *goquery.Selection) {
+	var textBuilder strings.Builder
+	writeText(&textBuilder, selection)
+
+	// 普通链接的文字为图标加上「网页链接」，图标没有 alt，转换后只剩下文字
+	text := strings.TrimSpace(textBuilder.String())
+	if text == "全文" {
+		return
+	}
+
+	href, _ := selection.Attr("href")
+	if href == "" || strings.HasPrefix(text, "@") || strings.HasPrefix(text, "#") {
+		sb.WriteString(text)
+		return
+	}
+	if strings.HasPrefix(href, "/") {
+		href = "https://m.weibo.cn" + href
+	}
+
+	sb.WriteString(fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(href), text))
+}
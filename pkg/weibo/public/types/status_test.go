@@ -0,0 +1,53 @@
+package weibo_public_types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextInHTML(t *testing.T) {
+	status := &Status{
+		Text: `今天画的 <a href="https://m.weibo.cn/search?containerid=231522type%3D1%26q%3D%23%E5%8E%9F%E5%88%9B%23"><span class="surl-text">#原创#</span></a>` +
+			`<span class="url-icon"><img alt="[哈哈]" src="https://face.t.sinajs.cn/t4/appstyle/expression/ext/normal/8f/2018new_haha_thumb.png" /></span><br />` +
+			`感谢 <a href="/n/某人">@某人</a> 的约稿 &lt;3 ` +
+			`<a href="https://weibo.cn/sinaurl?u=https%3A%2F%2Fexample.com"><span class="url-icon"><img src="https://h5.sinaimg.cn/upload/2015/09/25/3/timeline_card_small_web_default.png" /></span><span class="surl-text">网页链接</span></a>` +
+			` ...<a href="/status/5012345678901234">全文</a>`,
+	}
+
+	assert.Equal(t, ""+
+		"今天画的 #原创#[哈哈]\n"+
+		"感谢 @某人 的约稿 &lt;3 "+
+		`<a href="https://weibo.cn/sinaurl?u=https%3A%2F%2Fexample.com">网页链接</a> ...`,
+		status.TextInHTML())
+}
+
+func TestPic(t *testing.T) {
+	assert := assert.New(t)
+
+	pic := &Pic{PID: "006abc", URL: "https://wx1.sinaimg.cn/orj360/006abc.gif"}
+	assert.Equal("https://wx1.sinaimg.cn/large/006abc.gif", pic.LargeURL())
+	assert.True(pic.IsGIF())
+	assert.Equal("https://wx1.sinaimg.cn/mw2000/006abc.gif", ResizedURL(pic.LargeURL(), "mw2000"))
+}
+
+func TestVideoURLs(t *testing.T) {
+	pageInfo := &PageInfo{
+		Type: PageInfoTypeVideo,
+		URLs: map[string]string{
+			"mp4_720p_mp4": "https://f.video.weibocdn.com/720.mp4",
+			"mp4_ld_mp4":   "https://f.video.weibocdn.com/ld.mp4",
+		},
+		MediaInfo: &MediaInfo{
+			MP4720pMP4: "https://f.video.weibocdn.com/720.mp4",
+			StreamURL:  "https://f.video.weibocdn.com/stream.mp4",
+		},
+	}
+
+	assert.True(t, pageInfo.IsVideo())
+	assert.Equal(t, []string{
+		"https://f.video.weibocdn.com/720.mp4",
+		"https://f.video.weibocdn.com/ld.mp4",
+		"https://f.video.weibocdn.com/stream.mp4",
+	}, pageInfo.VideoURLs())
+}
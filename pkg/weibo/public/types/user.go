@@ -0,0 +1,13 @@
+package weibo_public_types
+
+import "fmt"
+
+type User struct {
+	ID         int64  `json:"id"`
+	ScreenName string `json:"screen_name"`
+}
+
+// ProfileURL 返回用户在网页版上的主页
+func (u *User) ProfileURL() string {
+	return fmt.Sprintf("https://weibo.com/u/%d", u.ID)
+}
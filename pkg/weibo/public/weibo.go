@@ -0,0 +1,170 @@
+package weibo_public
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/imroc/req/v3"
+	"github.com/sirupsen/logrus"
+
+	"github.com/nekomeowww/perobot/pkg/options"
+	weibo_public_types "github.com/nekomeowww/perobot/pkg/weibo/public/types"
+)
+
+const (
+	// DefaultBaseURL 移动版微博，无需登录即可访问公开的微博
+	DefaultBaseURL = "https://m.weibo.cn"
+	// ImageReferer 新浪图床会拒绝没有微博 Referer 的请求
+	ImageReferer = "https://weibo.com/"
+)
+
+type ClientOptions struct {
+	Logger  *logrus.Entry
+	BaseURL string
+}
+
+func WithLogger(logger *logrus.Entry) options.CallOptions[ClientOptions] {
+	return options.NewCallOptions(func(o *ClientOptions) {
+		o.Logger = logger
+	})
+}
+
+// WithBaseURL 设定移动版微博接口的地址
+func WithBaseURL(baseURL string) options.CallOptions[ClientOptions] {
+	return options.NewCallOptions(func(o *ClientOptions) {
+		o.BaseURL = baseURL
+	})
+}
+
+// Client 访问移动版微博的公开接口
+type Client struct {
+	reqClient *req.Client
+	logger    *logrus.Entry
+}
+
+func NewClient(callOpts ...options.CallOptions[ClientOptions]) (*Client, error) {
+	opts := options.ApplyCallOptions(callOpts, ClientOptions{
+		Logger:  logrus.NewEntry(logrus.New()),
+		BaseURL: DefaultBaseURL,
+	})
+
+	c := req.
+		C().
+		SetBaseURL(strings.TrimSuffix(opts.BaseURL, "/")).
+		SetCommonHeader("Accept", "application/json, text/plain, */*").
+		SetCommonHeader("MWeibo-Pwa", "1").
+		SetCommonHeader("X-Requested-With", "XMLHttpRequest").
+		SetCommonHeader("Referer", "https://m.weibo.cn/").
+		SetUserAgent("Mozilla/5.0 (iPhone; CPU iPhone OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.6 Mobile/15E148 Safari/604.1")
+
+	client := &Client{
+		reqClient: c,
+		logger:    opts.Logger,
+	}
+
+	return client, nil
+}
+
+// GetStatus 返回微博详情，id 可以是数字 ID 或者 base62 形式的 ID，微博不存在、被删除或无权查看时返回 nil
+func (c *Client) GetStatus(id string) (*weibo_public_types.Status, error) {
+	var statusResp weibo_public_types.StatusResp
+
+	resp, err := c.reqClient.R().
+		SetQueryParam("id", id).
+		SetSuccessResult(&statusResp).
+		Get("/statuses/show")
+	if err != nil {
+		c.logger.Errorf("failed to get weibo status, err: %v", err)
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to get weibo status, status code: %d", resp.StatusCode)
+		return nil, fmt.Errorf("request to %s failed: status code: %d", resp.Request.URL, resp.StatusCode)
+	}
+	if statusResp.OK != 1 || statusResp.Data == nil {
+		c.logger.Warnf("weibo status %s is unavailable, msg: %s", id, statusResp.Msg)
+		return nil, nil
+	}
+
+	return statusResp.Data, nil
+}
+
+// GetLongText 返回长微博的完整 HTML 正文
+func (c *Client) GetLongText(id string) (string, error) {
+	var longTextResp weibo_public_types.LongTextResp
+
+	resp, err := c.reqClient.R().
+		SetQueryParam("id", id).
+		SetSuccessResult(&longTextResp).
+		Get("/statuses/extend")
+	if err != nil {
+		c.logger.Errorf("failed to get weibo long text, err: %v", err)
+		return "", err
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to get weibo long text, status code: %d", resp.StatusCode)
+		return "", fmt.Errorf("request to %s failed: status code: %d", resp.Request.URL, resp.StatusCode)
+	}
+	if longTextResp.OK != 1 || longTextResp.Data == nil {
+		return "", fmt.Errorf("request to %s failed: ok: %d", resp.Request.URL, longTextResp.OK)
+	}
+
+	return longTextResp.Data.LongTextContent, nil
+}
+
+// Download 下载新浪图床上的图片或者微博视频，并以流的形式写入 w
+func (c *Client) Download(link string, w io.Writer) error {
+	resp, err := c.reqClient.R().
+		SetHeader("Referer", ImageReferer).
+		SetOutput(w).
+		Get(link)
+	if err != nil {
+		c.logger.Errorf("failed to fetch weibo media, err: %v", err)
+		return err
+	}
+	if !resp.IsSuccess() {
+		c.logger.Errorf("failed to fetch weibo media, status code: %d", resp.StatusCode)
+		return fmt.Errorf("failed to fetch weibo media, status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+const base62Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// BIDToMID 将微博链接中 base62 形式的 ID 转换为数字 ID，
+// 从右往左每 4 位 base62 字符对应 7 位十进制数字，最左边一组不补零
+func BIDToMID(bid string) (string, error) {
+	if bid == "" {
+		return "", fmt.Errorf("invalid weibo bid: %s", bid)
+	}
+
+	var mid string
+	for end := len(bid); end > 0; end -= 4 {
+		start := end - 4
+		if start < 0 {
+			start = 0
+		}
+
+		var value int64
+		for _, r := range bid[start:end] {
+			index := strings.IndexRune(base62Alphabet, r)
+			if index < 0 {
+				return "", fmt.Errorf("invalid weibo bid: %s", bid)
+			}
+
+			value = value*62 + int64(index)
+		}
+
+		chunk := strconv.FormatInt(value, 10)
+		if start > 0 && len(chunk) < 7 {
+			chunk = strings.Repeat("0", 7-len(chunk)) + chunk
+		}
+
+		mid = chunk + mid
+	}
+
+	return mid, nil
+}
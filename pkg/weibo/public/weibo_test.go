@@ -0,0 +1,94 @@
+package weibo_public
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStatus(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/statuses/show", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "XMLHttpRequest", r.Header.Get("X-Requested-With"))
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		switch r.URL.Query().Get("id") {
+		case "5012345678901234":
+			_, _ = w.Write([]byte(`{
+				"ok": 1,
+				"data": {
+					"id": "5012345678901234",
+					"bid": "Nabcdefgh",
+					"text": "新图<br />",
+					"isLongText": true,
+					"user": { "id": 1234567890, "screen_name": "画师" },
+					"pics": [
+						{
+							"pid": "006abc",
+							"url": "https://wx1.sinaimg.cn/orj360/006abc.jpg",
+							"large": { "url": "https://wx1.sinaimg.cn/large/006abc.jpg", "geo": { "width": "2048", "height": 1536 } }
+						}
+					]
+				}
+			}`))
+		default:
+			_, _ = w.Write([]byte(`{"ok":0,"msg":"暂无查看权限"}`))
+		}
+	})
+	mux.HandleFunc("/statuses/extend", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_, _ = w.Write([]byte(`{"ok":1,"data":{"longTextContent":"新图，完整的正文"}}`))
+	})
+	mux.HandleFunc("/large/006abc.jpg", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Referer") != ImageReferer {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		_, _ = w.Write([]byte("image"))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient(WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	status, err := client.GetStatus("5012345678901234")
+	require.NoError(t, err)
+	require.NotNil(t, status)
+	assert.Equal(t, "https://weibo.com/1234567890/Nabcdefgh", status.URL())
+	assert.True(t, status.IsLongText)
+	require.Len(t, status.Pics, 1)
+
+	width, height := status.Pics[0].Size()
+	assert.Equal(t, 2048, width)
+	assert.Equal(t, 1536, height)
+
+	longText, err := client.GetLongText("5012345678901234")
+	require.NoError(t, err)
+	assert.Equal(t, "新图，完整的正文", longText)
+
+	status, err = client.GetStatus("5000000000000000")
+	require.NoError(t, err)
+	assert.Nil(t, status)
+
+	buffer := new(bytes.Buffer)
+	err = client.Download(server.URL+"/large/006abc.jpg", buffer)
+	require.NoError(t, err)
+	assert.Equal(t, "image", buffer.String())
+}
+
+func TestBIDToMID(t *testing.T) {
+	mid, err := BIDToMID("z0JH2lOMb")
+	require.NoError(t, err)
+	assert.Equal(t, "3501756485200075", mid)
+
+	_, err = BIDToMID("z0JH-lOMb")
+	assert.Error(t, err)
+}